@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	_ "ospm/docs/api"
 	"ospm/utils"
 )
@@ -10,6 +12,15 @@ import (
 // @description This document covers the API endpoints exposed by Owl MNS Subscriber Profile Manager.
 // @contact.name Mahmoud Ahmadi
 // @contact.email ma.ahmadi1989@gmail.com
+// @securityDefinitions.apikey APIKey
+// @in header
+// @name X-API-Key
+// @description Operator API key. Every endpoint except /apidoc and /auth needs a key with the scope of the request
 func main() {
+	// ospm migrate manages the schema migrations of the database without starting the servers
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(utils.RunMigrate(os.Args[2:]))
+	}
+
 	utils.StartOSPM()
 }
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+	"ospm/internal/ospmctl"
+)
+
+// ospmctl is the administrative command-line tool of OSPM, run ospmctl help to see its commands
+func main() {
+	os.Exit(ospmctl.Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
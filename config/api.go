@@ -3,14 +3,31 @@ package config
 import (
 	"fmt"
 	"os"
+	"ospm/internal/service/clientip"
+	"ospm/internal/service/complementary"
+	"strings"
 )
 
+// ClientIPHeaders are the forwarding headers that the client IP can be taken from
+var ClientIPHeaders = []string{clientip.HeaderForwarded, clientip.HeaderXForwardedFor, clientip.HeaderXRealIP}
+
 type APISetting struct {
 	Port          string
 	ListenAddress string
 	AllowOrigins  string
 	AllowMethods  string
 	AllowHeaders  string
+
+	// TrustedProxies are the CIDRs of the proxies and the load balancers in front of OSPM.
+	// The client IP is only taken from the forwarding headers of the requests they send
+	TrustedProxies []string
+
+	// ClientIPHeader is the only forwarding header that is read from the trusted proxies. It should be the one
+	// that the proxy sets, the other ones are passed through by the proxy as the client sends them
+	ClientIPHeader string
+
+	trustedProxySet     *complementary.IPSet
+	trustedProxiesError error
 }
 
 func (a *APISetting) GetListenAddress() string {
@@ -45,5 +62,41 @@ func LoadAPISettings() *APISetting {
 		loadedConfigs.AllowHeaders = "*"
 	}
 
+	if trustedProxies := os.Getenv("OSPM_API_TRUSTED_PROXIES"); trustedProxies != "" {
+		for _, proxy := range strings.Split(trustedProxies, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				loadedConfigs.TrustedProxies = append(loadedConfigs.TrustedProxies, proxy)
+			}
+		}
+	}
+	loadedConfigs.trustedProxySet, loadedConfigs.trustedProxiesError = complementary.ParseIPSet(loadedConfigs.TrustedProxies)
+
+	loadedConfigs.ClientIPHeader = strings.TrimSpace(os.Getenv("OSPM_API_CLIENT_IP_HEADER"))
+	if loadedConfigs.ClientIPHeader == "" {
+		loadedConfigs.ClientIPHeader = clientip.HeaderXForwardedFor
+	}
+	for _, header := range ClientIPHeaders {
+		if strings.EqualFold(loadedConfigs.ClientIPHeader, header) {
+			loadedConfigs.ClientIPHeader = header
+		}
+	}
+
 	return loadedConfigs
 }
+
+// TrustedProxySet returns the parsed CIDRs of the trusted proxies
+func (a *APISetting) TrustedProxySet() *complementary.IPSet {
+	return a.trustedProxySet
+}
+
+func (a *APISetting) Validate() error {
+	if a.trustedProxiesError != nil {
+		return fmt.Errorf("OSPM_API_TRUSTED_PROXIES should be a comma separated list of CIDRs or IP addresses, %s", a.trustedProxiesError)
+	}
+	for _, header := range ClientIPHeaders {
+		if a.ClientIPHeader == header {
+			return nil
+		}
+	}
+	return fmt.Errorf("OSPM_API_CLIENT_IP_HEADER should be one of %s, given value is: %s", strings.Join(ClientIPHeaders, ", "), a.ClientIPHeader)
+}
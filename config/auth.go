@@ -0,0 +1,66 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+type AuthSetting struct {
+	TokenSigningKey string `json:"-"`
+	TokenIssuer     string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	APIKeyRequired  bool
+
+	generatedSigningKey bool
+}
+
+func LoadAuthSettings() *AuthSetting {
+	loadedConfigs := &AuthSetting{}
+
+	loadedConfigs.TokenSigningKey = os.Getenv("OSPM_AUTH_TOKEN_SIGNING_KEY")
+	if loadedConfigs.TokenSigningKey == "" {
+		// a random key keeps the service usable but the issued tokens
+		// will be invalidated after each restart
+		randomKey := make([]byte, 32)
+		if _, err := rand.Read(randomKey); err != nil {
+			log.Fatalf("failed to generate a random token signing key, error: %s", err)
+		}
+		loadedConfigs.TokenSigningKey = hex.EncodeToString(randomKey)
+		loadedConfigs.generatedSigningKey = true
+	}
+
+	loadedConfigs.TokenIssuer = os.Getenv("OSPM_AUTH_TOKEN_ISSUER")
+	if loadedConfigs.TokenIssuer == "" {
+		loadedConfigs.TokenIssuer = "ospm"
+	}
+
+	loadedConfigs.AccessTokenTTL = loadDuration("OSPM_AUTH_ACCESS_TOKEN_TTL", 15*time.Minute)
+	loadedConfigs.RefreshTokenTTL = loadDuration("OSPM_AUTH_REFRESH_TOKEN_TTL", 30*24*time.Hour)
+
+	// the operator API keys can only be disabled explicitly
+	loadedConfigs.APIKeyRequired = strings.ToLower(os.Getenv("OSPM_AUTH_API_KEY_REQUIRED")) != "false"
+
+	return loadedConfigs
+}
+
+// loadDuration reads a duration like 15m or 720h from the given environment variable.
+// Empty or invalid values are replaced by the given default value
+func loadDuration(envName string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(envName)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("invalid value %q for %s, default value %s will be used", value, envName, defaultValue)
+		return defaultValue
+	}
+
+	return duration
+}
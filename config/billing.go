@@ -0,0 +1,33 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+type BillingSetting struct {
+	DefaultCurrency string
+}
+
+// currencyCodePattern matches the ISO-4217 alphabetic codes
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func LoadBillingSettings() *BillingSetting {
+	loadedConfigs := &BillingSetting{}
+
+	loadedConfigs.DefaultCurrency = strings.ToUpper(os.Getenv("OSPM_BILLING_DEFAULT_CURRENCY"))
+	if loadedConfigs.DefaultCurrency == "" {
+		loadedConfigs.DefaultCurrency = "IRR"
+	}
+
+	return loadedConfigs
+}
+
+func (b *BillingSetting) Validate() error {
+	if !currencyCodePattern.MatchString(b.DefaultCurrency) {
+		return fmt.Errorf("OSPM_BILLING_DEFAULT_CURRENCY should be an ISO-4217 currency code like IRR or USD, given value is: %s", b.DefaultCurrency)
+	}
+	return nil
+}
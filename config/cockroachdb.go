@@ -16,8 +16,21 @@ type CockRoachDBConfig struct {
 	ClientKeyPath  string
 	ClientCertPath string
 	CACertPath     string
+	Migrations     string
 }
 
+const (
+	// MigrationsAuto applies the pending schema migrations on startup
+	MigrationsAuto = "auto"
+
+	// MigrationsCheck refuses to start when there are pending schema migrations,
+	// so the migrations are only applied by ospm migrate up
+	MigrationsCheck = "check"
+
+	// MigrationsOff neither applies nor checks the schema migrations on startup
+	MigrationsOff = "off"
+)
+
 func LoadCockroachDBConfigs() *CockRoachDBConfig {
 	loadedConfig := &CockRoachDBConfig{}
 
@@ -64,9 +77,22 @@ func LoadCockroachDBConfigs() *CockRoachDBConfig {
 		loadedConfig.CACertPath = "/etc/roachCerts/ca.crt"
 	}
 
+	loadedConfig.Migrations = os.Getenv("OSPM_COCKROACHDB_MIGRATIONS")
+	if loadedConfig.Migrations == "" {
+		loadedConfig.Migrations = MigrationsAuto
+	}
+
 	return loadedConfig
 }
 
+func (roach *CockRoachDBConfig) Validate() error {
+	switch roach.Migrations {
+	case MigrationsAuto, MigrationsCheck, MigrationsOff:
+		return nil
+	}
+	return fmt.Errorf("OSPM_COCKROACHDB_MIGRATIONS should be one of auto/check/off, given value is: %s", roach.Migrations)
+}
+
 func (roach *CockRoachDBConfig) DSN() string {
 	if roach.SSLMode == "disabled" {
 		return fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable",
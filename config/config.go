@@ -1,40 +1,177 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/joho/godotenv"
 )
 
 type OSPMConfig struct {
-	API            *APISetting
-	Logrus         *LogrusConfig
-	RDMS           *CockRoachDBConfig
-	ClientPolicies *ClientPolicy
+	API        *APISetting
+	Logrus     *LogrusConfig
+	RDMS       *CockRoachDBConfig
+	Policy     *PolicySetting
+	Auth       *AuthSetting
+	Radius     *RadiusSetting
+	Billing    *BillingSetting
+	Permission *PermissionSetting
 }
 
-var OSPM *OSPMConfig
+// current keeps the config in use. It is replaced as a whole on reload,
+// so the readers either see the old or the new config and never a mix of them
+var current atomic.Pointer[OSPMConfig]
+
+var (
+	// configFilePath is resolved once on startup and is used by the reloads
+	configFilePath string
+
+	// processEnvironments are the variables that are set before the config file is loaded.
+	// They have priority over the config file and are not changed by the reloads
+	processEnvironments map[string]bool
+
+	// fileEnvironments are the variables that are set from the config file,
+	// so the ones removed from the file can be unset on reload
+	fileEnvironments = map[string]bool{}
+
+	environmentsLock sync.Mutex
+)
+
+// Current returns the config in use
+func Current() *OSPMConfig {
+	return current.Load()
+}
 
 func LoadLocalEnvironments() {
+	environmentsLock.Lock()
+	defer environmentsLock.Unlock()
 
-	configFile := GetConfigFilePath()
-	if err := godotenv.Load(configFile); err != nil {
-		log.Printf("failed to load the config file under %s, Default Values will be used. error: %s", configFile, err)
+	if processEnvironments == nil {
+		configFilePath = GetConfigFilePath()
+		processEnvironments = map[string]bool{}
+		for _, environment := range os.Environ() {
+			name, _, _ := strings.Cut(environment, "=")
+			processEnvironments[name] = true
+		}
 	}
 
+	values, err := godotenv.Read(configFilePath)
+	if err != nil {
+		log.Printf("failed to load the config file under %s, Default Values will be used. error: %s", configFilePath, err)
+		values = map[string]string{}
+	}
+
+	for name := range fileEnvironments {
+		if _, found := values[name]; !found {
+			os.Unsetenv(name)
+			delete(fileEnvironments, name)
+		}
+	}
+
+	for name, value := range values {
+		if processEnvironments[name] {
+			continue
+		}
+		os.Setenv(name, value)
+		fileEnvironments[name] = true
+	}
 }
 
 // LoadOSPMConfigs loads all of the configurations defined
-// in the config file so then can be accessible fomrconfig.OSPMConfigs
+// in the config file so then can be accessible from config.Current()
 func LoadOSPMConfigs() {
 	LoadLocalEnvironments()
 
-	OSPM = &OSPMConfig{
-		API:            LoadAPISettings(),
-		Logrus:         LoadLogrusConfigs(),
-		RDMS:           LoadCockroachDBConfigs(),
-		ClientPolicies: LoadClientPolicies(),
+	loadedConfigs := load()
+	if err := loadedConfigs.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	if loadedConfigs.Auth.generatedSigningKey {
+		log.Printf("OSPM_AUTH_TOKEN_SIGNING_KEY is not set, a random key is generated. issued tokens will be invalid after restart")
+	}
+
+	current.Store(loadedConfigs)
+}
+
+// Reload reads the config file again and returns the new config if it is valid. It is not used
+// until it is passed to Use, so the files it points to can be checked before anything is replaced.
+// The settings of the listeners, the database and the RADIUS server are kept since they need a
+// restart, the returned warnings list the ones of them that are changed
+func Reload() (*OSPMConfig, []string, error) {
+	LoadLocalEnvironments()
+
+	previousConfigs := Current()
+	reloadedConfigs := load()
+	if err := reloadedConfigs.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	warnings := []string{}
+	restartOnly := []struct {
+		name     string
+		previous interface{}
+		reloaded interface{}
+		keep     func()
+	}{
+		{"API", previousConfigs.API, reloadedConfigs.API, func() { reloadedConfigs.API = previousConfigs.API }},
+		{"RDMS", previousConfigs.RDMS, reloadedConfigs.RDMS, func() { reloadedConfigs.RDMS = previousConfigs.RDMS }},
+		{"Radius", previousConfigs.Radius, reloadedConfigs.Radius, func() { reloadedConfigs.Radius = previousConfigs.Radius }},
+	}
+	for _, section := range restartOnly {
+		if !reflect.DeepEqual(section.previous, section.reloaded) {
+			warnings = append(warnings, fmt.Sprintf("changes of the %s settings need a restart", section.name))
+		}
+		section.keep()
+	}
+
+	// a new random key would invalidate all of the issued tokens
+	if reloadedConfigs.Auth.generatedSigningKey && previousConfigs.Auth.generatedSigningKey {
+		reloadedConfigs.Auth.TokenSigningKey = previousConfigs.Auth.TokenSigningKey
+	}
+
+	return reloadedConfigs, warnings, nil
+}
+
+// Use replaces the config in use
+func Use(configs *OSPMConfig) {
+	current.Store(configs)
+}
+
+// Validate checks the settings that can not be replaced by their default values
+func (c *OSPMConfig) Validate() error {
+	return errors.Join(
+		c.API.Validate(),
+		c.RDMS.Validate(),
+		c.Billing.Validate(),
+		c.Policy.Validate(),
+	)
+}
+
+// ConfigFilePath returns the path of the config file that is in use
+func ConfigFilePath() string {
+	environmentsLock.Lock()
+	defer environmentsLock.Unlock()
+
+	return configFilePath
+}
+
+func load() *OSPMConfig {
+	return &OSPMConfig{
+		API:        LoadAPISettings(),
+		Logrus:     LoadLogrusConfigs(),
+		RDMS:       LoadCockroachDBConfigs(),
+		Policy:     LoadPolicySettings(),
+		Auth:       LoadAuthSettings(),
+		Radius:     LoadRadiusSettings(),
+		Billing:    LoadBillingSettings(),
+		Permission: LoadPermissionSettings(),
 	}
 }
 
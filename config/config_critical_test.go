@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The test cases change the environment variables of the process so they are not run in parallel
+func TestReload(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.env")
+
+	environmentsLock.Lock()
+	configFilePath = configFile
+	processEnvironments = map[string]bool{}
+	environmentsLock.Unlock()
+
+	writeConfig := func(content string) {
+		assert.NoError(t, os.WriteFile(configFile, []byte(content), 0600))
+	}
+
+	writeConfig("OSPM_LOG_LEVEL=\"info\"\nOSPM_API_LISTEN_PORT=\"9898\"\nOSPM_BILLING_DEFAULT_CURRENCY=\"IRR\"\n")
+	LoadOSPMConfigs()
+	initialConfigs := Current()
+
+	type testCase struct {
+		name             string
+		content          string
+		expectedError    bool
+		expectedWarnings int
+		check            func(t *testing.T, reloaded *OSPMConfig)
+	}
+
+	testCases := []testCase{
+		{
+			name:    "the log level is changed. In this case, the new config should be returned without replacing the current one",
+			content: "OSPM_LOG_LEVEL=\"debug\"\nOSPM_API_LISTEN_PORT=\"9898\"\nOSPM_BILLING_DEFAULT_CURRENCY=\"IRR\"\n",
+			check: func(t *testing.T, reloaded *OSPMConfig) {
+				assert.Equal(t, "DEBUG", reloaded.Logrus.LogLevel)
+				assert.Equal(t, "INFO", Current().Logrus.LogLevel)
+			},
+		},
+		{
+			name:          "the currency is not valid. In this case, the reload should be rejected",
+			content:       "OSPM_LOG_LEVEL=\"debug\"\nOSPM_BILLING_DEFAULT_CURRENCY=\"Rial\"\n",
+			expectedError: true,
+		},
+		{
+			name:          "the client IP header is not one of the forwarding headers. In this case, the reload should be rejected",
+			content:       "OSPM_LOG_LEVEL=\"info\"\nOSPM_API_CLIENT_IP_HEADER=\"True-Client-IP\"\n",
+			expectedError: true,
+		},
+		{
+			name:             "the listen port is changed. In this case, the current API settings should be kept with a warning",
+			content:          "OSPM_LOG_LEVEL=\"info\"\nOSPM_API_LISTEN_PORT=\"9999\"\nOSPM_BILLING_DEFAULT_CURRENCY=\"IRR\"\n",
+			expectedWarnings: 1,
+			check: func(t *testing.T, reloaded *OSPMConfig) {
+				assert.Equal(t, "9898", reloaded.API.Port)
+			},
+		},
+		{
+			name:    "a variable is removed from the config file. In this case, its default value should be used",
+			content: "OSPM_LOG_LEVEL=\"info\"\nOSPM_API_LISTEN_PORT=\"9898\"\n",
+			check: func(t *testing.T, reloaded *OSPMConfig) {
+				_, found := os.LookupEnv("OSPM_BILLING_DEFAULT_CURRENCY")
+				assert.False(t, found)
+				assert.Equal(t, "IRR", reloaded.Billing.DefaultCurrency)
+			},
+		},
+		{
+			name:    "the signing key is not set. In this case, the generated key should be kept so the issued tokens stay valid",
+			content: "OSPM_LOG_LEVEL=\"info\"\nOSPM_API_LISTEN_PORT=\"9898\"\n",
+			check: func(t *testing.T, reloaded *OSPMConfig) {
+				assert.Equal(t, initialConfigs.Auth.TokenSigningKey, reloaded.Auth.TokenSigningKey)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writeConfig(tc.content)
+			reloaded, warnings, err := Reload()
+			if tc.expectedError {
+				assert.Error(t, err)
+				assert.Same(t, initialConfigs, Current())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, warnings, tc.expectedWarnings)
+			tc.check(t, reloaded)
+		})
+	}
+}
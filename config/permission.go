@@ -0,0 +1,22 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type PermissionSetting struct {
+	CatalogFile string
+	CacheTTL    time.Duration
+}
+
+func LoadPermissionSettings() *PermissionSetting {
+	loadedConfigs := &PermissionSetting{}
+
+	// an empty path means the built-in catalog is used
+	loadedConfigs.CatalogFile = os.Getenv("OSPM_PERMISSION_CATALOG_FILE")
+
+	loadedConfigs.CacheTTL = loadDuration("OSPM_PERMISSION_CACHE_TTL", 5*time.Minute)
+
+	return loadedConfigs
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type PolicySetting struct {
+	File string
+
+	legacyEnvs []string
+}
+
+// legacyPolicyEnvs are the whitelists that are replaced by the policy file
+var legacyPolicyEnvs = []string{
+	"ORGANIZATION_SOFT_DELETE_CLIENT_WHITELIST_IP",
+	"ORGANIZATION_HARD_DELETE_CLIENT_WHITELIST_IP",
+	"ORGANIZATION_LIST_ALL_CLIENT_WHITELIST_IP",
+	"UNDO_ORGANIZATION_SOFT_DELETE_CLIENT_WHITELIST_IP",
+	"ORGANIZATION_BALANCE_POLICY_CLIENT_WHITELIST_IP",
+}
+
+func LoadPolicySettings() *PolicySetting {
+	loadedConfigs := &PolicySetting{}
+
+	for _, envName := range legacyPolicyEnvs {
+		if os.Getenv(envName) != "" {
+			loadedConfigs.legacyEnvs = append(loadedConfigs.legacyEnvs, envName)
+		}
+	}
+
+	// an empty path means the built-in policy is used
+	loadedConfigs.File = os.Getenv("OSPM_POLICY_FILE")
+
+	return loadedConfigs
+}
+
+// Validate rejects the old whitelists, since ignoring them would silently
+// allow the requests they used to block
+func (p *PolicySetting) Validate() error {
+	if len(p.legacyEnvs) > 0 {
+		return fmt.Errorf("%s are not supported anymore, the whitelists should be moved to the policy file (OSPM_POLICY_FILE)",
+			strings.Join(p.legacyEnvs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type RadiusSetting struct {
+	Enabled        bool
+	ListenAddress  string
+	AuthPort       string
+	AccountingPort string
+	SharedSecret   string `json:"-"`
+	CHAPSecretKey  string `json:"-"`
+}
+
+func (r *RadiusSetting) GetAuthListenAddress() string {
+	return fmt.Sprintf("%s:%s", r.ListenAddress, r.AuthPort)
+}
+
+func (r *RadiusSetting) GetAccountingListenAddress() string {
+	return fmt.Sprintf("%s:%s", r.ListenAddress, r.AccountingPort)
+}
+
+func LoadRadiusSettings() *RadiusSetting {
+	loadedConfigs := &RadiusSetting{}
+
+	loadedConfigs.Enabled = strings.ToLower(os.Getenv("OSPM_RADIUS_ENABLED")) == "true"
+
+	loadedConfigs.ListenAddress = os.Getenv("OSPM_RADIUS_LISTEN_ADDRESS")
+	if loadedConfigs.ListenAddress == "" {
+		loadedConfigs.ListenAddress = "127.0.0.1"
+	}
+
+	loadedConfigs.AuthPort = os.Getenv("OSPM_RADIUS_AUTH_PORT")
+	if loadedConfigs.AuthPort == "" {
+		loadedConfigs.AuthPort = "1812"
+	}
+
+	loadedConfigs.AccountingPort = os.Getenv("OSPM_RADIUS_ACCOUNTING_PORT")
+	if loadedConfigs.AccountingPort == "" {
+		loadedConfigs.AccountingPort = "1813"
+	}
+
+	// there is no default value for the shared secret
+	loadedConfigs.SharedSecret = os.Getenv("OSPM_RADIUS_SHARED_SECRET")
+
+	// CHAP is only available if this key is set
+	loadedConfigs.CHAPSecretKey = os.Getenv("OSPM_RADIUS_CHAP_ENCRYPTION_KEY")
+
+	return loadedConfigs
+}
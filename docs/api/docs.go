@@ -19,31 +19,23 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/organization": {
+        "/api_key": {
             "get": {
-                "description": "\\",
+                "description": "Returns all of the API keys including the revoked and expired ones, without their secrets",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
-                ],
-                "summary": "List all organizations",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "includes soft deleted organizations (Optional)",
-                        "name": "list_all",
-                        "in": "query"
-                    }
+                    "API Key"
                 ],
+                "summary": "List API keys",
                 "responses": {
                     "200": {
-                        "description": "Successful Response",
+                        "description": "Successful response",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.OrganizationShortInfo"
+                                "$ref": "#/definitions/models.APIKeyResponse"
                             }
                         }
                     },
@@ -56,7 +48,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Adds a new organization to the system. The request body must contain the organization details.",
+                "description": "Creates a new operator API key with the given scopes. The key is only returned in this response and should be sent in the X-API-Key header",
                 "consumes": [
                     "application/json"
                 ],
@@ -64,28 +56,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "API Key"
                 ],
-                "summary": "Add a new organization",
+                "summary": "Create an API key",
                 "parameters": [
                     {
-                        "description": "Organization details",
+                        "description": "API key details",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Organization"
+                            "$ref": "#/definitions/models.APIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Organization successfully added",
+                        "description": "Successfully created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.APIKeySecretResponse"
                         }
                     },
                     "400": {
@@ -101,52 +90,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api_key/{api_key_id}": {
             "delete": {
-                "description": "\\",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Disables the API key permanently",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "API Key"
                 ],
-                "summary": "Delete an organization",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Organization ID",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization Name",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Deletion Mode: hard/soft",
-                        "name": "mode",
-                        "in": "query",
+                        "description": "API Key ID",
+                        "name": "api_key_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Organization successfully deleted",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.APIError"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "The key is already revoked",
                         "schema": {
                             "$ref": "#/definitions/models.APIError"
                         }
@@ -160,42 +136,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/organization/recover/profile": {
-            "patch": {
-                "description": "\\",
+        "/api_key/{api_key_id}/rotate": {
+            "post": {
+                "description": "Replaces the secret of the API key and keeps its scopes. The old key stops working immediately",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "API Key"
                 ],
-                "summary": "Recover s soft deleted organization",
+                "summary": "Rotate an API key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Organization ID",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization Name",
-                        "name": "name",
-                        "in": "query"
+                        "description": "API Key ID",
+                        "name": "api_key_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Organization successfully deleted",
+                        "description": "Successfully rotated",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.APIKeySecretResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The key is revoked",
                         "schema": {
                             "$ref": "#/definitions/models.APIError"
                         }
@@ -209,42 +183,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/organizations/profile": {
+        "/audit": {
             "get": {
-                "description": "Retrieves detailed information about a specific organization identified by its name or ID.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the audit entries of the changes that match the given filters, the newest first. Each entry has the actor, the before and after snapshots of the entity and the hash that chains it to the previous entry",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Audit"
                 ],
-                "summary": "Get organization profile by name or ID",
+                "summary": "Query the audit log",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Organization Name",
-                        "name": "name",
+                        "description": "Type of the entity: organization, subscriber_group, subscriber, subscription, organization_balance, product_offering, product_specification, api_key",
+                        "name": "entity_type",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Organization ID",
-                        "name": "id",
+                        "description": "ID of the entity",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key ID or IP of the actor",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the entries at or after the given time in RFC3339 format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the entries at or before the given time in RFC3339 format",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries to return (Default: 100, Max: 1000)",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful Response",
+                        "description": "Successful response",
                         "schema": {
-                            "$ref": "#/definitions/models.OrganizationResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Organization Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.APIError"
                         }
@@ -258,42 +256,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriber-group/list/{organization_id}": {
+        "/audit/verify": {
             "get": {
-                "description": "Returns a list of all subscriber groups within an organization",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Checks the hash of every audit entry and its link to the previous entry. The first broken entry is returned if the log has been tampered with",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
-                ],
-                "summary": "List all Subscriber Groups",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Organization ID",
-                        "name": "organization_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Audit"
                 ],
+                "summary": "Verify the audit log",
                 "responses": {
                     "200": {
                         "description": "Successful response",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.SubscriberGroupMinimal"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.APIError"
+                            "$ref": "#/definitions/models.AuditVerification"
                         }
                     },
                     "500": {
@@ -305,9 +282,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriber-group/{subscriber-group-id}": {
-            "delete": {
-                "description": "Deletes a specific subscriber group within an organization by its ID",
+        "/auth/login": {
+            "post": {
+                "description": "Verifies the subscriber credentials and returns a short lived access token and a refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -315,31 +292,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Authentication"
                 ],
-                "summary": "Delete a Subscriber Group",
+                "summary": "Subscriber login",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Subscriber Group ID",
-                        "name": "subscriber-group-id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Subscriber Group ID",
-                        "name": "organization-id",
-                        "in": "path",
-                        "required": true
+                        "description": "Subscriber Credentials",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenPair"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.APIError"
                         }
@@ -351,9 +332,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Updates the settings of a specific subscriber group within an organization by its ID",
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Rotates the given refresh token and returns a new pair of tokens. Each refresh token can be used once",
                 "consumes": [
                     "application/json"
                 ],
@@ -361,37 +344,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Authentication"
                 ],
-                "summary": "Update Subscriber Group",
+                "summary": "Refresh tokens",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Subscriber Group ID",
-                        "name": "subscriber-group-id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Subscriber Group ID",
-                        "name": "organization-id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Subscriber Group Settings",
+                        "description": "Refresh Token",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SubscriberGroupAPI"
+                            "$ref": "#/definitions/models.RefreshTokenRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "No Content"
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -402,43 +386,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriber_group/{organization_id}": {
+        "/auth/revoke": {
             "post": {
-                "description": "Adds a new subscriber group within an organization",
+                "description": "Revokes the session of the given access or refresh token. The other tokens of the session become invalid too",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Organization"
+                    "Authentication"
                 ],
-                "summary": "Add New Subscriber Group",
+                "summary": "Revoke tokens",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Subscriber Group ID",
-                        "name": "organization_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Subscriber Group Details",
+                        "description": "Access or Refresh Token",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SubscriberGroupAPI"
+                            "$ref": "#/definitions/models.RevokeTokenRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully added new subscriber group",
-                        "schema": {
-                            "$ref": "#/definitions/models.SubscriberGroupCreateResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -446,6 +417,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.APIError"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -455,9 +432,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriber_group/{subscriber_group_id}": {
-            "get": {
-                "description": "Retrieves the details of a specific subscriber group within an organization",
+        "/balance/{organization_id}/adjustment": {
+            "post": {
+                "description": "Corrects the balance of an organization by a signed amount. The negative balance threshold is not enforced for adjustments",
                 "consumes": [
                     "application/json"
                 ],
@@ -465,21 +442,230 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Balance"
                 ],
-                "summary": "Get Subscriber Group Detail",
+                "summary": "Adjust the organization balance",
                 "parameters": [
                     {
-                        "type": "integer",
+                        "type": "string",
                         "description": "Organization ID",
                         "name": "organization_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Subscriber Group ID",
-                        "name": "subscriber_group_id",
+                        "description": "Adjustment details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BalanceOperationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The request key is already used for the same adjustment",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerTransactionResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Successfully adjusted",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Request key is used for another operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/balance/{organization_id}/credit": {
+            "post": {
+                "description": "Tops up the balance of an organization. Retrying with the same request key does not change the balance again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Balance"
+                ],
+                "summary": "Credit the organization balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Credit details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BalanceOperationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The request key is already used for the same credit",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerTransactionResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Successfully credited",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Request key is used for another operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/balance/{organization_id}/debit": {
+            "post": {
+                "description": "Charges the balance of an organization. Debits that would take the balance over the negative balance threshold are rejected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Balance"
+                ],
+                "summary": "Debit the organization balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Debit details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BalanceOperationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The request key is already used for the same debit",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerTransactionResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Successfully debited",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Request key is used for another operation",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Insufficient balance",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/balance/{organization_id}/reconciliation": {
+            "get": {
+                "description": "Compares the stored balance of an organization with the sum of its ledger entries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Balance"
+                ],
+                "summary": "Reconcile the organization balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization_id",
                         "in": "path",
                         "required": true
                     }
@@ -488,7 +674,79 @@ const docTemplate = `{
                     "200": {
                         "description": "Successful response",
                         "schema": {
-                            "$ref": "#/definitions/models.SubscriberGroupAPI"
+                            "$ref": "#/definitions/models.BalanceReconciliation"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/balance/{organization_id}/reversal/{transaction_id}": {
+            "post": {
+                "description": "Cancels a ledger transaction of an organization by posting its opposite. Each transaction can only be reversed once. Reversals that lower the balance are rejected if they would take it over the negative balance threshold",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Balance"
+                ],
+                "summary": "Reverse a ledger transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ledger Transaction ID",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reversal details. The amount is ignored",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BalanceOperationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The request key is already used for the same reversal",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerTransactionResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Successfully reversed",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
                         }
                     },
                     "404": {
@@ -497,166 +755,3655 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.APIError"
                         }
                     },
+                    "409": {
+                        "description": "Request key is used for another operation or the transaction is not reversible",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Insufficient balance",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.APIError"
                         }
-                    }
+                    }
+                }
+            }
+        },
+        "/balance/{organization_id}/transactions": {
+            "get": {
+                "description": "Returns all of the ledger transactions of an organization, the latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Balance"
+                ],
+                "summary": "List ledger transactions of an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LedgerTransactionResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/productOffering": {
+            "get": {
+                "description": "Returns the product offerings that match the given filters. The total number of matches is returned in X-Total-Count header",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "List product offerings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exact name of the offering",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lifecycle status: draft/active/retired",
+                        "name": "lifecycleStatus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the product specification",
+                        "name": "specificationId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the offerings valid at the given time in RFC3339 format",
+                        "name": "validAt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of items to return (Default: 100, Max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProductOfferingResource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a new product offering. It is created in draft status unless another status is given. The referenced product specification must exist and an active offering needs an active specification",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Add product offering",
+                "parameters": [
+                    {
+                        "description": "Product offering",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductOfferingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductOfferingResource"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/productOffering/{product_offering_id}": {
+            "get": {
+                "description": "Retrieves a product offering by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Get product offering",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Offering ID",
+                        "name": "product_offering_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductOfferingResource"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a product offering in soft mode",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Delete product offering",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Offering ID",
+                        "name": "product_offering_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially updates a product offering. Lifecycle status can only move forward: draft -\u003e active -\u003e retired",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Update product offering",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Offering ID",
+                        "name": "product_offering_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductOfferingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductOfferingResource"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid lifecycle status transition",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/productSpecification": {
+            "get": {
+                "description": "Returns the product offering specifications that match the given filters. The total number of matches is returned in X-Total-Count header",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "List product specifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exact name of the specification",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lifecycle status: draft/active/retired",
+                        "name": "lifecycleStatus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of the specification: product/service",
+                        "name": "productSpecificationType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the specifications valid at the given time in RFC3339 format",
+                        "name": "validAt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of items to return (Default: 100, Max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProductSpecificationResource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a new product offering specification. It is created in draft status unless another status is given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Add product specification",
+                "parameters": [
+                    {
+                        "description": "Product specification",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductSpecificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductSpecificationResource"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/productSpecification/{product_specification_id}": {
+            "get": {
+                "description": "Retrieves a product offering specification by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Get product specification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Specification ID",
+                        "name": "product_specification_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductSpecificationResource"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a product offering specification in soft mode. Specifications that are referenced by offerings can not be deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Delete product specification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Specification ID",
+                        "name": "product_specification_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The specification is in use",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially updates a product offering specification. A specification can not be retired while it is used by offerings that are not retired",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Update product specification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Specification ID",
+                        "name": "product_specification_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductSpecificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductSpecificationResource"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid lifecycle status transition or the specification is in use",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization": {
+            "get": {
+                "description": "\\",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "List organizations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of organizations in the page (Default: 100, Max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key: name, created_at or balance. A - prefix sorts in descending order, e.g. -balance (Default: created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the organizations whose name starts with the given value, case-insensitive",
+                        "name": "name_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the organizations whose owner is of the given type: legal, individual",
+                        "name": "owner_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deleted state: exclude, include or only (Default: exclude)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deprecated, true is the same as deleted=include",
+                        "name": "list_all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the organizations created at or after the given time in RFC3339 format",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the organizations created at or before the given time in RFC3339 format",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the organizations whose balance amount is at least the given value",
+                        "name": "balance_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the organizations whose balance amount is at most the given value",
+                        "name": "balance_max",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a new organization to the system. The request body must contain the organization details.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Add a new organization",
+                "parameters": [
+                    {
+                        "description": "Organization details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Organization"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Organization successfully added",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "\\",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Delete an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization Name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deletion Mode: hard/soft",
+                        "name": "mode",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Organization successfully deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/profile": {
+            "patch": {
+                "description": "\\",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Update the organization profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization Name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "description": "JSON Merge Patch of the profile",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationProfilePatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The name, email or mobile is used by another organization",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/profile/balance_policy": {
+            "patch": {
+                "description": "\\",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Update the organization balance policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization Name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Balance policy",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BalancePolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Denied by the route policy",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/recover/profile": {
+            "patch": {
+                "description": "\\",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Recover s soft deleted organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization Name",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Organization successfully deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/profile": {
+            "get": {
+                "description": "Retrieves detailed information about a specific organization identified by its name or ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Get organization profile by name or ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization Name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/permission_catalog": {
+            "get": {
+                "description": "Returns the version of the permission catalog and the definition of the permissions that can be given to the subscriber groups",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Permission"
+                ],
+                "summary": "Get the permission catalog",
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.PermissionCatalog"
+                        }
+                    }
+                }
+            }
+        },
+        "/permission_catalog/{permission_category}": {
+            "get": {
+                "description": "Returns the definition of the permissions of the given category",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Permission"
+                ],
+                "summary": "Get a permission category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Permission Category",
+                        "name": "permission_category",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.PermissionCatalog"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber": {
+            "post": {
+                "description": "Adds a new subscriber. The given organization and subscriber group must already exist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriber"
+                ],
+                "summary": "Add New Subscriber",
+                "parameters": [
+                    {
+                        "description": "Subscriber Details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Subscriber"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully added new subscriber",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber-group/list/{organization_id}": {
+            "get": {
+                "description": "Returns a list of all subscriber groups within an organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "List all Subscriber Groups",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SubscriberGroupMinimal"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber-group/{subscriber-group-id}": {
+            "delete": {
+                "description": "Deletes a specific subscriber group within an organization by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Delete a Subscriber Group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscriber Group ID",
+                        "name": "subscriber-group-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Subscriber Group ID",
+                        "name": "organization-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber/list/organization/{organization_id}": {
+            "get": {
+                "description": "Returns a list of all subscribers within an organization. Soft deleteds will not be listed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriber"
+                ],
+                "summary": "List subscribers of an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SubscriberShortInfo"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber/list/subscriber_group/{subscriber_group_id}": {
+            "get": {
+                "description": "Returns a list of all subscribers that are member of the given subscriber group. Soft deleteds will not be listed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriber"
+                ],
+                "summary": "List subscribers of a subscriber group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber Group ID",
+                        "name": "subscriber_group_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SubscriberShortInfo"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber/recover/{subscriber_id}": {
+            "patch": {
+                "description": "Recovers a soft deleted subscriber. The organization and subscriber group of the subscriber must still exist",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriber"
+                ],
+                "summary": "Recover a soft deleted subscriber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscriber successfully recovered",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber/{subscriber_id}": {
+            "get": {
+                "description": "Retrieves the details of a specific subscriber. Credentials are never returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriber"
+                ],
+                "summary": "Get Subscriber Detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriberResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a specific subscriber by its ID either in soft or hard mode",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriber"
+                ],
+                "summary": "Delete a Subscriber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deletion Mode: hard/soft",
+                        "name": "mode",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates the details, credentials or the subscriber group of a specific subscriber. Empty fields are left untouched",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriber"
+                ],
+                "summary": "Update Subscriber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscriber Details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Subscriber"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber/{subscriber_id}/entitlement": {
+            "get": {
+                "description": "Returns the subscriptions of a subscriber which were active at the given time. The current time is used by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscription"
+                ],
+                "summary": "List entitlements of a subscriber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The time in RFC3339 format",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SubscriptionResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber/{subscriber_id}/permission/evaluate": {
+            "post": {
+                "description": "Resolves the permissions of the subscriber through its subscriber group and decides on each of the given permissions. If the value of a permission is given, the group must have the same value, otherwise any value other than false or no is allowed. At most 100 permissions can be evaluated at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Permission"
+                ],
+                "summary": "Evaluate the permissions of a subscriber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permissions to evaluate",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PermissionEvaluationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.PermissionEvaluationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber/{subscriber_id}/permission/{permission_category}/{permission_name}": {
+            "get": {
+                "description": "Decides whether the subscriber holds the permission. It is the single permission version of the evaluate endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Permission"
+                ],
+                "summary": "Evaluate a permission of a subscriber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Permission Category",
+                        "name": "permission_category",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Permission Name",
+                        "name": "permission_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The value that the subscriber should have",
+                        "name": "value",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.PermissionDecision"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber/{subscriber_id}/subscription": {
+            "get": {
+                "description": "Returns all of the subscriptions of a subscriber including the terminated ones with their history, the latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscription"
+                ],
+                "summary": "List subscriptions of a subscriber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SubscriptionResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribes a subscriber to an active product offering. The subscription is pending until its start date if it is in the future",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscription"
+                ],
+                "summary": "Subscribe a subscriber to a product offering",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully subscribed",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Already subscribed to the product offering",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber_group/{organization_id}": {
+            "post": {
+                "description": "Adds a new subscriber group within an organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Add New Subscriber Group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscriber Group ID",
+                        "name": "organization_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Format version of the body. Version 2 accepts models.SubscriberGroupCreateRequestV2 (Default: 1)",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Subscriber Group Details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriberGroupAPI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully added new subscriber group",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriberGroupCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriber_group/{subscriber_group_id}": {
+            "get": {
+                "description": "Retrieves the details of a specific subscriber group within an organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Get Subscriber Group Detail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Subscriber Group ID",
+                        "name": "subscriber_group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Format version of the group. Version 2 (models.SubscriberGroupAPIV2) nests the permissions by category and name (Default: 1)",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriberGroupAPI"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates the settings of a specific subscriber group by its ID. Either the whole permission set is replaced or individual permissions are added, modified and removed, all in one transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Update Subscriber Group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber Group ID",
+                        "name": "subscriber_group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Format version of the body and the response. Version 2 accepts models.SubscriberGroupUpdateRequestV2 and returns models.SubscriberGroupAPIV2 (Default: 1)",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Subscriber Group Settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriberGroupUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated subscriber group",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriberGroupAPI"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The permission to add already exists or the permission to change does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/{subscription_id}": {
+            "get": {
+                "description": "Retrieves a subscription and its history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscription"
+                ],
+                "summary": "Get Subscription Detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/{subscription_id}/change_plan": {
+            "post": {
+                "description": "Terminates an active subscription and subscribes the subscriber to another product offering from now on. The new subscription is returned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscription"
+                ],
+                "summary": "Change the plan of a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The new product offering",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The subscription is not active",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/{subscription_id}/resume": {
+            "post": {
+                "description": "Activates a suspended subscription again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscription"
+                ],
+                "summary": "Resume a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The reason of the resumption",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The subscription is not suspended",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/{subscription_id}/suspend": {
+            "post": {
+                "description": "Suspends an active subscription until it is resumed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscription"
+                ],
+                "summary": "Suspend a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The reason of the suspension",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The subscription is not active",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/{subscription_id}/terminate": {
+            "post": {
+                "description": "Ends a subscription now. Terminated subscriptions can not be changed anymore",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscription"
+                ],
+                "summary": "Terminate a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The reason of the termination",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscriptionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The subscription is already terminated",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/usage/organization/{organization_id}": {
+            "get": {
+                "description": "Returns the total usage of all subscribers of an organization for the sessions started in the given time range. The last 30 days are used by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usage"
+                ],
+                "summary": "Get usage of an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the time range in RFC3339 format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the time range in RFC3339 format",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.UsageSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/usage/subscriber/{subscriber_id}": {
+            "get": {
+                "description": "Returns the total usage of a subscriber for the sessions started in the given time range. The last 30 days are used by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usage"
+                ],
+                "summary": "Get usage of a subscriber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the time range in RFC3339 format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the time range in RFC3339 format",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "$ref": "#/definitions/models.UsageSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/usage/subscriber/{subscriber_id}/sessions": {
+            "get": {
+                "description": "Returns the accounting sessions of a subscriber started in the given time range. The last 30 days are used by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usage"
+                ],
+                "summary": "List sessions of a subscriber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscriber ID",
+                        "name": "subscriber_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the time range in RFC3339 format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the time range in RFC3339 format",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AccountingSessionInfo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.APIError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "This field determines the raised error",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "This field determines the detailed information about raise error",
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "the key does not expire if it is not given",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "billing system"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "organization:read",
+                        "subscriber_group:write"
+                    ]
+                }
+            }
+        },
+        "models.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "billing system"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "3f9a1c2e"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "rotated_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "organization:read",
+                        "subscriber_group:write"
+                    ]
+                }
+            }
+        },
+        "models.APIKeySecretResponse": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "ospm_3f9a1c2e_9mR3xV0c2Lq8aZ1bW5nY7tK4pD6sH0jF2gE8uI3oA1c"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "billing system"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "3f9a1c2e"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "rotated_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "organization:read",
+                        "subscriber_group:write"
+                    ]
+                }
+            }
+        },
+        "models.AccountingSessionInfo": {
+            "type": "object",
+            "properties": {
+                "accounting_session_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "acct_session_id": {
+                    "type": "string",
+                    "example": "81200004"
+                },
+                "calling_station_id": {
+                    "type": "string"
+                },
+                "framed_ip_address": {
+                    "type": "string",
+                    "example": "100.64.0.10"
+                },
+                "input_octets": {
+                    "type": "integer"
+                },
+                "input_packets": {
+                    "type": "integer"
+                },
+                "last_updated_at": {
+                    "type": "string"
+                },
+                "nas_identifier": {
+                    "type": "string",
+                    "example": "bras-01"
+                },
+                "nas_ip_address": {
+                    "type": "string",
+                    "example": "10.0.0.1"
+                },
+                "nas_port_id": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "output_octets": {
+                    "type": "integer"
+                },
+                "output_packets": {
+                    "type": "integer"
+                },
+                "session_time": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "stopped_at": {
+                    "type": "string"
+                },
+                "subscriber_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "terminate_cause": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "valid values: create, update, delete, hard_delete, recover",
+                    "type": "string",
+                    "example": "hard_delete"
+                },
+                "actor_api_key_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "actor_ip": {
+                    "type": "string",
+                    "example": "10.20.30.40"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string",
+                    "example": "966c1a5d-1a0e-4b8b-9d6f-2b1e3d7c9a10"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "example": "organization"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "previous_hash": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "5f0c5a39-0e3b-4d45-9d0f-26b5b8b2b41a"
+                },
+                "sequence": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.AuditVerification": {
+            "type": "object",
+            "properties": {
+                "broken_sequence": {
+                    "description": "the first entry whose hash or link does not match",
+                    "type": "integer",
+                    "example": 731
+                },
+                "checked_entries": {
+                    "type": "integer",
+                    "example": 1250
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "the hash of the entry does not match its content"
+                },
+                "valid": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.BalanceOperationRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.50"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IRR"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "monthly top-up"
+                },
+                "request_key": {
+                    "description": "Unique key of the request within the organization to make retries safe",
+                    "type": "string",
+                    "example": "top-up-2024-01-0001"
+                }
+            }
+        },
+        "models.BalancePolicyRequest": {
+            "type": "object",
+            "properties": {
+                "allow_negative_balance": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IRR"
+                },
+                "negative_balance_threshold": {
+                    "type": "string",
+                    "example": "1000"
+                }
+            }
+        },
+        "models.BalanceReconciliation": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "the balance stored on the organization",
+                    "type": "string",
+                    "example": "100.50"
+                },
+                "consistent": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IRR"
+                },
+                "difference": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "ledger_balance": {
+                    "description": "the sum of the organization balance account entries",
+                    "type": "string",
+                    "example": "100.50"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                }
+            }
+        },
+        "models.CharacteristicSpecification": {
+            "type": "object",
+            "properties": {
+                "allowedValues": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "maxValue": {
+                    "type": "string",
+                    "example": "1000000000bps"
+                },
+                "minValue": {
+                    "type": "string",
+                    "example": "1000000bps"
+                },
+                "valueType": {
+                    "description": "valid values: integer, decimal, boolean, enum, duration, bandwidth, data_volume",
+                    "type": "string",
+                    "example": "bandwidth"
+                }
+            }
+        },
+        "models.LedgerEntryResponse": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string",
+                    "example": "organization_balance"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "100.50"
+                }
+            }
+        },
+        "models.LedgerTransactionResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.50"
+                },
+                "balance_after": {
+                    "type": "string",
+                    "example": "100.50"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IRR"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "monthly top-up"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LedgerEntryResponse"
+                    }
+                },
+                "ledger_transaction_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "request_key": {
+                    "type": "string",
+                    "example": "top-up-2024-01-0001"
+                },
+                "reversed_transaction_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "credit"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "S3cret!"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "subscriber1"
+                }
+            }
+        },
+        "models.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.50"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IRR"
+                }
+            }
+        },
+        "models.Organization": {
+            "type": "object"
+        },
+        "models.OrganizationDetailsResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "mobile": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrganizationOwnerResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "legal_national_id": {
+                    "type": "string"
+                },
+                "mobile": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrganizationPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrganizationShortInfo"
+                    }
+                },
+                "next_cursor": {
+                    "description": "the cursor of the next page, empty on the last page",
+                    "type": "string",
+                    "example": "eyJzIjoibmFtZSJ9"
+                },
+                "total": {
+                    "description": "the number of the organizations that match the filters in all of the pages",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.OrganizationProfilePatch": {
+            "type": "object",
+            "properties": {
+                "organization_details": {
+                    "$ref": "#/definitions/models.OrganizationDetailsResponse"
+                },
+                "organization_owner": {
+                    "$ref": "#/definitions/models.OrganizationOwnerResponse"
+                }
+            }
+        },
+        "models.OrganizationResponse": {
+            "type": "object",
+            "properties": {
+                "allow_negative_balance": {
+                    "type": "boolean"
+                },
+                "balance": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "negative_balance_threshold": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "organization_details": {
+                    "$ref": "#/definitions/models.OrganizationDetailsResponse"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "organization_owner": {
+                    "$ref": "#/definitions/models.OrganizationOwnerResponse"
+                }
+            }
+        },
+        "models.OrganizationShortInfo": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "null unless the organization is soft deleted",
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "This field determines the unique id of the organization. The id is in uuid v4 format",
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "organization_name": {
+                    "description": "This field determines the name of the organization",
+                    "type": "string",
+                    "example": "sample organization"
+                },
+                "owner_type": {
+                    "description": "valid values are: legal, individual",
+                    "type": "string",
+                    "example": "legal"
+                }
+            }
+        },
+        "models.PermissionAPI": {
+            "type": "object",
+            "properties": {
+                "permission_category": {
+                    "type": "string",
+                    "example": "REPORT_LEVEL"
+                },
+                "permission_name": {
+                    "type": "string",
+                    "example": "CAN_VIEW_PAYMENT_HISTORY"
+                },
+                "permission_value": {
+                    "type": "string",
+                    "example": "yes"
+                }
+            }
+        },
+        "models.PermissionCatalog": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PermissionDefinition"
+                    }
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "models.PermissionDecision": {
+            "type": "object",
+            "properties": {
+                "decision": {
+                    "type": "string",
+                    "example": "allow"
+                },
+                "matched_rule": {
+                    "$ref": "#/definitions/models.PermissionRule"
+                },
+                "permission_category": {
+                    "type": "string",
+                    "example": "REPORT_LEVEL"
+                },
+                "permission_name": {
+                    "type": "string",
+                    "example": "CAN_VIEW_PAYMENT_HISTORY"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "the subscriber group grants the permission"
+                },
+                "requested_value": {
+                    "type": "string",
+                    "example": "yes"
+                }
+            }
+        },
+        "models.PermissionDefinition": {
+            "type": "object",
+            "properties": {
+                "allowed_values": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string",
+                    "example": "allows the subscriber to see the payment history"
+                },
+                "max_value": {
+                    "type": "string"
+                },
+                "min_value": {
+                    "type": "string"
+                },
+                "permission_category": {
+                    "type": "string",
+                    "example": "REPORT_LEVEL"
+                },
+                "permission_name": {
+                    "type": "string",
+                    "example": "CAN_VIEW_PAYMENT_HISTORY"
+                },
+                "value_type": {
+                    "type": "string",
+                    "example": "enum"
+                }
+            }
+        },
+        "models.PermissionEvaluationRequest": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PermissionAPI"
+                    }
+                }
+            }
+        },
+        "models.PermissionEvaluationResponse": {
+            "type": "object",
+            "properties": {
+                "decisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PermissionDecision"
+                    }
+                },
+                "subscriber_group_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7b"
+                },
+                "subscriber_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                }
+            }
+        },
+        "models.PermissionRule": {
+            "type": "object",
+            "properties": {
+                "permission_category": {
+                    "type": "string",
+                    "example": "REPORT_LEVEL"
+                },
+                "permission_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7c"
+                },
+                "permission_name": {
+                    "type": "string",
+                    "example": "CAN_VIEW_PAYMENT_HISTORY"
+                },
+                "permission_value": {
+                    "type": "string",
+                    "example": "yes"
+                },
+                "subscriber_group_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7b"
+                }
+            }
+        },
+        "models.ProductOfferingRequest": {
+            "type": "object",
+            "properties": {
+                "characteristicValue": {
+                    "type": "string",
+                    "example": "100Mbps"
+                },
+                "characteristicValueType": {
+                    "type": "string",
+                    "example": "bandwidth"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "lifecycleStatus": {
+                    "type": "string",
+                    "example": "draft"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fiber 100M Monthly"
+                },
+                "persianName": {
+                    "type": "string"
+                },
+                "productSpecification": {
+                    "$ref": "#/definitions/models.ProductSpecificationRef"
+                },
+                "validFor": {
+                    "$ref": "#/definitions/models.TimePeriod"
+                }
+            }
+        },
+        "models.ProductOfferingResource": {
+            "type": "object",
+            "properties": {
+                "@type": {
+                    "type": "string",
+                    "example": "ProductOffering"
+                },
+                "characteristicValue": {
+                    "description": "normalized by its type",
+                    "type": "string",
+                    "example": "100000000bps"
+                },
+                "characteristicValueType": {
+                    "type": "string",
+                    "example": "bandwidth"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "href": {
+                    "type": "string",
+                    "example": "/catalog/productOffering/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "lastUpdate": {
+                    "type": "string"
+                },
+                "lifecycleStatus": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fiber 100M Monthly"
+                },
+                "persianName": {
+                    "type": "string"
+                },
+                "productSpecification": {
+                    "$ref": "#/definitions/models.ProductSpecificationRef"
+                },
+                "validFor": {
+                    "$ref": "#/definitions/models.TimePeriod"
+                }
+            }
+        },
+        "models.ProductSpecificationRef": {
+            "type": "object",
+            "properties": {
+                "href": {
+                    "type": "string",
+                    "example": "/catalog/productSpecification/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fiber Internet"
+                }
+            }
+        },
+        "models.ProductSpecificationRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "lifecycleStatus": {
+                    "type": "string",
+                    "example": "draft"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fiber Internet"
+                },
+                "persianName": {
+                    "type": "string"
+                },
+                "productSpecCharacteristic": {
+                    "description": "replaces the whole characteristic specification when given",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CharacteristicSpecification"
+                        }
+                    ]
+                },
+                "productSpecificationType": {
+                    "type": "string",
+                    "example": "service"
+                },
+                "validFor": {
+                    "$ref": "#/definitions/models.TimePeriod"
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.APIError": {
+        },
+        "models.ProductSpecificationResource": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "This field determines the raised error",
+                "@type": {
+                    "type": "string",
+                    "example": "ProductSpecification"
+                },
+                "description": {
                     "type": "string"
                 },
-                "message": {
-                    "description": "This field determines the detailed information about raise error",
+                "href": {
+                    "type": "string",
+                    "example": "/catalog/productSpecification/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "lastUpdate": {
+                    "type": "string"
+                },
+                "lifecycleStatus": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fiber Internet"
+                },
+                "persianName": {
                     "type": "string"
+                },
+                "productSpecCharacteristic": {
+                    "$ref": "#/definitions/models.CharacteristicSpecification"
+                },
+                "productSpecificationType": {
+                    "description": "valid values: product, service",
+                    "type": "string",
+                    "example": "service"
+                },
+                "validFor": {
+                    "$ref": "#/definitions/models.TimePeriod"
                 }
             }
         },
-        "models.Organization": {
+        "models.RefreshTokenRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RevokeTokenRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Subscriber": {
             "type": "object"
         },
-        "models.OrganizationDetailsResponse": {
+        "models.SubscriberDetailsResponse": {
             "type": "object",
             "properties": {
-                "address": {
+                "passport_id": {
                     "type": "string"
                 },
-                "email": {
+                "subscriber_email": {
                     "type": "string"
                 },
-                "mobile": {
+                "subscriber_mobile": {
                     "type": "string"
                 },
-                "name": {
+                "subscriber_name": {
                     "type": "string"
                 },
-                "phone": {
+                "subscriber_national_id": {
+                    "type": "string"
+                },
+                "subscriber_phone": {
                     "type": "string"
                 }
             }
         },
-        "models.OrganizationOwnerResponse": {
+        "models.SubscriberGroupAPI": {
             "type": "object",
             "properties": {
-                "address": {
+                "organization_id": {
                     "type": "string"
                 },
-                "email": {
+                "subscriber_group_description": {
                     "type": "string"
                 },
-                "legal_national_id": {
+                "subscriber_group_id": {
                     "type": "string"
                 },
-                "mobile": {
+                "subscriber_group_name": {
                     "type": "string"
                 },
-                "name": {
+                "subscriber_group_permissions": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.SubscriberGroupCreateResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "string"
                 },
-                "phone": {
+                "message": {
                     "type": "string"
                 },
-                "type": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "models.OrganizationResponse": {
+        "models.SubscriberGroupMinimal": {
             "type": "object",
             "properties": {
-                "allow_negative_balance": {
-                    "type": "boolean"
+                "subscriber_group_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
                 },
-                "balance": {
-                    "type": "number"
+                "subscriber_group_name": {
+                    "type": "string",
+                    "example": "sample group"
+                }
+            }
+        },
+        "models.SubscriberGroupUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "add_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PermissionAPI"
+                    }
                 },
-                "negative_balance_threshold": {
-                    "type": "number"
+                "remove_permissions": {
+                    "description": "the value is ignored",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PermissionAPI"
+                    }
                 },
-                "organization_details": {
-                    "$ref": "#/definitions/models.OrganizationDetailsResponse"
+                "subscriber_group_description": {
+                    "type": "string",
+                    "example": "sample description"
+                },
+                "subscriber_group_name": {
+                    "type": "string",
+                    "example": "sample group"
+                },
+                "subscriber_group_permissions": {
+                    "description": "replaces the whole permission set when given",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PermissionAPI"
+                    }
                 },
+                "update_permissions": {
+                    "description": "changes the value of the existing permissions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PermissionAPI"
+                    }
+                }
+            }
+        },
+        "models.SubscriberResponse": {
+            "type": "object",
+            "properties": {
                 "organization_id": {
                     "type": "string",
                     "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
                 },
-                "organization_owner": {
-                    "$ref": "#/definitions/models.OrganizationOwnerResponse"
+                "subscriber_details": {
+                    "$ref": "#/definitions/models.SubscriberDetailsResponse"
+                },
+                "subscriber_group_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7b"
+                },
+                "subscriber_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "subscriber_username": {
+                    "type": "string"
                 }
             }
         },
-        "models.OrganizationShortInfo": {
+        "models.SubscriberShortInfo": {
             "type": "object",
             "properties": {
-                "organization_id": {
-                    "description": "This field determines the unique id of the organization. The id is in uuid v4 format",
+                "subscriber_group_id": {
+                    "description": "This field determines the group which the subscriber belongs to",
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7b"
+                },
+                "subscriber_id": {
+                    "description": "This field determines the unique id of the subscriber. The id is in uuid v4 format",
                     "type": "string",
                     "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
                 },
-                "organization_name": {
-                    "description": "This field determines the name of the organization",
+                "subscriber_name": {
+                    "description": "This field determines the name of the subscriber",
                     "type": "string",
-                    "example": "sample organization"
+                    "example": "sample subscriber"
                 }
             }
         },
-        "models.SubscriberGroupAPI": {
+        "models.SubscriptionActionRequest": {
             "type": "object",
             "properties": {
-                "organization_id": {
-                    "type": "string"
+                "product_offering_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
                 },
-                "subscriber_group_description": {
-                    "type": "string"
+                "reason": {
+                    "type": "string",
+                    "example": "upgrade"
+                }
+            }
+        },
+        "models.SubscriptionEventResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "subscribe"
                 },
-                "subscriber_group_id": {
+                "effective_at": {
                     "type": "string"
                 },
-                "subscriber_group_name": {
+                "from_status": {
+                    "type": "string",
+                    "example": ""
+                },
+                "product_offering_id": {
                     "type": "string"
                 },
-                "subscriber_group_permissions": {
-                    "type": "object",
-                    "additionalProperties": true
+                "reason": {
+                    "type": "string",
+                    "example": "new customer"
+                },
+                "to_status": {
+                    "type": "string",
+                    "example": "active"
                 }
             }
         },
-        "models.SubscriberGroupCreateResponse": {
+        "models.SubscriptionRequest": {
             "type": "object",
             "properties": {
-                "id": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                },
+                "product_offering_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "new customer"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.SubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "end_date": {
                     "type": "string"
                 },
-                "message": {
+                "previous_subscription_id": {
                     "type": "string"
                 },
-                "name": {
+                "product_offering_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "start_date": {
                     "type": "string"
+                },
+                "subscriber_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "subscription_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubscriptionEventResponse"
+                    }
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                },
+                "subscription_status": {
+                    "type": "string",
+                    "example": "active"
                 }
             }
         },
-        "models.SubscriberGroupMinimal": {
+        "models.TimePeriod": {
             "type": "object",
             "properties": {
-                "subscriber_group_id": {
+                "endDateTime": {
                     "type": "string",
-                    "example": "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+                    "example": "2024-12-31T23:59:59Z"
                 },
-                "subscriber_group_name": {
+                "startDateTime": {
                     "type": "string",
-                    "example": "sample group"
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.TokenPair": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "access_token_expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "refresh_token_expires_at": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "models.UsageSummary": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "input_octets": {
+                    "type": "integer"
+                },
+                "input_packets": {
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "output_octets": {
+                    "type": "integer"
+                },
+                "output_packets": {
+                    "type": "integer"
+                },
+                "session_count": {
+                    "type": "integer"
+                },
+                "session_time": {
+                    "type": "integer"
+                },
+                "subscriber_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "APIKey": {
+            "description": "Operator API key. Every endpoint except /apidoc and /auth needs a key with the scope of the request",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        }
     }
 }`
 
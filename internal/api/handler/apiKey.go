@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/api/middleware"
+	"ospm/internal/models"
+	"ospm/internal/service/apikey"
+	"ospm/internal/service/logger"
+
+	// This line is being used by swagger auto-documenting
+	_ "ospm/docs/api"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// @Summary 	Create an API key
+// @Description Creates a new operator API key with the given scopes. The key is only returned in this response and should be sent in the X-API-Key header
+// @Tags 		API Key
+// @Accept  	json
+// @Produce  	json
+// @Param 		body body models.APIKeyRequest true "API key details"
+// @Success 	201 {object} models.APIKeySecretResponse "Successfully created"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/api_key [post]
+func AddAPIKey(context *fiber.Ctx) error {
+	var request models.APIKeyRequest
+	if err := context.BodyParser(&request); err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	created, err := apikey.Create(request, middleware.Actor(context))
+	if err != nil {
+		return apiKeyError(context, err)
+	}
+
+	return context.Status(fiber.StatusCreated).JSON(created)
+}
+
+// @Summary 	List API keys
+// @Description Returns all of the API keys including the revoked and expired ones, without their secrets
+// @Tags 		API Key
+// @Produce  	json
+// @Success 	200 {array} models.APIKeyResponse "Successful response"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/api_key [get]
+func GetAPIKeyList(context *fiber.Ctx) error {
+	apiKeys, err := apikey.List()
+	if err != nil {
+		return apiKeyError(context, err)
+	}
+
+	return context.Status(200).JSON(apiKeys)
+}
+
+// @Summary 	Rotate an API key
+// @Description Replaces the secret of the API key and keeps its scopes. The old key stops working immediately
+// @Tags 		API Key
+// @Produce  	json
+// @Param 		api_key_id path string true "API Key ID"
+// @Success 	200 {object} models.APIKeySecretResponse "Successfully rotated"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "The key is revoked"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/api_key/{api_key_id}/rotate [post]
+func RotateAPIKey(context *fiber.Ctx) error {
+	rotated, err := apikey.Rotate(context.Params("api_key_id"), middleware.Actor(context))
+	if err != nil {
+		return apiKeyError(context, err)
+	}
+
+	return context.Status(200).JSON(rotated)
+}
+
+// @Summary 	Revoke an API key
+// @Description Disables the API key permanently
+// @Tags 		API Key
+// @Produce  	json
+// @Param 		api_key_id path string true "API Key ID"
+// @Success 	204 "No Content"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "The key is already revoked"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/api_key/{api_key_id} [delete]
+func RevokeAPIKey(context *fiber.Ctx) error {
+	if err := apikey.Revoke(context.Params("api_key_id"), middleware.Actor(context)); err != nil {
+		return apiKeyError(context, err)
+	}
+
+	return context.SendStatus(204)
+}
+
+func apiKeyError(context *fiber.Ctx, err error) error {
+	responseCode := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		responseCode = fiber.StatusNotFound
+	case errors.Is(err, apikey.ErrInvalidRequest):
+		responseCode = fiber.StatusBadRequest
+	case errors.Is(err, apikey.ErrRevoked):
+		responseCode = fiber.StatusConflict
+	}
+	logger.OSPMLogger.Errorln(
+		fmt.Sprintf(
+			"failed to process request. Path: %s, client ip: %s, error: %+v",
+			context.Path(), middleware.ClientIP(context), err))
+	return context.Status(responseCode).JSON(models.APIError{
+		Error:   err.Error(),
+		Message: "failed to process the API key request",
+	})
+}
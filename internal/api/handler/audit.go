@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/api/middleware"
+	"ospm/internal/models"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/logger"
+	"time"
+
+	// This line is being used by swagger auto-documenting
+	_ "ospm/docs/api"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary 	Query the audit log
+// @Description Returns the audit entries of the changes that match the given filters, the newest first. Each entry has the actor, the before and after snapshots of the entity and the hash that chains it to the previous entry
+// @Tags 		Audit
+// @Produce  	json
+// @Param 		entity_type query string false "Type of the entity: organization, subscriber_group, subscriber, subscription, organization_balance, product_offering, product_specification, api_key"
+// @Param 		entity_id query string false "ID of the entity"
+// @Param 		actor query string false "API key ID or IP of the actor"
+// @Param 		from query string false "Only the entries at or after the given time in RFC3339 format"
+// @Param 		to query string false "Only the entries at or before the given time in RFC3339 format"
+// @Param 		limit query int false "Maximum number of entries to return (Default: 100, Max: 1000)"
+// @Success 	200 {array} models.AuditEntry "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/audit [get]
+func GetAuditLog(context *fiber.Ctx) error {
+	filter, err := auditFilter(context)
+	if err != nil {
+		return auditError(context, fmt.Errorf("%w: %s", audit.ErrInvalidFilter, err))
+	}
+
+	entries, err := audit.Query(filter)
+	if err != nil {
+		return auditError(context, err)
+	}
+
+	return context.Status(200).JSON(entries)
+}
+
+// @Summary 	Verify the audit log
+// @Description Checks the hash of every audit entry and its link to the previous entry. The first broken entry is returned if the log has been tampered with
+// @Tags 		Audit
+// @Produce  	json
+// @Success 	200 {object} models.AuditVerification "Successful response"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/audit/verify [get]
+func VerifyAuditLog(context *fiber.Ctx) error {
+	verification, err := audit.Verify()
+	if err != nil {
+		return auditError(context, err)
+	}
+
+	return context.Status(200).JSON(verification)
+}
+
+func auditFilter(context *fiber.Ctx) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		EntityType: context.Query("entity_type"),
+		EntityID:   context.Query("entity_id"),
+		Actor:      context.Query("actor"),
+		Limit:      context.QueryInt("limit", 0),
+	}
+
+	for name, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if context.Query(name) == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, context.Query(name))
+		if err != nil {
+			return filter, fmt.Errorf("%s should be in RFC3339 format, %s", name, err)
+		}
+		*target = &parsed
+	}
+
+	return filter, nil
+}
+
+// auditError maps the errors of the audit service to the response
+func auditError(context *fiber.Ctx, err error) error {
+	responseCode := fiber.StatusInternalServerError
+	if errors.Is(err, audit.ErrInvalidFilter) {
+		responseCode = fiber.StatusBadRequest
+	}
+
+	logger.OSPMLogger.Errorln(
+		fmt.Sprintf(
+			"failed to process request. Path: %s, client ip: %s, error: %+v",
+			context.Path(), middleware.ClientIP(context), err))
+
+	return context.Status(responseCode).JSON(models.APIError{
+		Error:   err.Error(),
+		Message: "failed to process the request",
+	})
+}
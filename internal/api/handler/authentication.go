@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"ospm/internal/api/middleware"
+	"ospm/internal/models"
+	"ospm/internal/service/authentication"
+	"ospm/internal/service/subscriber"
+
+	// This line is being used by swagger auto-documenting
+	_ "ospm/docs/api"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary 	Subscriber login
+// @Description Verifies the subscriber credentials and returns a short lived access token and a refresh token
+// @Tags 		Authentication
+// @Accept  	json
+// @Produce  	json
+// @Param 		body body models.LoginRequest true "Subscriber Credentials"
+// @Success 	200 {object} models.TokenPair "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	401 {object} models.APIError "Unauthorized"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/auth/login [post]
+func Login(context *fiber.Ctx) error {
+	var loginRequest models.LoginRequest
+
+	if err := context.BodyParser(&loginRequest); err != nil || loginRequest.Username == "" || loginRequest.Password == "" {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   fiber.ErrBadRequest.Error(),
+			Message: "username and password must be provided",
+		})
+	}
+
+	tokens, err := authentication.Login(loginRequest.Username, loginRequest.Password, middleware.ClientIP(context), context.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		if errors.Is(err, subscriber.ErrInvalidCredentials) {
+			return context.Status(fiber.StatusUnauthorized).JSON(models.APIError{
+				Error:   fiber.ErrUnauthorized.Error(),
+				Message: err.Error(),
+			})
+		}
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to login",
+		})
+	}
+
+	return context.Status(200).JSON(tokens)
+}
+
+// @Summary 	Refresh tokens
+// @Description Rotates the given refresh token and returns a new pair of tokens. Each refresh token can be used once
+// @Tags 		Authentication
+// @Accept  	json
+// @Produce  	json
+// @Param 		body body models.RefreshTokenRequest true "Refresh Token"
+// @Success 	200 {object} models.TokenPair "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	401 {object} models.APIError "Unauthorized"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/auth/refresh [post]
+func RefreshToken(context *fiber.Ctx) error {
+	var refreshRequest models.RefreshTokenRequest
+
+	if err := context.BodyParser(&refreshRequest); err != nil || refreshRequest.RefreshToken == "" {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   fiber.ErrBadRequest.Error(),
+			Message: "refresh token must be provided",
+		})
+	}
+
+	tokens, err := authentication.Refresh(refreshRequest.RefreshToken)
+	if err != nil {
+		if errors.Is(err, authentication.ErrInvalidToken) || errors.Is(err, authentication.ErrSessionRevoked) {
+			return context.Status(fiber.StatusUnauthorized).JSON(models.APIError{
+				Error:   fiber.ErrUnauthorized.Error(),
+				Message: err.Error(),
+			})
+		}
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to refresh the tokens",
+		})
+	}
+
+	return context.Status(200).JSON(tokens)
+}
+
+// @Summary 	Revoke tokens
+// @Description Revokes the session of the given access or refresh token. The other tokens of the session become invalid too
+// @Tags 		Authentication
+// @Accept  	json
+// @Param 		body body models.RevokeTokenRequest true "Access or Refresh Token"
+// @Success 	204 "No Content"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	401 {object} models.APIError "Unauthorized"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/auth/revoke [post]
+func RevokeToken(context *fiber.Ctx) error {
+	var revokeRequest models.RevokeTokenRequest
+
+	if err := context.BodyParser(&revokeRequest); err != nil || revokeRequest.Token == "" {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   fiber.ErrBadRequest.Error(),
+			Message: "token must be provided",
+		})
+	}
+
+	if err := authentication.Revoke(revokeRequest.Token); err != nil {
+		if errors.Is(err, authentication.ErrInvalidToken) {
+			return context.Status(fiber.StatusUnauthorized).JSON(models.APIError{
+				Error:   fiber.ErrUnauthorized.Error(),
+				Message: err.Error(),
+			})
+		}
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to revoke the token",
+		})
+	}
+
+	return context.SendStatus(204)
+}
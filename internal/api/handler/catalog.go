@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/api/middleware"
+	"ospm/internal/models"
+	"ospm/internal/service/catalog"
+	"ospm/internal/service/logger"
+	"strconv"
+	"time"
+
+	// This line is being used by swagger auto-documenting
+	_ "ospm/docs/api"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// @Summary 	List product offerings
+// @Description Returns the product offerings that match the given filters. The total number of matches is returned in X-Total-Count header
+// @Tags 		Catalog
+// @Produce  	json
+// @Param 		name query string false "Exact name of the offering"
+// @Param 		lifecycleStatus query string false "Lifecycle status: draft/active/retired"
+// @Param 		specificationId query string false "ID of the product specification"
+// @Param 		validAt query string false "Only the offerings valid at the given time in RFC3339 format"
+// @Param 		offset query int false "Number of items to skip"
+// @Param 		limit query int false "Maximum number of items to return (Default: 100, Max: 1000)"
+// @Success 	200 {array} models.ProductOfferingResource "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/catalog/productOffering [get]
+func GetProductOfferingList(context *fiber.Ctx) error {
+	filter, err := catalogFilter(context)
+	if err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "invalid filter",
+		})
+	}
+
+	offerings, total, err := catalog.ListOfferings(filter)
+	if err != nil {
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the product offerings",
+		})
+	}
+
+	context.Set("X-Total-Count", strconv.FormatInt(total, 10))
+	context.Set("X-Result-Count", strconv.Itoa(len(offerings)))
+	return context.Status(200).JSON(offerings)
+}
+
+// @Summary 	Get product offering
+// @Description Retrieves a product offering by its ID
+// @Tags 		Catalog
+// @Produce  	json
+// @Param 		product_offering_id path string true "Product Offering ID"
+// @Success 	200 {object} models.ProductOfferingResource "Successful response"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/catalog/productOffering/{product_offering_id} [get]
+func GetProductOffering(context *fiber.Ctx) error {
+	offering, err := catalog.OfferingDetail(context.Params("product_offering_id"))
+	if err != nil {
+		return catalogError(context, err, "failed to load the product offering")
+	}
+
+	return context.Status(200).JSON(offering)
+}
+
+// @Summary 	Add product offering
+// @Description Adds a new product offering. It is created in draft status unless another status is given. The referenced product specification must exist and an active offering needs an active specification
+// @Tags 		Catalog
+// @Accept  	json
+// @Produce  	json
+// @Param 		body body models.ProductOfferingRequest true "Product offering"
+// @Success 	201 {object} models.ProductOfferingResource "Successfully created"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/catalog/productOffering [post]
+func AddProductOffering(context *fiber.Ctx) error {
+	var request models.ProductOfferingRequest
+	if err := context.BodyParser(&request); err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	offering, err := catalog.NewOffering(request, middleware.Actor(context))
+	if err != nil {
+		return catalogError(context, err, "failed to add the product offering")
+	}
+
+	context.Location(offering.Href)
+	return context.Status(201).JSON(offering)
+}
+
+// @Summary 	Update product offering
+// @Description Partially updates a product offering. Lifecycle status can only move forward: draft -> active -> retired
+// @Tags 		Catalog
+// @Accept  	json
+// @Produce  	json
+// @Param 		product_offering_id path string true "Product Offering ID"
+// @Param 		body body models.ProductOfferingRequest true "Fields to update"
+// @Success 	200 {object} models.ProductOfferingResource "Successfully updated"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "Invalid lifecycle status transition"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/catalog/productOffering/{product_offering_id} [patch]
+func UpdateProductOffering(context *fiber.Ctx) error {
+	var request models.ProductOfferingRequest
+	if err := context.BodyParser(&request); err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	offering, err := catalog.UpdateOffering(context.Params("product_offering_id"), request, middleware.Actor(context))
+	if err != nil {
+		return catalogError(context, err, "failed to update the product offering")
+	}
+
+	return context.Status(200).JSON(offering)
+}
+
+// @Summary 	Delete product offering
+// @Description Deletes a product offering in soft mode
+// @Tags 		Catalog
+// @Produce  	json
+// @Param 		product_offering_id path string true "Product Offering ID"
+// @Success 	204 "No Content"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/catalog/productOffering/{product_offering_id} [delete]
+func DeleteProductOffering(context *fiber.Ctx) error {
+	if err := catalog.DeleteOffering(context.Params("product_offering_id"), middleware.Actor(context)); err != nil {
+		return catalogError(context, err, "failed to delete the product offering")
+	}
+
+	return context.SendStatus(204)
+}
+
+// @Summary 	List product specifications
+// @Description Returns the product offering specifications that match the given filters. The total number of matches is returned in X-Total-Count header
+// @Tags 		Catalog
+// @Produce  	json
+// @Param 		name query string false "Exact name of the specification"
+// @Param 		lifecycleStatus query string false "Lifecycle status: draft/active/retired"
+// @Param 		productSpecificationType query string false "Type of the specification: product/service"
+// @Param 		validAt query string false "Only the specifications valid at the given time in RFC3339 format"
+// @Param 		offset query int false "Number of items to skip"
+// @Param 		limit query int false "Maximum number of items to return (Default: 100, Max: 1000)"
+// @Success 	200 {array} models.ProductSpecificationResource "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/catalog/productSpecification [get]
+func GetProductSpecificationList(context *fiber.Ctx) error {
+	filter, err := catalogFilter(context)
+	if err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "invalid filter",
+		})
+	}
+
+	specifications, total, err := catalog.ListSpecifications(filter)
+	if err != nil {
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the product specifications",
+		})
+	}
+
+	context.Set("X-Total-Count", strconv.FormatInt(total, 10))
+	context.Set("X-Result-Count", strconv.Itoa(len(specifications)))
+	return context.Status(200).JSON(specifications)
+}
+
+// @Summary 	Get product specification
+// @Description Retrieves a product offering specification by its ID
+// @Tags 		Catalog
+// @Produce  	json
+// @Param 		product_specification_id path string true "Product Specification ID"
+// @Success 	200 {object} models.ProductSpecificationResource "Successful response"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/catalog/productSpecification/{product_specification_id} [get]
+func GetProductSpecification(context *fiber.Ctx) error {
+	specification, err := catalog.SpecificationDetail(context.Params("product_specification_id"))
+	if err != nil {
+		return catalogError(context, err, "failed to load the product specification")
+	}
+
+	return context.Status(200).JSON(specification)
+}
+
+// @Summary 	Add product specification
+// @Description Adds a new product offering specification. It is created in draft status unless another status is given
+// @Tags 		Catalog
+// @Accept  	json
+// @Produce  	json
+// @Param 		body body models.ProductSpecificationRequest true "Product specification"
+// @Success 	201 {object} models.ProductSpecificationResource "Successfully created"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/catalog/productSpecification [post]
+func AddProductSpecification(context *fiber.Ctx) error {
+	var request models.ProductSpecificationRequest
+	if err := context.BodyParser(&request); err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	specification, err := catalog.NewSpecification(request, middleware.Actor(context))
+	if err != nil {
+		return catalogError(context, err, "failed to add the product specification")
+	}
+
+	context.Location(specification.Href)
+	return context.Status(201).JSON(specification)
+}
+
+// @Summary 	Update product specification
+// @Description Partially updates a product offering specification. A specification can not be retired while it is used by offerings that are not retired
+// @Tags 		Catalog
+// @Accept  	json
+// @Produce  	json
+// @Param 		product_specification_id path string true "Product Specification ID"
+// @Param 		body body models.ProductSpecificationRequest true "Fields to update"
+// @Success 	200 {object} models.ProductSpecificationResource "Successfully updated"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "Invalid lifecycle status transition or the specification is in use"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/catalog/productSpecification/{product_specification_id} [patch]
+func UpdateProductSpecification(context *fiber.Ctx) error {
+	var request models.ProductSpecificationRequest
+	if err := context.BodyParser(&request); err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	specification, err := catalog.UpdateSpecification(context.Params("product_specification_id"), request, middleware.Actor(context))
+	if err != nil {
+		return catalogError(context, err, "failed to update the product specification")
+	}
+
+	return context.Status(200).JSON(specification)
+}
+
+// @Summary 	Delete product specification
+// @Description Deletes a product offering specification in soft mode. Specifications that are referenced by offerings can not be deleted
+// @Tags 		Catalog
+// @Produce  	json
+// @Param 		product_specification_id path string true "Product Specification ID"
+// @Success 	204 "No Content"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "The specification is in use"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/catalog/productSpecification/{product_specification_id} [delete]
+func DeleteProductSpecification(context *fiber.Ctx) error {
+	if err := catalog.DeleteSpecification(context.Params("product_specification_id"), middleware.Actor(context)); err != nil {
+		return catalogError(context, err, "failed to delete the product specification")
+	}
+
+	return context.SendStatus(204)
+}
+
+// catalogFilter parses the filters of the catalog listing endpoints
+func catalogFilter(context *fiber.Ctx) (models.CatalogFilter, error) {
+	filter := models.CatalogFilter{
+		Name:            context.Query("name"),
+		LifecycleStatus: context.Query("lifecycleStatus"),
+		Type:            context.Query("productSpecificationType"),
+		SpecificationID: context.Query("specificationId"),
+		Offset:          context.QueryInt("offset", 0),
+		Limit:           context.QueryInt("limit", catalog.DefaultListLimit),
+	}
+
+	if context.Query("validAt") != "" {
+		validAt, err := time.Parse(time.RFC3339, context.Query("validAt"))
+		if err != nil {
+			return filter, err
+		}
+		filter.ValidAt = &validAt
+	}
+
+	return filter, nil
+}
+
+// catalogError maps the errors of the catalog service to the response
+func catalogError(context *fiber.Ctx, err error, message string) error {
+	responseCode := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		responseCode = fiber.StatusNotFound
+	case errors.Is(err, catalog.ErrInvalidRequest), errors.Is(err, catalog.ErrInvalidReference), errors.Is(err, gorm.ErrDuplicatedKey):
+		responseCode = fiber.StatusBadRequest
+	case errors.Is(err, catalog.ErrInvalidLifecycleTransition), errors.Is(err, catalog.ErrSpecificationInUse):
+		responseCode = fiber.StatusConflict
+	}
+
+	if responseCode != fiber.StatusNotFound {
+		logger.OSPMLogger.Errorln(
+			fmt.Sprintf(
+				"failed to process request. Path: %s, client ip: %s, error: %+v",
+				context.Path(), middleware.ClientIP(context), err))
+	}
+
+	return context.Status(responseCode).JSON(models.APIError{
+		Error:   err.Error(),
+		Message: message,
+	})
+}
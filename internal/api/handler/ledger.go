@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/api/middleware"
+	"ospm/internal/models"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/ledger"
+	"ospm/internal/service/logger"
+
+	// This line is being used by swagger auto-documenting
+	_ "ospm/docs/api"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// @Summary 	Credit the organization balance
+// @Description Tops up the balance of an organization. Retrying with the same request key does not change the balance again
+// @Tags 		Balance
+// @Accept  	json
+// @Produce  	json
+// @Param 		organization_id path string true "Organization ID"
+// @Param 		body body models.BalanceOperationRequest true "Credit details"
+// @Success 	200 {object} models.LedgerTransactionResponse "The request key is already used for the same credit"
+// @Success 	201 {object} models.LedgerTransactionResponse "Successfully credited"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "Request key is used for another operation"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/balance/{organization_id}/credit [post]
+func CreditOrganizationBalance(context *fiber.Ctx) error {
+	return postBalanceOperation(context, ledger.Credit)
+}
+
+// @Summary 	Debit the organization balance
+// @Description Charges the balance of an organization. Debits that would take the balance over the negative balance threshold are rejected
+// @Tags 		Balance
+// @Accept  	json
+// @Produce  	json
+// @Param 		organization_id path string true "Organization ID"
+// @Param 		body body models.BalanceOperationRequest true "Debit details"
+// @Success 	200 {object} models.LedgerTransactionResponse "The request key is already used for the same debit"
+// @Success 	201 {object} models.LedgerTransactionResponse "Successfully debited"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "Request key is used for another operation"
+// @Failure 	422 {object} models.APIError "Insufficient balance"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/balance/{organization_id}/debit [post]
+func DebitOrganizationBalance(context *fiber.Ctx) error {
+	return postBalanceOperation(context, ledger.Debit)
+}
+
+// @Summary 	Adjust the organization balance
+// @Description Corrects the balance of an organization by a signed amount. The negative balance threshold is not enforced for adjustments
+// @Tags 		Balance
+// @Accept  	json
+// @Produce  	json
+// @Param 		organization_id path string true "Organization ID"
+// @Param 		body body models.BalanceOperationRequest true "Adjustment details"
+// @Success 	200 {object} models.LedgerTransactionResponse "The request key is already used for the same adjustment"
+// @Success 	201 {object} models.LedgerTransactionResponse "Successfully adjusted"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "Request key is used for another operation"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/balance/{organization_id}/adjustment [post]
+func AdjustOrganizationBalance(context *fiber.Ctx) error {
+	return postBalanceOperation(context, ledger.Adjust)
+}
+
+// @Summary 	Reverse a ledger transaction
+// @Description Cancels a ledger transaction of an organization by posting its opposite. Each transaction can only be reversed once. Reversals that lower the balance are rejected if they would take it over the negative balance threshold
+// @Tags 		Balance
+// @Accept  	json
+// @Produce  	json
+// @Param 		organization_id path string true "Organization ID"
+// @Param 		transaction_id path string true "Ledger Transaction ID"
+// @Param 		body body models.BalanceOperationRequest true "Reversal details. The amount is ignored"
+// @Success 	200 {object} models.LedgerTransactionResponse "The request key is already used for the same reversal"
+// @Success 	201 {object} models.LedgerTransactionResponse "Successfully reversed"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "Request key is used for another operation or the transaction is not reversible"
+// @Failure 	422 {object} models.APIError "Insufficient balance"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/balance/{organization_id}/reversal/{transaction_id} [post]
+func ReverseLedgerTransaction(context *fiber.Ctx) error {
+	transactionID := context.Params("transaction_id")
+
+	return postBalanceOperation(context, func(organizationID string, request models.BalanceOperationRequest, actor audit.Actor) (models.LedgerTransaction, bool, error) {
+		return ledger.Reverse(organizationID, transactionID, request, actor)
+	})
+}
+
+// @Summary 	List ledger transactions of an organization
+// @Description Returns all of the ledger transactions of an organization, the latest first
+// @Tags 		Balance
+// @Produce  	json
+// @Param 		organization_id path string true "Organization ID"
+// @Success 	200 {array} models.LedgerTransactionResponse "Successful response"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/balance/{organization_id}/transactions [get]
+func GetLedgerTransactions(context *fiber.Ctx) error {
+	transactions, err := ledger.Transactions(context.Params("organization_id"))
+	if err != nil {
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the ledger transactions",
+		})
+	}
+
+	return context.Status(200).JSON(transactions)
+}
+
+// @Summary 	Reconcile the organization balance
+// @Description Compares the stored balance of an organization with the sum of its ledger entries
+// @Tags 		Balance
+// @Produce  	json
+// @Param 		organization_id path string true "Organization ID"
+// @Success 	200 {object} models.BalanceReconciliation "Successful response"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/balance/{organization_id}/reconciliation [get]
+func ReconcileOrganizationBalance(context *fiber.Ctx) error {
+	reconciliation, err := ledger.Reconcile(context.Params("organization_id"))
+	if err != nil {
+		responseCode := fiber.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			responseCode = fiber.StatusNotFound
+		}
+		return context.Status(responseCode).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to reconcile the organization balance",
+		})
+	}
+
+	return context.Status(200).JSON(reconciliation)
+}
+
+// postBalanceOperation parses the request and maps the result of the given ledger operation to the response
+func postBalanceOperation(
+	context *fiber.Ctx,
+	operation func(organizationID string, request models.BalanceOperationRequest, actor audit.Actor) (models.LedgerTransaction, bool, error),
+) error {
+	var request models.BalanceOperationRequest
+	if err := context.BodyParser(&request); err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	transaction, created, err := operation(context.Params("organization_id"), request, middleware.Actor(context))
+	if err != nil {
+		responseCode := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			responseCode = fiber.StatusNotFound
+		case errors.Is(err, ledger.ErrInvalidAmount), errors.Is(err, ledger.ErrMissingRequestKey), errors.Is(err, models.ErrCurrencyMismatch):
+			responseCode = fiber.StatusBadRequest
+		case errors.Is(err, ledger.ErrRequestKeyConflict), errors.Is(err, ledger.ErrNotReversible):
+			responseCode = fiber.StatusConflict
+		case errors.Is(err, ledger.ErrNegativeBalanceThreshold):
+			responseCode = fiber.StatusUnprocessableEntity
+		}
+		logger.OSPMLogger.Errorln(
+			fmt.Sprintf(
+				"failed to process request. Path: %s, client ip: %s, error: %+v",
+				context.Path(), middleware.ClientIP(context), err))
+		return context.Status(responseCode).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to change the organization balance",
+		})
+	}
+
+	responseCode := fiber.StatusOK
+	if created {
+		responseCode = fiber.StatusCreated
+	}
+
+	return context.Status(responseCode).JSON(ledger.Clean(&transaction))
+}
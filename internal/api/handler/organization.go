@@ -2,55 +2,129 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"ospm/internal/api/middleware"
 	"ospm/internal/models"
+	"ospm/internal/service/logger"
 	"ospm/internal/service/organization"
+	"strconv"
+	"strings"
+	"time"
 
 	// This line is being used by swagger auto-documenting
 	_ "ospm/docs/api"
 
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
-// @Summary 	List all organizations
+// OrganizationHandler handles the organization requests with its service
+type OrganizationHandler struct {
+	service *organization.Service
+}
+
+// NewOrganizationHandler returns a handler of the organization requests
+func NewOrganizationHandler(service *organization.Service) *OrganizationHandler {
+	return &OrganizationHandler{service: service}
+}
+
+// @Summary 	List organizations
 //
 //	@Description \
-//				Retrieves a list of all organizations available \
-//				in the system. each record is summarized. \
-//				Soft deleteds will not be listed by default. \
-//				To include the soft deleted ones. set list_all=true as query paramater
+//				Retrieves a page of the organizations that match the filters, sorted by the given key. \
+//				Soft deleteds will not be listed by default, set deleted=include or deleted=only to list them. \
+//				The next page is requested by passing the next_cursor of the page as cursor with the same filters and sort. \
+//				next_cursor is empty on the last page and an empty page is not an error
 //
 // @Tags 		Organization
 // @Produce 	json
-// @Param 		list_all query string false "includes soft deleted organizations (Optional)"
-// @Success 	200 {array} models.OrganizationShortInfo "Successful Response"
+// @Param 		limit query int false "Maximum number of organizations in the page (Default: 100, Max: 1000)"
+// @Param 		cursor query string false "next_cursor of the previous page"
+// @Param 		sort query string false "Sort key: name, created_at or balance. A - prefix sorts in descending order, e.g. -balance (Default: created_at)"
+// @Param 		name_prefix query string false "Only the organizations whose name starts with the given value, case-insensitive"
+// @Param 		owner_type query string false "Only the organizations whose owner is of the given type: legal, individual"
+// @Param 		deleted query string false "Deleted state: exclude, include or only (Default: exclude)"
+// @Param 		list_all query string false "Deprecated, true is the same as deleted=include"
+// @Param 		created_from query string false "Only the organizations created at or after the given time in RFC3339 format"
+// @Param 		created_to query string false "Only the organizations created at or before the given time in RFC3339 format"
+// @Param 		balance_min query string false "Only the organizations whose balance amount is at least the given value"
+// @Param 		balance_max query string false "Only the organizations whose balance amount is at most the given value"
+// @Success 	200 {object} models.OrganizationPage "Successful Response"
+// @Failure 	400 {object} models.APIError "Bad Request"
 // @Failure 	500 {object} models.APIError "Internal Server Error"
 // @Router 		/organization [get]
-func GetOrganizationList(context *fiber.Ctx) error {
-	var organizationList []models.OrganizationShortInfo
-	var err error
+func (h *OrganizationHandler) GetOrganizationList(context *fiber.Ctx) error {
+	request, err := organizationListRequest(context)
+	if err == nil {
+		var page models.OrganizationPage
+		if page, err = h.service.List(request); err == nil {
+			return context.Status(fiber.StatusOK).JSON(page)
+		}
+	}
 
-	if context.Query("list_all") == "true" {
-		organizationList, err = organization.ListAll()
-	} else {
-		organizationList, err = organization.List()
+	responseCode := fiber.StatusInternalServerError
+	if errors.Is(err, organization.ErrInvalidListRequest) {
+		responseCode = fiber.StatusBadRequest
 	}
+	logger.OSPMLogger.Errorln(
+		fmt.Sprintf(
+			"failed to process request. Path: %s, client ip: %s, error: %+v",
+			context.Path(), middleware.ClientIP(context), err))
+	return context.Status(responseCode).JSON(models.APIError{
+		Error:   err.Error(),
+		Message: "failed to list the organizations",
+	})
+}
 
-	if err != nil {
-		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
-			Error:   fiber.ErrInternalServerError.Error(),
-			Message: err.Error(),
-		})
+// organizationListRequest reads the filters, the sort and the page of the organization list from the query
+func organizationListRequest(context *fiber.Ctx) (models.OrganizationListRequest, error) {
+	request := models.OrganizationListRequest{
+		Filter: models.OrganizationFilter{
+			NamePrefix: context.Query("name_prefix"),
+			OwnerType:  context.Query("owner_type"),
+			Deleted:    context.Query("deleted"),
+		},
+		Sort:   context.Query("sort"),
+		Cursor: context.Query("cursor"),
 	}
 
-	if len(organizationList) == 0 {
-		return context.Status(fiber.ErrNotFound.Code).JSON(models.APIError{
-			Error:   fiber.ErrNotFound.Error(),
-			Message: fiber.ErrNotFound.Message,
-		})
+	if context.Query("limit") != "" {
+		limit, err := strconv.Atoi(context.Query("limit"))
+		if err != nil {
+			return request, fmt.Errorf("%w: limit should be an integer, %s", organization.ErrInvalidListRequest, err)
+		}
+		request.Limit = limit
+	}
+
+	// list_all is kept for the existing clients
+	if request.Filter.Deleted == "" && context.Query("list_all") == "true" {
+		request.Filter.Deleted = models.OrganizationDeletedInclude
+	}
+
+	for name, target := range map[string]**time.Time{"created_from": &request.Filter.CreatedFrom, "created_to": &request.Filter.CreatedTo} {
+		if context.Query(name) == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, context.Query(name))
+		if err != nil {
+			return request, fmt.Errorf("%w: %s should be in RFC3339 format, %s", organization.ErrInvalidListRequest, name, err)
+		}
+		*target = &parsed
 	}
 
-	return context.Status(200).JSON(organizationList)
+	for name, target := range map[string]**models.Decimal{"balance_min": &request.Filter.BalanceMin, "balance_max": &request.Filter.BalanceMax} {
+		if context.Query(name) == "" {
+			continue
+		}
+		parsed, err := models.ParseDecimal(context.Query(name))
+		if err != nil {
+			return request, fmt.Errorf("%w: %s should be a decimal number, %s", organization.ErrInvalidListRequest, name, err)
+		}
+		*target = &parsed
+	}
+
+	return request, nil
 }
 
 // @Summary 	Get organization profile by name or ID
@@ -64,7 +138,7 @@ func GetOrganizationList(context *fiber.Ctx) error {
 // @Failure 	404 {object} models.APIError "Organization Not Found"
 // @Failure 	500 {object} models.APIError "Internal Server Error"
 // @Router 		/organizations/profile [get]
-func GetOrganizationProfile(context *fiber.Ctx) error {
+func (h *OrganizationHandler) GetOrganizationProfile(context *fiber.Ctx) error {
 	organizationName := context.Query("name")
 	organizationID := context.Query("id")
 
@@ -75,7 +149,7 @@ func GetOrganizationProfile(context *fiber.Ctx) error {
 		})
 	}
 
-	organizationDetails, err := organization.Details(organizationName, organizationID)
+	organizationDetails, err := h.service.Details(organizationName, organizationID)
 	if err != nil {
 		status := fiber.StatusInternalServerError
 		message := fiber.ErrInternalServerError.Message
@@ -112,7 +186,7 @@ func GetOrganizationProfile(context *fiber.Ctx) error {
 // @Failure 	400 {object} models.APIError "Bad Request"
 // @Failure 	500 {object} models.APIError "Internal Server Error"
 // @Router 		/organization [post]
-func AddNewOrganization(context *fiber.Ctx) error {
+func (h *OrganizationHandler) AddNewOrganization(context *fiber.Ctx) error {
 	newOrganization := models.Organization{}
 	err := context.BodyParser(&newOrganization)
 	if err != nil {
@@ -122,7 +196,7 @@ func AddNewOrganization(context *fiber.Ctx) error {
 		})
 	}
 
-	newOrganizationID, err := organization.New(newOrganization)
+	newOrganizationID, err := h.service.New(newOrganization, middleware.Actor(context))
 	if err != nil {
 		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
 			Error:   fiber.ErrInternalServerError.Error(),
@@ -156,7 +230,7 @@ func AddNewOrganization(context *fiber.Ctx) error {
 // @Failure 	400 {object} models.APIError "Bad Request"
 // @Failure 	500 {object} models.APIError "Internal Server Error"
 // @Router 		/organization [delete]
-func DeleteOrganization(context *fiber.Ctx) error {
+func (h *OrganizationHandler) DeleteOrganization(context *fiber.Ctx) error {
 	organizationName := context.Query("name")
 	organizationID := context.Query("id")
 	deletionMode := context.Query("mode")
@@ -170,14 +244,14 @@ func DeleteOrganization(context *fiber.Ctx) error {
 
 	switch deletionMode {
 	case "soft":
-		if err := organization.SoftDelete(organizationID, organizationName); err != nil {
+		if err := h.service.SoftDelete(organizationID, organizationName, middleware.Actor(context)); err != nil {
 			return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
 				Error:   err.Error(),
 				Message: "failed to delete the organization",
 			})
 		}
 	case "hard":
-		if err := organization.HardDelete(organizationID, organizationName); err != nil {
+		if err := h.service.HardDelete(organizationID, organizationName, middleware.Actor(context)); err != nil {
 			return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
 				Error:   err.Error(),
 				Message: "failed to delete the organization",
@@ -213,7 +287,7 @@ func DeleteOrganization(context *fiber.Ctx) error {
 // @Failure 	400 {object} models.APIError "Bad Request"
 // @Failure 	500 {object} models.APIError "Internal Server Error"
 // @Router 		/organization/recover/profile [patch]
-func RecoverSoftDeletedOrganization(context *fiber.Ctx) error {
+func (h *OrganizationHandler) RecoverSoftDeletedOrganization(context *fiber.Ctx) error {
 	organizationName := context.Query("name")
 	organizationID := context.Query("id")
 
@@ -224,7 +298,7 @@ func RecoverSoftDeletedOrganization(context *fiber.Ctx) error {
 		})
 	}
 
-	if err := organization.Recover(organizationID, organizationName); err != nil {
+	if err := h.service.Recover(organizationID, organizationName, middleware.Actor(context)); err != nil {
 		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
 			Error:   err.Error(),
 			Message: "failed to delete the organization",
@@ -238,3 +312,124 @@ func RecoverSoftDeletedOrganization(context *fiber.Ctx) error {
 
 	return context.Status(200).JSON(responseMessage)
 }
+
+// @Summary 	Update the organization profile
+//
+//	@Description \
+//		Partially updates the details and the owner of an organization identified by its name or ID. \
+//		The body is a JSON Merge Patch (RFC 7396), only the given fields are changed and null clears the field. \
+//		The balance policy can not be changed by this endpoint, use /organization/profile/balance_policy instead
+//
+// @Tags 		Organization
+// @Accept 		json
+// @Produce 	json
+// @Param 		id query string false "Organization ID"
+// @Param 		name query string false "Organization Name"
+// @Param 		body body models.OrganizationProfilePatch true "JSON Merge Patch of the profile"
+// @Success 	200 {object} models.OrganizationResponse "Successful Response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Organization Not Found"
+// @Failure 	409 {object} models.APIError "The name, email or mobile is used by another organization"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/organization/profile [patch]
+func (h *OrganizationHandler) UpdateOrganizationProfile(context *fiber.Ctx) error {
+	organizationName := context.Query("name")
+	organizationID := context.Query("id")
+
+	if organizationID == "" && organizationName == "" {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   fiber.ErrBadRequest.Error(),
+			Message: "either organization ID or name must be provided",
+		})
+	}
+
+	contentType := string(context.Request().Header.ContentType())
+	if contentType != "" && !strings.HasPrefix(contentType, "application/merge-patch+json") && !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		return context.Status(fiber.StatusUnsupportedMediaType).JSON(models.APIError{
+			Error:   fiber.ErrUnsupportedMediaType.Error(),
+			Message: "the profile patch should be sent as application/merge-patch+json",
+		})
+	}
+
+	organizationDetails, err := h.service.UpdateProfile(organizationID, organizationName, context.Body(), middleware.Actor(context))
+	if err != nil {
+		responseCode := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			responseCode = fiber.StatusNotFound
+		case errors.Is(err, organization.ErrInvalidPatch), errors.Is(err, organization.ErrInvalidProfile):
+			responseCode = fiber.StatusBadRequest
+		case errors.Is(err, organization.ErrConflict):
+			responseCode = fiber.StatusConflict
+		}
+		logger.OSPMLogger.Errorln(
+			fmt.Sprintf(
+				"failed to process request. Path: %s, client ip: %s, error: %+v",
+				context.Path(), middleware.ClientIP(context), err))
+		return context.Status(responseCode).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to update the organization profile",
+		})
+	}
+
+	return context.Status(fiber.StatusOK).JSON(organizationDetails)
+}
+
+// @Summary 	Update the organization balance policy
+//
+//	@Description \
+//		Changes whether an organization is allowed to have negative balance and how much. \
+//		This is a privileged operation which is only permitted for the local clients by the built-in route policy
+//
+// @Tags 		Organization
+// @Accept 		json
+// @Produce 	json
+// @Param 		id query string false "Organization ID"
+// @Param 		name query string false "Organization Name"
+// @Param 		body body models.BalancePolicyRequest true "Balance policy"
+// @Success 	200 {object} models.OrganizationResponse "Successful Response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	403 {object} models.APIError "Denied by the route policy"
+// @Failure 	404 {object} models.APIError "Organization Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/organization/profile/balance_policy [patch]
+func (h *OrganizationHandler) UpdateOrganizationBalancePolicy(context *fiber.Ctx) error {
+	organizationName := context.Query("name")
+	organizationID := context.Query("id")
+
+	if organizationID == "" && organizationName == "" {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   fiber.ErrBadRequest.Error(),
+			Message: "either organization ID or name must be provided",
+		})
+	}
+
+	var request models.BalancePolicyRequest
+	if err := context.BodyParser(&request); err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	organizationDetails, err := h.service.UpdateBalancePolicy(organizationID, organizationName, request, middleware.Actor(context))
+	if err != nil {
+		responseCode := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			responseCode = fiber.StatusNotFound
+		case errors.Is(err, organization.ErrInvalidBalancePolicy):
+			responseCode = fiber.StatusBadRequest
+		}
+		logger.OSPMLogger.Errorln(
+			fmt.Sprintf(
+				"failed to process request. Path: %s, client ip: %s, error: %+v",
+				context.Path(), middleware.ClientIP(context), err))
+		return context.Status(responseCode).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to update the organization balance policy",
+		})
+	}
+
+	return context.Status(fiber.StatusOK).JSON(organizationDetails)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"io"
+	"net/http/httptest"
+	"ospm/config"
+	"ospm/internal/models"
+	"ospm/internal/repository/memory"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/organization"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// newOrganizationTestApp returns an app that serves the organization routes on an in-memory repository with the owl organization
+func newOrganizationTestApp(t *testing.T) *fiber.App {
+	service := organization.NewService(memory.NewOrganizationRepository())
+	_, err := service.New(models.Organization{
+		Details: models.OrganizationDetails{Name: "owl", Email: "info@owl.com", Mobile: "09120000000"},
+		Owner:   models.OrganizationOwner{Type: "legal", Name: "owl owner", Email: "owner@owl.com", Mobile: "09120000001", LegalNationalID: "1234567890"},
+	}, audit.Actor{})
+	assert.NoError(t, err)
+
+	organizationHandler := NewOrganizationHandler(service)
+	// the in-memory repository keeps the values after the request, so they must not be reused by fiber
+	app := fiber.New(fiber.Config{Immutable: true})
+	app.Get("/organization", organizationHandler.GetOrganizationList)
+	app.Get("/organization/profile", organizationHandler.GetOrganizationProfile)
+	app.Post("/organization", organizationHandler.AddNewOrganization)
+	app.Delete("/organization", organizationHandler.DeleteOrganization)
+	app.Patch("/organization/profile", organizationHandler.UpdateOrganizationProfile)
+	return app
+}
+
+func TestOrganizationHandler(t *testing.T) {
+	config.LoadOSPMConfigs()
+	logger.InitLogger()
+
+	type testCase struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}
+
+	testCases := []testCase{
+		{
+			name:           "the profile of an existing organization is requested by its name. In this case, its profile should be returned",
+			method:         fiber.MethodGet,
+			target:         "/organization/profile?name=owl",
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   `"email":"owner@owl.com"`,
+		},
+		{
+			name:           "the profile of an organization that does not exist is requested. In this case, it should not be found",
+			method:         fiber.MethodGet,
+			target:         "/organization/profile?name=ario",
+			expectedStatus: fiber.StatusNotFound,
+		},
+		{
+			name:   "a valid organization is added. In this case, it should be created",
+			method: fiber.MethodPost,
+			target: "/organization",
+			body: `{"organization_details":{"name":"ario","email":"info@ario.com","mobile":"09130000000"},
+				"organization_owner":{"Type":"individual","name":"ario owner","email":"owner@ario.com","mobile":"09130000001","legal_national_id":"0987654321"}}`,
+			expectedStatus: fiber.StatusCreated,
+			expectedBody:   "organization ario successfully added",
+		},
+		{
+			name:           "an organization without its owner is added. In this case, it should be rejected",
+			method:         fiber.MethodPost,
+			target:         "/organization",
+			body:           `{"organization_details":{"name":"ario"}}`,
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedBody:   "new organization details are wrong",
+		},
+		{
+			name:           "the organizations are listed by a name prefix in a different case. In this case, the page should have the matching organization",
+			method:         fiber.MethodGet,
+			target:         "/organization?name_prefix=OW&owner_type=legal&limit=1&sort=-name",
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   `"organization_name":"owl","owner_type":"legal"`,
+		},
+		{
+			name:           "the organizations are listed with an invalid balance range. In this case, it should be rejected",
+			method:         fiber.MethodGet,
+			target:         "/organization?balance_min=10&balance_max=abc",
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   "balance_max should be a decimal number",
+		},
+		{
+			name:           "the organizations are listed with a limit that is not an integer. In this case, it should be rejected",
+			method:         fiber.MethodGet,
+			target:         "/organization?limit=abc",
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   "limit should be an integer",
+		},
+		{
+			name:           "an organization is deleted without the deletion mode. In this case, it should be rejected",
+			method:         fiber.MethodDelete,
+			target:         "/organization?name=owl",
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "the owner email is patched to an invalid value. In this case, it should be rejected",
+			method:         fiber.MethodPatch,
+			target:         "/organization/profile?name=owl",
+			body:           `{"organization_owner":{"email":null}}`,
+			expectedStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			app := newOrganizationTestApp(t)
+
+			request := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			response, err := app.Test(request)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, response.StatusCode)
+
+			body, err := io.ReadAll(response.Body)
+			assert.NoError(t, err)
+			assert.Contains(t, string(body), tc.expectedBody)
+		})
+	}
+}
+
+func TestOrganizationHandlerSoftDelete(t *testing.T) {
+	config.LoadOSPMConfigs()
+	logger.InitLogger()
+
+	app := newOrganizationTestApp(t)
+
+	response, err := app.Test(httptest.NewRequest(fiber.MethodDelete, "/organization?name=owl&mode=soft", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+	// the soft deleted organizations are only listed with list_all or deleted, an empty page is not an error
+	response, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/organization", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, response.StatusCode)
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"items":[],"total":0,"next_cursor":""}`, string(body))
+
+	for _, target := range []string{"/organization?list_all=true", "/organization?deleted=only"} {
+		response, err = app.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+		body, err = io.ReadAll(response.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), `"total":1`)
+	}
+}
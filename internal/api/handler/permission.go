@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/api/middleware"
+	"ospm/internal/models"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/permission"
+
+	// This line is being used by swagger auto-documenting
+	_ "ospm/docs/api"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// @Summary 	Get the permission catalog
+// @Description Returns the version of the permission catalog and the definition of the permissions that can be given to the subscriber groups
+// @Tags 		Permission
+// @Produce  	json
+// @Success 	200 {object} models.PermissionCatalog "Successful response"
+// @Router 		/permission_catalog [get]
+func GetPermissionCatalog(context *fiber.Ctx) error {
+	return context.Status(200).JSON(middleware.Settings(context).Catalog.PermissionCatalog)
+}
+
+// @Summary 	Get a permission category
+// @Description Returns the definition of the permissions of the given category
+// @Tags 		Permission
+// @Produce  	json
+// @Param 		permission_category path string true "Permission Category"
+// @Success 	200 {object} models.PermissionCatalog "Successful response"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Router 		/permission_catalog/{permission_category} [get]
+func GetPermissionCategory(context *fiber.Ctx) error {
+	catalog := middleware.Settings(context).Catalog
+	category := context.Params("permission_category")
+
+	definitions := catalog.Category(category)
+	if len(definitions) == 0 {
+		return context.Status(fiber.ErrNotFound.Code).JSON(models.APIError{
+			Error:   "permission category " + category + " is not defined in the permission catalog",
+			Message: "failed to load the permission category",
+		})
+	}
+
+	return context.Status(200).JSON(models.PermissionCatalog{
+		Version:     catalog.Version,
+		Permissions: definitions,
+	})
+}
+
+// @Summary 	Evaluate the permissions of a subscriber
+// @Description Resolves the permissions of the subscriber through its subscriber group and decides on each of the given permissions. If the value of a permission is given, the group must have the same value, otherwise any value other than false or no is allowed. At most 100 permissions can be evaluated at once
+// @Tags 		Permission
+// @Accept  	json
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Param 		body body models.PermissionEvaluationRequest true "Permissions to evaluate"
+// @Success 	200 {object} models.PermissionEvaluationResponse "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/{subscriber_id}/permission/evaluate [post]
+func EvaluateSubscriberPermissions(context *fiber.Ctx) error {
+	var request models.PermissionEvaluationRequest
+	if err := context.BodyParser(&request); err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	result, err := permission.Evaluate(context.Params("subscriber_id"), request.Permissions)
+	if err != nil {
+		return permissionEvaluationError(context, err)
+	}
+
+	return context.Status(200).JSON(result)
+}
+
+// @Summary 	Evaluate a permission of a subscriber
+// @Description Decides whether the subscriber holds the permission. It is the single permission version of the evaluate endpoint
+// @Tags 		Permission
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Param 		permission_category path string true "Permission Category"
+// @Param 		permission_name path string true "Permission Name"
+// @Param 		value query string false "The value that the subscriber should have"
+// @Success 	200 {object} models.PermissionDecision "Successful response"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/{subscriber_id}/permission/{permission_category}/{permission_name} [get]
+func EvaluateSubscriberPermission(context *fiber.Ctx) error {
+	result, err := permission.Evaluate(context.Params("subscriber_id"), []models.PermissionAPI{{
+		PermissionCategory: context.Params("permission_category"),
+		PermissionName:     context.Params("permission_name"),
+		PermissionValue:    context.Query("value"),
+	}})
+	if err != nil {
+		return permissionEvaluationError(context, err)
+	}
+
+	return context.Status(200).JSON(result.Decisions[0])
+}
+
+func permissionEvaluationError(context *fiber.Ctx, err error) error {
+	responseCode := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		responseCode = fiber.StatusNotFound
+	case errors.Is(err, permission.ErrInvalidQuery):
+		responseCode = fiber.StatusBadRequest
+	}
+	logger.OSPMLogger.Errorln(
+		fmt.Sprintf(
+			"failed to process request. Path: %s, client ip: %s, error: %+v",
+			context.Path(), middleware.ClientIP(context), err))
+	return context.Status(responseCode).JSON(models.APIError{
+		Error:   err.Error(),
+		Message: "failed to evaluate the permissions",
+	})
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/api/middleware"
+	"ospm/internal/models"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/subscriber"
+
+	// This line is being used by swagger auto-documenting
+	_ "ospm/docs/api"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// @Summary 	List subscribers of an organization
+// @Description Returns a list of all subscribers within an organization. Soft deleteds will not be listed
+// @Tags 		Subscriber
+// @Produce  	json
+// @Param 		organization_id path string true "Organization ID"
+// @Success 	200 {array} models.SubscriberShortInfo "Successful response"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/list/organization/{organization_id} [get]
+func GetSubscriberList(context *fiber.Ctx) error {
+	organizationID := context.Params("organization_id")
+
+	subscriberList, err := subscriber.List(organizationID)
+	if err != nil {
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the subscriber list",
+		})
+	}
+
+	return context.Status(200).JSON(subscriberList)
+}
+
+// @Summary 	List subscribers of a subscriber group
+// @Description Returns a list of all subscribers that are member of the given subscriber group. Soft deleteds will not be listed
+// @Tags 		Subscriber
+// @Produce  	json
+// @Param 		subscriber_group_id path string true "Subscriber Group ID"
+// @Success 	200 {array} models.SubscriberShortInfo "Successful response"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/list/subscriber_group/{subscriber_group_id} [get]
+func GetSubscriberGroupMemberList(context *fiber.Ctx) error {
+	subscriberGroupID := context.Params("subscriber_group_id")
+
+	subscriberList, err := subscriber.ListByGroup(subscriberGroupID)
+	if err != nil {
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the subscriber list",
+		})
+	}
+
+	return context.Status(200).JSON(subscriberList)
+}
+
+// @Summary 	Get Subscriber Detail
+// @Description Retrieves the details of a specific subscriber. Credentials are never returned
+// @Tags 		Subscriber
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Success 	200 {object} models.SubscriberResponse "Successful response"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/{subscriber_id} [get]
+func GetSubscriberDetail(context *fiber.Ctx) error {
+	subscriberID := context.Params("subscriber_id")
+
+	subscriberDetail, err := subscriber.Detail(subscriberID)
+	if err != nil {
+		responseCode := fiber.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			responseCode = fiber.StatusNotFound
+		}
+		return context.Status(responseCode).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the subscriber details",
+		})
+	}
+
+	return context.Status(200).JSON(subscriber.Clean(&subscriberDetail))
+}
+
+// @Summary 	Add New Subscriber
+// @Description Adds a new subscriber. The given organization and subscriber group must already exist
+// @Tags 		Subscriber
+// @Accept  	json
+// @Produce  	json
+// @Param 		body body models.Subscriber true "Subscriber Details"
+// @Success 	201 {object} map[string]string "Successfully added new subscriber"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber [post]
+func AddNewSubscriber(context *fiber.Ctx) error {
+	var newSubscriber models.Subscriber
+
+	if err := context.BodyParser(&newSubscriber); err != nil {
+		logger.OSPMLogger.Errorln(
+			fmt.Sprintf(
+				"failed to process request. Path: %s, client ip: %s, error: %+v",
+				context.Path(), middleware.ClientIP(context), err))
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	id, err := subscriber.New(newSubscriber, middleware.Actor(context))
+	if err != nil {
+		responseCode := fiber.StatusInternalServerError
+		if errors.Is(err, subscriber.ErrInvalidReference) || errors.Is(err, gorm.ErrDuplicatedKey) {
+			responseCode = fiber.StatusBadRequest
+		}
+		logger.OSPMLogger.Errorln(
+			fmt.Sprintf(
+				"failed to process request. Path: %s, client ip: %s, error: %+v",
+				context.Path(), middleware.ClientIP(context), err))
+		return context.Status(responseCode).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to add new subscriber",
+		})
+	}
+
+	return context.Status(201).JSON(map[string]string{
+		"message":           fmt.Sprintf("subscriber %s successfully added", newSubscriber.Details.Name),
+		"new_subscriber_id": id,
+	})
+}
+
+// @Summary 	Update Subscriber
+// @Description Updates the details, credentials or the subscriber group of a specific subscriber. Empty fields are left untouched
+// @Tags 		Subscriber
+// @Accept  	json
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Param 		body body models.Subscriber true "Subscriber Details"
+// @Success 	200 "OK"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/{subscriber_id} [patch]
+func UpdateSubscriber(context *fiber.Ctx) error {
+	var newSubscriberDetails models.Subscriber
+	subscriberID := context.Params("subscriber_id")
+
+	if err := context.BodyParser(&newSubscriberDetails); err != nil {
+		logger.OSPMLogger.Errorln(
+			fmt.Sprintf(
+				"failed to process request. Path: %s, client ip: %s, error: %+v",
+				context.Path(), middleware.ClientIP(context), err))
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	if err := subscriber.Update(newSubscriberDetails, subscriberID, middleware.Actor(context)); err != nil {
+		responseCode := fiber.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			responseCode = fiber.StatusNotFound
+		} else if errors.Is(err, subscriber.ErrInvalidReference) || errors.Is(err, gorm.ErrDuplicatedKey) {
+			responseCode = fiber.StatusBadRequest
+		}
+		logger.OSPMLogger.Errorln(
+			fmt.Sprintf(
+				"failed to process request. Path: %s, client ip: %s, error: %+v",
+				context.Path(), middleware.ClientIP(context), err))
+		return context.Status(responseCode).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to update the subscriber",
+		})
+	}
+
+	return context.SendStatus(200)
+}
+
+// @Summary 	Delete a Subscriber
+// @Description Deletes a specific subscriber by its ID either in soft or hard mode
+// @Tags 		Subscriber
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Param 		mode query string true "Deletion Mode: hard/soft"
+// @Success 	204 "No Content"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/{subscriber_id} [delete]
+func DeleteSubscriber(context *fiber.Ctx) error {
+	var err error
+	subscriberID := context.Params("subscriber_id")
+
+	switch context.Query("mode") {
+	case "soft":
+		err = subscriber.SoftDelete(subscriberID, middleware.Actor(context))
+	case "hard":
+		err = subscriber.HardDelete(subscriberID, middleware.Actor(context))
+	default:
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   fiber.ErrBadRequest.Error(),
+			Message: "the deletion mode should be provided. valid values are: soft/hard",
+		})
+	}
+
+	if err != nil {
+		responseCode := fiber.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			responseCode = fiber.StatusNotFound
+		}
+		return context.Status(responseCode).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to delete the subscriber",
+		})
+	}
+
+	return context.SendStatus(204)
+}
+
+// @Summary 	Recover a soft deleted subscriber
+// @Description Recovers a soft deleted subscriber. The organization and subscriber group of the subscriber must still exist
+// @Tags 		Subscriber
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Success 	200 {object} map[string]string "Subscriber successfully recovered"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/recover/{subscriber_id} [patch]
+func RecoverSoftDeletedSubscriber(context *fiber.Ctx) error {
+	subscriberID := context.Params("subscriber_id")
+
+	if err := subscriber.Recover(subscriberID, middleware.Actor(context)); err != nil {
+		responseCode := fiber.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			responseCode = fiber.StatusNotFound
+		} else if errors.Is(err, subscriber.ErrInvalidReference) {
+			responseCode = fiber.StatusBadRequest
+		}
+		return context.Status(responseCode).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to recover the subscriber",
+		})
+	}
+
+	return context.Status(200).JSON(map[string]string{
+		"message":               "subscriber successfully recovered",
+		"subscriber_to_recover": subscriberID,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"ospm/internal/api/middleware"
 	"ospm/internal/models"
 	"ospm/internal/service/logger"
 	"ospm/internal/service/subscriberGroup"
@@ -15,6 +16,16 @@ import (
 	"gorm.io/gorm"
 )
 
+// SubscriberGroupHandler handles the subscriber group requests with its service
+type SubscriberGroupHandler struct {
+	service *subscriberGroup.Service
+}
+
+// NewSubscriberGroupHandler returns a handler of the subscriber group requests
+func NewSubscriberGroupHandler(service *subscriberGroup.Service) *SubscriberGroupHandler {
+	return &SubscriberGroupHandler{service: service}
+}
+
 // @Summary 	List all Subscriber Groups
 // @Description Returns a list of all subscriber groups within an organization
 // @Tags 		Organization
@@ -25,10 +36,10 @@ import (
 // @Failure 	404 {object} models.APIError "Not Found"
 // @Failure 	500 {object} models.APIError "Internal Server Error"
 // @Router 		/subscriber-group/list/{organization_id} [get]
-func GetSubscriberGroupList(context *fiber.Ctx) error {
+func (h *SubscriberGroupHandler) GetSubscriberGroupList(context *fiber.Ctx) error {
 	organizationID := context.Params("organization_id")
 
-	organizationGroupList, err := subscriberGroup.List(organizationID)
+	organizationGroupList, err := h.service.List(organizationID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			errorMessage := models.APIError{
@@ -66,14 +77,24 @@ func GetSubscriberGroupList(context *fiber.Ctx) error {
 // @Produce  	json
 // @Param 		organization_id path int true "Organization ID"
 // @Param 		subscriber_group_id path int true "Subscriber Group ID"
+// @Param 		version query int false "Format version of the group. Version 2 (models.SubscriberGroupAPIV2) nests the permissions by category and name (Default: 1)"
 // @Success 	200 {object} models.SubscriberGroupAPI "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
 // @Failure 	404 {object} models.APIError "Not Found"
 // @Failure 	500 {object} models.APIError "Internal Server Error"
 // @Router 		/subscriber_group/{subscriber_group_id} [get]
-func GetSubscriberGroupDetail(context *fiber.Ctx) error {
+func (h *SubscriberGroupHandler) GetSubscriberGroupDetail(context *fiber.Ctx) error {
 	subscriberGroupID := context.Params("subscriber_group_id")
 
-	groupDetail, err := subscriberGroup.Detail(subscriberGroupID)
+	version, err := subscriberGroupFormatVersion(context)
+	if err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the subscriber group details",
+		})
+	}
+
+	groupDetail, err := h.service.Detail(subscriberGroupID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			errorMessage := models.APIError{
@@ -90,7 +111,12 @@ func GetSubscriberGroupDetail(context *fiber.Ctx) error {
 		}
 	}
 
-	jsonDetails, err := json.Marshal(groupDetail.Beautify())
+	var jsonDetails []byte
+	if version == 2 {
+		jsonDetails, err = json.Marshal(groupDetail.BeautifyV2())
+	} else {
+		jsonDetails, err = json.Marshal(groupDetail.Beautify())
+	}
 	if err != nil {
 		errorMessage := models.APIError{
 			Error:   err.Error(),
@@ -108,15 +134,26 @@ func GetSubscriberGroupDetail(context *fiber.Ctx) error {
 // @Accept  	json
 // @Produce  	json
 // @Param 		organization_id path int true "Subscriber Group ID"
+// @Param 		version query int false "Format version of the body. Version 2 accepts models.SubscriberGroupCreateRequestV2 (Default: 1)"
 // @Param 		body body models.SubscriberGroupAPI true "Subscriber Group Details"
 // @Success 	201 {object} models.SubscriberGroupCreateResponse "Successfully added new subscriber group"
 // @Failure 	400 {object} models.APIError "Bad Request"
 // @Failure 	500 {object} models.APIError "Internal Server Error"
 // @Router 		/subscriber_group/{organization_id} [post]
-func AddNewSubscriberGroup(context *fiber.Ctx) error {
+func (h *SubscriberGroupHandler) AddNewSubscriberGroup(context *fiber.Ctx) error {
 	var newSubscriberGroup models.SubscriberGroup
 
-	err := context.BodyParser(&newSubscriberGroup)
+	version, err := subscriberGroupFormatVersion(context)
+	if err == nil {
+		if version == 2 {
+			var request models.SubscriberGroupCreateRequestV2
+			if err = context.BodyParser(&request); err == nil {
+				newSubscriberGroup = request.SubscriberGroup()
+			}
+		} else {
+			err = context.BodyParser(&newSubscriberGroup)
+		}
+	}
 	if err != nil {
 		errorMessage := models.APIError{
 			Error:   err.Error(),
@@ -125,15 +162,15 @@ func AddNewSubscriberGroup(context *fiber.Ctx) error {
 		logger.OSPMLogger.Errorln(
 			fmt.Sprintf(
 				"failed to process request. Path: %s, client ip: %s, error: %+v",
-				context.Path(), context.IP(), err))
+				context.Path(), middleware.ClientIP(context), err))
 		return context.Status(fiber.StatusBadRequest).JSON(errorMessage)
 	}
 
 	newSubscriberGroup.OrganizationID = context.Params("organization_id")
-	id, err := subscriberGroup.New(newSubscriberGroup)
+	id, err := h.service.New(newSubscriberGroup, middleware.Actor(context))
 	if err != nil {
 		responseCode := 500
-		if errors.Is(err, gorm.ErrDuplicatedKey) {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || errors.Is(err, subscriberGroup.ErrInvalidRequest) {
 			responseCode = fiber.ErrBadRequest.Code
 		}
 		errorMessage := models.APIError{
@@ -143,7 +180,7 @@ func AddNewSubscriberGroup(context *fiber.Ctx) error {
 		logger.OSPMLogger.Errorln(
 			fmt.Sprintf(
 				"failed to process request. Path: %s, client ip: %s, error: %+v",
-				context.Path(), context.IP(), err))
+				context.Path(), middleware.ClientIP(context), err))
 		return context.Status(responseCode).JSON(errorMessage)
 	}
 
@@ -157,23 +194,36 @@ func AddNewSubscriberGroup(context *fiber.Ctx) error {
 }
 
 // @Summary 	Update Subscriber Group
-// @Description Updates the settings of a specific subscriber group within an organization by its ID
+// @Description Updates the settings of a specific subscriber group by its ID. Either the whole permission set is replaced or individual permissions are added, modified and removed, all in one transaction
 // @Tags 		Organization
 // @Accept  	json
 // @Produce  	json
-// @Param 		subscriber-group-id path int true "Subscriber Group ID"
-// @Param 		organization-id path int true "Subscriber Group ID"
-// @Param 		body body models.SubscriberGroupAPI true "Subscriber Group Settings"
-// @Success 	200 "No Content"
+// @Param 		subscriber_group_id path string true "Subscriber Group ID"
+// @Param 		version query int false "Format version of the body and the response. Version 2 accepts models.SubscriberGroupUpdateRequestV2 and returns models.SubscriberGroupAPIV2 (Default: 1)"
+// @Param 		body body models.SubscriberGroupUpdateRequest true "Subscriber Group Settings"
+// @Success 	200 {object} models.SubscriberGroupAPI "The updated subscriber group"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "The permission to add already exists or the permission to change does not exist"
 // @Failure 	500 {object} models.APIError "Internal Server Error"
-// @Router 		/subscriber-group/{subscriber-group-id} [patch]
-func UpdateSubscriberGroup(context *fiber.Ctx) error {
-	var newSubscriberGroupSettings models.SubscriberGroup
+// @Router 		/subscriber_group/{subscriber_group_id} [patch]
+func (h *SubscriberGroupHandler) UpdateSubscriberGroup(context *fiber.Ctx) error {
+	var request models.SubscriberGroupUpdateRequest
 	var responseCode int
 
-	subscriberGroupID := context.Params("subscriber-group-id")
+	subscriberGroupID := context.Params("subscriber_group_id")
 
-	err := context.BodyParser(&newSubscriberGroupSettings)
+	version, err := subscriberGroupFormatVersion(context)
+	if err == nil {
+		if version == 2 {
+			var requestV2 models.SubscriberGroupUpdateRequestV2
+			if err = context.BodyParser(&requestV2); err == nil {
+				request = requestV2.UpdateRequest()
+			}
+		} else {
+			err = context.BodyParser(&request)
+		}
+	}
 	if err != nil {
 		errorMessage := models.APIError{
 			Error:   err.Error(),
@@ -182,15 +232,20 @@ func UpdateSubscriberGroup(context *fiber.Ctx) error {
 		logger.OSPMLogger.Errorln(
 			fmt.Sprintf(
 				"failed to process request. Path: %s, client ip: %s, error: %+v",
-				context.Path(), context.IP(), err))
+				context.Path(), middleware.ClientIP(context), err))
 		return context.Status(fiber.StatusBadRequest).JSON(errorMessage)
 	}
 
-	err = subscriberGroup.Update(newSubscriberGroupSettings, subscriberGroupID)
+	updatedGroup, err := h.service.Update(request, subscriberGroupID, middleware.Actor(context))
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
 			responseCode = fiber.ErrNotFound.Code
-		} else {
+		case errors.Is(err, subscriberGroup.ErrInvalidRequest):
+			responseCode = fiber.ErrBadRequest.Code
+		case errors.Is(err, subscriberGroup.ErrPermissionExists), errors.Is(err, subscriberGroup.ErrPermissionNotFound):
+			responseCode = fiber.ErrConflict.Code
+		default:
 			responseCode = fiber.ErrInternalServerError.Code
 		}
 		errorMessage := models.APIError{
@@ -200,12 +255,14 @@ func UpdateSubscriberGroup(context *fiber.Ctx) error {
 		logger.OSPMLogger.Errorln(
 			fmt.Sprintf(
 				"failed to process request. Path: %s, client ip: %s, error: %+v",
-				context.Path(), context.IP(), err))
+				context.Path(), middleware.ClientIP(context), err))
 		return context.Status(responseCode).JSON(errorMessage)
 	}
 
-	return context.SendStatus(200)
-
+	if version == 2 {
+		return context.Status(200).JSON(updatedGroup.BeautifyV2())
+	}
+	return context.Status(200).JSON(updatedGroup.Beautify())
 }
 
 // @Summary 	Delete a Subscriber Group
@@ -219,11 +276,11 @@ func UpdateSubscriberGroup(context *fiber.Ctx) error {
 // @Failure 	404 {object} models.APIError "Not Found"
 // @Failure 	500 {object} models.APIError "Internal Server Error"
 // @Router 		/subscriber-group/{subscriber-group-id} [delete]
-func DeleteSubscriberGroup(context *fiber.Ctx) error {
+func (h *SubscriberGroupHandler) DeleteSubscriberGroup(context *fiber.Ctx) error {
 	var responseCode int
 	subscriberGroupID := context.Params("subscriber_group_id")
 
-	err := subscriberGroup.Delete(subscriberGroupID)
+	err := h.service.Delete(subscriberGroupID, middleware.Actor(context))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			responseCode = fiber.ErrNotFound.Code
@@ -237,9 +294,22 @@ func DeleteSubscriberGroup(context *fiber.Ctx) error {
 		logger.OSPMLogger.Errorln(
 			fmt.Sprintf(
 				"failed to process request. Path: %s, client ip: %s, error: %+v",
-				context.Path(), context.IP(), err))
+				context.Path(), middleware.ClientIP(context), err))
 		return context.Status(responseCode).JSON(errorMessage)
 	}
 
 	return context.SendStatus(204)
 }
+
+// subscriberGroupFormatVersion returns the requested format version of the subscriber group.
+// Version 1 keeps one permission per category and is used when no version is given
+func subscriberGroupFormatVersion(context *fiber.Ctx) (int, error) {
+	switch context.Query("version") {
+	case "", "1":
+		return 1, nil
+	case "2":
+		return 2, nil
+	}
+
+	return 0, fmt.Errorf("unsupported subscriber group format version %q, supported versions are 1 and 2", context.Query("version"))
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"ospm/config"
+	"ospm/internal/models"
+	"ospm/internal/repository/memory"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/subscriberGroup"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriberGroupHandler(t *testing.T) {
+	config.LoadOSPMConfigs()
+	logger.InitLogger()
+
+	subscriberGroupHandler := NewSubscriberGroupHandler(subscriberGroup.NewService(memory.NewSubscriberGroupRepository()))
+	// the in-memory repository keeps the values after the request, so they must not be reused by fiber
+	app := fiber.New(fiber.Config{Immutable: true})
+	app.Get("/subscriber_group/list/:organization_id", subscriberGroupHandler.GetSubscriberGroupList)
+	app.Get("/subscriber_group/:subscriber_group_id", subscriberGroupHandler.GetSubscriberGroupDetail)
+	app.Post("/subscriber_group/:organization_id", subscriberGroupHandler.AddNewSubscriberGroup)
+	app.Patch("/subscriber_group/:subscriber_group_id", subscriberGroupHandler.UpdateSubscriberGroup)
+	app.Delete("/subscriber_group/:subscriber_group_id", subscriberGroupHandler.DeleteSubscriberGroup)
+
+	organizationID := "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+	send := func(method string, target string, body string) (int, string) {
+		request := httptest.NewRequest(method, target, strings.NewReader(body))
+		request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		response, err := app.Test(request)
+		assert.NoError(t, err)
+
+		responseBody, err := io.ReadAll(response.Body)
+		assert.NoError(t, err)
+		return response.StatusCode, string(responseBody)
+	}
+
+	status, body := send(fiber.MethodPost, "/subscriber_group/"+organizationID+"?version=2",
+		`{"subscriber_group_name":"basic","subscriber_group_permissions":{"ACCESS_LEVEL":{"CAN_LOGIN":"yes"}}}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+
+	var created models.SubscriberGroupCreateResponse
+	assert.NoError(t, json.Unmarshal([]byte(body), &created))
+
+	// the names of the groups are unique within their organization
+	status, _ = send(fiber.MethodPost, "/subscriber_group/"+organizationID+"?version=2", `{"subscriber_group_name":"basic"}`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+
+	status, body = send(fiber.MethodPatch, "/subscriber_group/"+created.Id,
+		`{"update_permissions":[{"permission_category":"ACCESS_LEVEL","permission_name":"CAN_LOGIN","permission_value":"no"}]}`)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Contains(t, body, `{"permission":"CAN_LOGIN","value":"no"}`)
+
+	status, body = send(fiber.MethodGet, "/subscriber_group/list/"+organizationID, "")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Contains(t, body, `"subscriber_group_name":"basic"`)
+
+	status, _ = send(fiber.MethodDelete, "/subscriber_group/"+created.Id, "")
+	assert.Equal(t, fiber.StatusNoContent, status)
+
+	status, _ = send(fiber.MethodGet, "/subscriber_group/"+created.Id, "")
+	assert.Equal(t, fiber.StatusNotFound, status)
+
+	status, _ = send(fiber.MethodDelete, "/subscriber_group/"+created.Id, "")
+	assert.Equal(t, fiber.StatusNotFound, status)
+}
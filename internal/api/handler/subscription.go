@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/api/middleware"
+	"ospm/internal/models"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/subscription"
+	"time"
+
+	// This line is being used by swagger auto-documenting
+	_ "ospm/docs/api"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// @Summary 	Subscribe a subscriber to a product offering
+// @Description Subscribes a subscriber to an active product offering. The subscription is pending until its start date if it is in the future
+// @Tags 		Subscription
+// @Accept  	json
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Param 		body body models.SubscriptionRequest true "Subscription details"
+// @Success 	201 {object} models.SubscriptionResponse "Successfully subscribed"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	409 {object} models.APIError "Already subscribed to the product offering"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/{subscriber_id}/subscription [post]
+func SubscribeSubscriber(context *fiber.Ctx) error {
+	var request models.SubscriptionRequest
+	if err := context.BodyParser(&request); err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to process the request",
+		})
+	}
+
+	result, err := subscription.Subscribe(context.Params("subscriber_id"), request, middleware.Actor(context))
+	if err != nil {
+		return subscriptionError(context, err)
+	}
+
+	return context.Status(fiber.StatusCreated).JSON(result)
+}
+
+// @Summary 	List subscriptions of a subscriber
+// @Description Returns all of the subscriptions of a subscriber including the terminated ones with their history, the latest first
+// @Tags 		Subscription
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Success 	200 {array} models.SubscriptionResponse "Successful response"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/{subscriber_id}/subscription [get]
+func GetSubscriberSubscriptions(context *fiber.Ctx) error {
+	subscriptions, err := subscription.List(context.Params("subscriber_id"))
+	if err != nil {
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the subscriptions",
+		})
+	}
+
+	return context.Status(200).JSON(subscriptions)
+}
+
+// @Summary 	List entitlements of a subscriber
+// @Description Returns the subscriptions of a subscriber which were active at the given time. The current time is used by default
+// @Tags 		Subscription
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Param 		at query string false "The time in RFC3339 format"
+// @Success 	200 {array} models.SubscriptionResponse "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscriber/{subscriber_id}/entitlement [get]
+func GetSubscriberEntitlements(context *fiber.Ctx) error {
+	at := time.Now()
+	if value := context.Query("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+				Error:   err.Error(),
+				Message: "invalid time",
+			})
+		}
+		at = parsed
+	}
+
+	entitlements, err := subscription.Entitlements(context.Params("subscriber_id"), at)
+	if err != nil {
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the entitlements",
+		})
+	}
+
+	return context.Status(200).JSON(entitlements)
+}
+
+// @Summary 	Get Subscription Detail
+// @Description Retrieves a subscription and its history
+// @Tags 		Subscription
+// @Produce  	json
+// @Param 		subscription_id path string true "Subscription ID"
+// @Success 	200 {object} models.SubscriptionResponse "Successful response"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscription/{subscription_id} [get]
+func GetSubscriptionDetail(context *fiber.Ctx) error {
+	result, err := subscription.Detail(context.Params("subscription_id"))
+	if err != nil {
+		return subscriptionError(context, err)
+	}
+
+	return context.Status(200).JSON(result)
+}
+
+// @Summary 	Change the plan of a subscription
+// @Description Terminates an active subscription and subscribes the subscriber to another product offering from now on. The new subscription is returned
+// @Tags 		Subscription
+// @Accept  	json
+// @Produce  	json
+// @Param 		subscription_id path string true "Subscription ID"
+// @Param 		body body models.SubscriptionActionRequest true "The new product offering"
+// @Success 	200 {object} models.SubscriptionResponse "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "The subscription is not active"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscription/{subscription_id}/change_plan [post]
+func ChangeSubscriptionPlan(context *fiber.Ctx) error {
+	return applySubscriptionAction(context, subscription.ChangePlan)
+}
+
+// @Summary 	Suspend a subscription
+// @Description Suspends an active subscription until it is resumed
+// @Tags 		Subscription
+// @Accept  	json
+// @Produce  	json
+// @Param 		subscription_id path string true "Subscription ID"
+// @Param 		body body models.SubscriptionActionRequest false "The reason of the suspension"
+// @Success 	200 {object} models.SubscriptionResponse "Successful response"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "The subscription is not active"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscription/{subscription_id}/suspend [post]
+func SuspendSubscription(context *fiber.Ctx) error {
+	return applySubscriptionAction(context, subscription.Suspend)
+}
+
+// @Summary 	Resume a subscription
+// @Description Activates a suspended subscription again
+// @Tags 		Subscription
+// @Accept  	json
+// @Produce  	json
+// @Param 		subscription_id path string true "Subscription ID"
+// @Param 		body body models.SubscriptionActionRequest false "The reason of the resumption"
+// @Success 	200 {object} models.SubscriptionResponse "Successful response"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "The subscription is not suspended"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscription/{subscription_id}/resume [post]
+func ResumeSubscription(context *fiber.Ctx) error {
+	return applySubscriptionAction(context, subscription.Resume)
+}
+
+// @Summary 	Terminate a subscription
+// @Description Ends a subscription now. Terminated subscriptions can not be changed anymore
+// @Tags 		Subscription
+// @Accept  	json
+// @Produce  	json
+// @Param 		subscription_id path string true "Subscription ID"
+// @Param 		body body models.SubscriptionActionRequest false "The reason of the termination"
+// @Success 	200 {object} models.SubscriptionResponse "Successful response"
+// @Failure 	404 {object} models.APIError "Not Found"
+// @Failure 	409 {object} models.APIError "The subscription is already terminated"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/subscription/{subscription_id}/terminate [post]
+func TerminateSubscription(context *fiber.Ctx) error {
+	return applySubscriptionAction(context, subscription.Terminate)
+}
+
+// applySubscriptionAction parses the optional request body and applies the given action on the subscription
+func applySubscriptionAction(
+	context *fiber.Ctx,
+	action func(subscriptionID string, request models.SubscriptionActionRequest, actor audit.Actor) (models.SubscriptionResponse, error),
+) error {
+	var request models.SubscriptionActionRequest
+	if len(context.Body()) > 0 {
+		if err := context.BodyParser(&request); err != nil {
+			return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+				Error:   err.Error(),
+				Message: "failed to process the request",
+			})
+		}
+	}
+
+	result, err := action(context.Params("subscription_id"), request, middleware.Actor(context))
+	if err != nil {
+		return subscriptionError(context, err)
+	}
+
+	return context.Status(200).JSON(result)
+}
+
+// subscriptionError maps the errors of the subscription service to the response
+func subscriptionError(context *fiber.Ctx, err error) error {
+	responseCode := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		responseCode = fiber.StatusNotFound
+	case errors.Is(err, subscription.ErrInvalidReference), errors.Is(err, subscription.ErrInvalidRequest):
+		responseCode = fiber.StatusBadRequest
+	case errors.Is(err, subscription.ErrInvalidTransition), errors.Is(err, subscription.ErrAlreadySubscribed):
+		responseCode = fiber.StatusConflict
+	}
+	logger.OSPMLogger.Errorln(
+		fmt.Sprintf(
+			"failed to process request. Path: %s, client ip: %s, error: %+v",
+			context.Path(), middleware.ClientIP(context), err))
+	return context.Status(responseCode).JSON(models.APIError{
+		Error:   err.Error(),
+		Message: "failed to process the subscription request",
+	})
+}
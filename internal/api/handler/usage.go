@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/service/usage"
+	"time"
+
+	// This line is being used by swagger auto-documenting
+	_ "ospm/docs/api"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultUsagePeriod is used if the start of the time range is not given
+const defaultUsagePeriod = 30 * 24 * time.Hour
+
+// @Summary 	Get usage of a subscriber
+// @Description Returns the total usage of a subscriber for the sessions started in the given time range. The last 30 days are used by default
+// @Tags 		Usage
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Param 		from query string false "Start of the time range in RFC3339 format"
+// @Param 		to query string false "End of the time range in RFC3339 format"
+// @Success 	200 {object} models.UsageSummary "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/usage/subscriber/{subscriber_id} [get]
+func GetSubscriberUsage(context *fiber.Ctx) error {
+	from, to, err := usageTimeRange(context)
+	if err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "invalid time range",
+		})
+	}
+
+	summary, err := usage.SubscriberUsage(context.Params("subscriber_id"), from, to)
+	if err != nil {
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the subscriber usage",
+		})
+	}
+
+	return context.Status(200).JSON(summary)
+}
+
+// @Summary 	List sessions of a subscriber
+// @Description Returns the accounting sessions of a subscriber started in the given time range. The last 30 days are used by default
+// @Tags 		Usage
+// @Produce  	json
+// @Param 		subscriber_id path string true "Subscriber ID"
+// @Param 		from query string false "Start of the time range in RFC3339 format"
+// @Param 		to query string false "End of the time range in RFC3339 format"
+// @Success 	200 {array} models.AccountingSessionInfo "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/usage/subscriber/{subscriber_id}/sessions [get]
+func GetSubscriberSessions(context *fiber.Ctx) error {
+	from, to, err := usageTimeRange(context)
+	if err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "invalid time range",
+		})
+	}
+
+	sessions, err := usage.SubscriberSessions(context.Params("subscriber_id"), from, to)
+	if err != nil {
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the subscriber sessions",
+		})
+	}
+
+	return context.Status(200).JSON(sessions)
+}
+
+// @Summary 	Get usage of an organization
+// @Description Returns the total usage of all subscribers of an organization for the sessions started in the given time range. The last 30 days are used by default
+// @Tags 		Usage
+// @Produce  	json
+// @Param 		organization_id path string true "Organization ID"
+// @Param 		from query string false "Start of the time range in RFC3339 format"
+// @Param 		to query string false "End of the time range in RFC3339 format"
+// @Success 	200 {object} models.UsageSummary "Successful response"
+// @Failure 	400 {object} models.APIError "Bad Request"
+// @Failure 	500 {object} models.APIError "Internal Server Error"
+// @Router 		/usage/organization/{organization_id} [get]
+func GetOrganizationUsage(context *fiber.Ctx) error {
+	from, to, err := usageTimeRange(context)
+	if err != nil {
+		return context.Status(fiber.StatusBadRequest).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "invalid time range",
+		})
+	}
+
+	summary, err := usage.OrganizationUsage(context.Params("organization_id"), from, to)
+	if err != nil {
+		return context.Status(fiber.StatusInternalServerError).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "failed to load the organization usage",
+		})
+	}
+
+	return context.Status(200).JSON(summary)
+}
+
+// usageTimeRange parses the from and to query parameters
+func usageTimeRange(context *fiber.Ctx) (time.Time, time.Time, error) {
+	var err error
+	to := time.Now()
+	if context.Query("to") != "" {
+		if to, err = time.Parse(time.RFC3339, context.Query("to")); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	from := to.Add(-defaultUsagePeriod)
+	if context.Query("from") != "" {
+		if from, err = time.Parse(time.RFC3339, context.Query("from")); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from (%s) must be before to (%s)", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	return from, to, nil
+}
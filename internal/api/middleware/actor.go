@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"ospm/internal/models"
+	"ospm/internal/service/audit"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+// Actor returns who is making the request for the audit log
+func Actor(context *fiber.Ctx) audit.Actor {
+	actor := audit.Actor{ClientIP: ClientIP(context)}
+
+	if apiKey, authenticated := context.Locals(APIKeyLocal).(models.APIKey); authenticated {
+		actor.APIKeyID = apiKey.ID
+	}
+	if requestID, found := context.Locals(requestid.ConfigDefault.ContextKey).(string); found {
+		actor.RequestID = requestID
+	}
+
+	return actor
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/service/apikey"
+	"ospm/internal/service/logger"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyLocal is the key of the authenticated models.APIKey in the request locals
+const APIKeyLocal = "api_key"
+
+// publicPaths do not need an API key. The subscribers authenticate by their own credentials
+var publicPaths = []string{"/apidoc", "/auth"}
+
+// APIKeyAuthentication checks the API key of the X-API-Key header and the scope
+// that the request needs. The authenticated key is kept in the request locals
+func APIKeyAuthentication(context *fiber.Ctx) error {
+	if !Settings(context).Config.Auth.APIKeyRequired || isPublicPath(context.Path()) {
+		return context.Next()
+	}
+
+	apiKey, err := apikey.Authenticate(context.Get("X-API-Key"))
+	if err != nil {
+		responseCode := fiber.StatusInternalServerError
+		if errors.Is(err, apikey.ErrInvalidAPIKey) {
+			responseCode = fiber.StatusUnauthorized
+		}
+		logger.OSPMLogger.Errorln(
+			fmt.Sprintf(
+				"failed to process request. Path: %s, client ip: %s, error: %+v",
+				context.Path(), ClientIP(context), err))
+		return context.Status(responseCode).JSON(models.APIError{
+			Error:   err.Error(),
+			Message: "a valid API key should be provided in X-API-Key header",
+		})
+	}
+
+	requiredScope := apikey.RequiredScope(context.Method(), context.Path(), context.Query("mode"))
+	if !apikey.HasScope(apiKey.Scopes, requiredScope) {
+		logger.OSPMLogger.Warnf("request of API key id %s is rejected. Path: %s, client ip: %s, required scope: %s",
+			apiKey.ID, context.Path(), ClientIP(context), requiredScope)
+		return context.Status(fiber.StatusForbidden).JSON(models.APIError{
+			Error:   apikey.ErrInsufficientScope.Error(),
+			Message: fmt.Sprintf("the API key needs %s scope", requiredScope),
+		})
+	}
+
+	context.Locals(APIKeyLocal, apiKey)
+	return context.Next()
+}
+
+func isPublicPath(path string) bool {
+	for _, publicPath := range publicPaths {
+		if path == publicPath || strings.HasPrefix(path, publicPath+"/") {
+			return true
+		}
+	}
+	return false
+}
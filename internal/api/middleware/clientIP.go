@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"ospm/internal/service/clientip"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClientIPLocal is the key of the resolved client IP in the locals of the request
+const ClientIPLocal = "client_ip"
+
+// ResolveClientIP resolves the IP of the client from the forwarding headers when the request
+// comes from a trusted proxy. It should follow LoadSettings so the others can use ClientIP
+func ResolveClientIP(context *fiber.Ctx) error {
+	apiSettings := Settings(context).Config.API
+	clientIP := clientip.Resolve(
+		context.Context().RemoteIP().String(),
+		func(key string) string { return context.Get(key) },
+		apiSettings.TrustedProxySet(),
+		apiSettings.ClientIPHeader,
+	)
+	context.Locals(ClientIPLocal, clientIP)
+
+	return context.Next()
+}
+
+// ClientIP returns the resolved IP of the client. The address of the connection
+// is returned if the IP is not resolved by ResolveClientIP
+func ClientIP(context *fiber.Ctx) string {
+	if clientIP, resolved := context.Locals(ClientIPLocal).(string); resolved {
+		return clientIP
+	}
+	return context.IP()
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/policy"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoutePolicy evaluates the route policy on every request. It should be used after
+// APIKeyAuthentication so the scopes of the API key can be checked by the rules
+func RoutePolicy(context *fiber.Ctx) error {
+	request := policy.Request{
+		Method:   context.Method(),
+		Path:     context.Path(),
+		Query:    func(key string) string { return context.Query(key) },
+		ClientIP: ClientIP(context),
+		Time:     time.Now(),
+	}
+	if apiKey, authenticated := context.Locals(APIKeyLocal).(models.APIKey); authenticated {
+		request.Scopes = apiKey.Scopes
+	}
+
+	decision := Settings(context).Policy.Evaluate(request)
+	if decision.Effect == policy.EffectAllow {
+		return context.Next()
+	}
+
+	message := decision.Message
+	if message == "" {
+		message = fmt.Sprintf("request from %s is not permitted", request.ClientIP)
+	}
+	logger.OSPMLogger.Warnf("request is denied by the route policy. Path: %s, method: %s, client ip: %s, rule: %s",
+		request.Path, request.Method, request.ClientIP, decision.Rule)
+
+	return context.Status(fiber.StatusForbidden).JSON(models.APIError{
+		Error:   fiber.ErrForbidden.Error(),
+		Message: message,
+	})
+}
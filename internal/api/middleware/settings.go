@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"ospm/internal/service/settings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SettingsLocal is the key of the settings snapshot of the request in its locals
+const SettingsLocal = "settings"
+
+// LoadSettings reads the settings snapshot once for the request, so the other middlewares and the
+// handlers use the same config, route policy and permission catalog even if they are reloaded meanwhile.
+// It should be the first middleware
+func LoadSettings(context *fiber.Ctx) error {
+	context.Locals(SettingsLocal, settings.Current())
+	return context.Next()
+}
+
+// Settings returns the settings snapshot of the request. The current snapshot is returned
+// if the request has not passed LoadSettings
+func Settings(context *fiber.Ctx) *settings.Snapshot {
+	if snapshot, loaded := context.Locals(SettingsLocal).(*settings.Snapshot); loaded {
+		return snapshot
+	}
+	return settings.Current()
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"ospm/internal/api/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupAPIKeyRoutes(rg fiber.Router) {
+
+	rg.Get("", handler.GetAPIKeyList)
+	rg.Post("", handler.AddAPIKey)
+	rg.Post("/:api_key_id/rotate", handler.RotateAPIKey)
+	rg.Delete("/:api_key_id", handler.RevokeAPIKey)
+}
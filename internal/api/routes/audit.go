@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"ospm/internal/api/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupAuditRoutes(rg fiber.Router) {
+
+	rg.Get("", handler.GetAuditLog)
+	rg.Get("/verify", handler.VerifyAuditLog)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"ospm/internal/api/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupAuthenticationRoutes(rg fiber.Router) {
+
+	rg.Post("/login", handler.Login)
+	rg.Post("/refresh", handler.RefreshToken)
+	rg.Post("/revoke", handler.RevokeToken)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"ospm/internal/api/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupCatalogRoutes(rg fiber.Router) {
+
+	rg.Get("/productOffering", handler.GetProductOfferingList)
+	rg.Get("/productOffering/:product_offering_id", handler.GetProductOffering)
+	rg.Post("/productOffering", handler.AddProductOffering)
+	rg.Patch("/productOffering/:product_offering_id", handler.UpdateProductOffering)
+	rg.Delete("/productOffering/:product_offering_id", handler.DeleteProductOffering)
+
+	rg.Get("/productSpecification", handler.GetProductSpecificationList)
+	rg.Get("/productSpecification/:product_specification_id", handler.GetProductSpecification)
+	rg.Post("/productSpecification", handler.AddProductSpecification)
+	rg.Patch("/productSpecification/:product_specification_id", handler.UpdateProductSpecification)
+	rg.Delete("/productSpecification/:product_specification_id", handler.DeleteProductSpecification)
+}
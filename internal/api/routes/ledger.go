@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"ospm/internal/api/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupBalanceRoutes(rg fiber.Router) {
+
+	rg.Get("/:organization_id/transactions", handler.GetLedgerTransactions)
+	rg.Get("/:organization_id/reconciliation", handler.ReconcileOrganizationBalance)
+	rg.Post("/:organization_id/credit", handler.CreditOrganizationBalance)
+	rg.Post("/:organization_id/debit", handler.DebitOrganizationBalance)
+	rg.Post("/:organization_id/adjustment", handler.AdjustOrganizationBalance)
+	rg.Post("/:organization_id/reversal/:transaction_id", handler.ReverseLedgerTransaction)
+}
@@ -2,16 +2,17 @@ package routes
 
 import (
 	"ospm/internal/api/handler"
-	"ospm/internal/api/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupOrganizationRoutes(rg fiber.Router) {
+func SetupOrganizationRoutes(rg fiber.Router, organizationHandler *handler.OrganizationHandler) {
 
-	rg.Get("", middleware.OrganizationPolicyCheck, handler.GetOrganizationList)
-	rg.Get("/profile", middleware.OrganizationPolicyCheck, handler.GetOrganizationProfile)
-	rg.Post("", middleware.OrganizationPolicyCheck, handler.AddNewOrganization)
-	rg.Delete("", middleware.OrganizationPolicyCheck, handler.DeleteOrganization)
-	rg.Patch("/recover/profile", middleware.OrganizationPolicyCheck, handler.RecoverSoftDeletedOrganization)
+	rg.Get("", organizationHandler.GetOrganizationList)
+	rg.Get("/profile", organizationHandler.GetOrganizationProfile)
+	rg.Post("", organizationHandler.AddNewOrganization)
+	rg.Delete("", organizationHandler.DeleteOrganization)
+	rg.Patch("/profile", organizationHandler.UpdateOrganizationProfile)
+	rg.Patch("/profile/balance_policy", organizationHandler.UpdateOrganizationBalancePolicy)
+	rg.Patch("/recover/profile", organizationHandler.RecoverSoftDeletedOrganization)
 }
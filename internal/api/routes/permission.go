@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"ospm/internal/api/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupPermissionCatalogRoutes(rg fiber.Router) {
+
+	rg.Get("/", handler.GetPermissionCatalog)
+	rg.Get("/:permission_category", handler.GetPermissionCategory)
+}
@@ -1,12 +1,44 @@
 package routes
 
 import (
+	"ospm/internal/api/handler"
+	"ospm/internal/api/middleware"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/organization"
+	"ospm/internal/service/subscriberGroup"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 )
 
 func Setup(app *fiber.App) {
+	// the settings are read once so the whole request is served by the same config, route policy and catalog
+	app.Use(middleware.LoadSettings)
+
+	// the client IP is resolved first so the policy and the logs see the real client behind the proxies
+	app.Use(middleware.ResolveClientIP)
+
+	// each request gets an id (or keeps the X-Request-ID given by the client) which is logged and audited
+	app.Use(requestid.New())
+
+	// every route needs an operator API key except the public ones
+	// and then the route policy decides whether the request is allowed
+	app.Use(middleware.APIKeyAuthentication)
+	app.Use(middleware.RoutePolicy)
+
 	SetupAPIDocs(app.Group("/apidoc"))
-	SetupOrganizationRoutes(app.Group("/organization"))
-	SetupSubscriberGroupRoutes(app.Group("/subscriber_group"))
+	SetupOrganizationRoutes(app.Group("/organization"),
+		handler.NewOrganizationHandler(organization.NewService(cockroachdb.NewOrganizationRepository(cockroachdb.DB))))
+	SetupSubscriberGroupRoutes(app.Group("/subscriber_group"),
+		handler.NewSubscriberGroupHandler(subscriberGroup.NewService(cockroachdb.NewSubscriberGroupRepository(cockroachdb.DB))))
+	SetupSubscriberRoutes(app.Group("/subscriber"))
+	SetupAuthenticationRoutes(app.Group("/auth"))
+	SetupUsageRoutes(app.Group("/usage"))
+	SetupBalanceRoutes(app.Group("/balance"))
+	SetupCatalogRoutes(app.Group("/catalog"))
+	SetupSubscriptionRoutes(app.Group("/subscription"))
+	SetupPermissionCatalogRoutes(app.Group("/permission_catalog"))
+	SetupAPIKeyRoutes(app.Group("/api_key"))
+	SetupAuditRoutes(app.Group("/audit"))
 
 }
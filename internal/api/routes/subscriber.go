@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"ospm/internal/api/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupSubscriberRoutes(rg fiber.Router) {
+
+	rg.Get("/list/organization/:organization_id", handler.GetSubscriberList)
+	rg.Get("/list/subscriber_group/:subscriber_group_id", handler.GetSubscriberGroupMemberList)
+	rg.Get("/:subscriber_id/subscription", handler.GetSubscriberSubscriptions)
+	rg.Get("/:subscriber_id/entitlement", handler.GetSubscriberEntitlements)
+	rg.Get("/:subscriber_id/permission/:permission_category/:permission_name", handler.EvaluateSubscriberPermission)
+	rg.Get("/:subscriber_id", handler.GetSubscriberDetail)
+	rg.Post("/:subscriber_id/subscription", handler.SubscribeSubscriber)
+	rg.Post("/:subscriber_id/permission/evaluate", handler.EvaluateSubscriberPermissions)
+	rg.Post("", handler.AddNewSubscriber)
+	rg.Patch("/recover/:subscriber_id", handler.RecoverSoftDeletedSubscriber)
+	rg.Patch("/:subscriber_id", handler.UpdateSubscriber)
+	rg.Delete("/:subscriber_id", handler.DeleteSubscriber)
+}
@@ -6,11 +6,11 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupSubscriberGroupRoutes(rg fiber.Router) {
+func SetupSubscriberGroupRoutes(rg fiber.Router, subscriberGroupHandler *handler.SubscriberGroupHandler) {
 
-	rg.Get("/list/:organization_id", handler.GetSubscriberGroupList)
-	rg.Get("/:subscriber_group_id", handler.GetSubscriberGroupDetail)
-	rg.Post("/:organization_id", handler.AddNewSubscriberGroup)
-	rg.Delete("/:subscriber_group_id", handler.DeleteSubscriberGroup)
-	rg.Patch("/:subscriber-group-id", handler.UpdateSubscriberGroup)
+	rg.Get("/list/:organization_id", subscriberGroupHandler.GetSubscriberGroupList)
+	rg.Get("/:subscriber_group_id", subscriberGroupHandler.GetSubscriberGroupDetail)
+	rg.Post("/:organization_id", subscriberGroupHandler.AddNewSubscriberGroup)
+	rg.Delete("/:subscriber_group_id", subscriberGroupHandler.DeleteSubscriberGroup)
+	rg.Patch("/:subscriber_group_id", subscriberGroupHandler.UpdateSubscriberGroup)
 }
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"ospm/internal/api/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupSubscriptionRoutes(rg fiber.Router) {
+
+	rg.Get("/:subscription_id", handler.GetSubscriptionDetail)
+	rg.Post("/:subscription_id/change_plan", handler.ChangeSubscriptionPlan)
+	rg.Post("/:subscription_id/suspend", handler.SuspendSubscription)
+	rg.Post("/:subscription_id/resume", handler.ResumeSubscription)
+	rg.Post("/:subscription_id/terminate", handler.TerminateSubscription)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"ospm/internal/api/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupUsageRoutes(rg fiber.Router) {
+
+	rg.Get("/subscriber/:subscriber_id", handler.GetSubscriberUsage)
+	rg.Get("/subscriber/:subscriber_id/sessions", handler.GetSubscriberSessions)
+	rg.Get("/organization/:organization_id", handler.GetOrganizationUsage)
+}
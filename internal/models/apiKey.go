@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKey is an operator key of the REST API. Only the SHA-256 hash of the key is stored,
+// the key itself is shown once when it is created or rotated. Prefix is the public part
+// of the key which is used to find it without scanning all of the hashes
+type APIKey struct {
+	gorm.Model
+	ID         string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"api_key_id"`
+	Name       string     `gorm:"not null;index" json:"name"`
+	Prefix     string     `gorm:"not null;uniqueIndex" json:"prefix"`
+	KeyHash    string     `gorm:"not null" json:"-"`
+	Scopes     StringList `gorm:"type:jsonb" json:"scopes"`
+	ExpiresAt  *time.Time `gorm:"index" json:"expires_at"`
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RotatedAt  *time.Time `json:"rotated_at"`
+}
+
+// ##########################
+// #	Swagger/API Models	#
+// ##########################
+// The following models are used for swagger documentation
+
+// APIKeyRequest is used to create a new API key. Scopes are in resource:action format like
+// organization:read or subscriber_group:write. resource:* and * give all of the actions
+type APIKeyRequest struct {
+	Name      string     `json:"name" example:"billing system"`
+	Scopes    []string   `json:"scopes" example:"organization:read,subscriber_group:write"`
+	ExpiresAt *time.Time `json:"expires_at"` // the key does not expire if it is not given
+}
+
+// APIKeyResponse is the API key without its secret
+type APIKeyResponse struct {
+	ID         string     `json:"api_key_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Name       string     `json:"name" example:"billing system"`
+	Prefix     string     `json:"prefix" example:"3f9a1c2e"`
+	Scopes     []string   `json:"scopes" example:"organization:read,subscriber_group:write"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RotatedAt  *time.Time `json:"rotated_at"`
+}
+
+// APIKeySecretResponse is returned when a key is created or rotated.
+// The key can not be retrieved again, it should be stored by the client
+type APIKeySecretResponse struct {
+	APIKeyResponse
+	Key string `json:"key" example:"ospm_3f9a1c2e_9mR3xV0c2Lq8aZ1bW5nY7tK4pD6sH0jF2gE8uI3oA1c"`
+}
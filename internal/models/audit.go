@@ -0,0 +1,93 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditEntry is a record of a change made by the API. Each entry keeps the hash of the previous one
+// and its own hash covers all of its fields, so changing or removing an entry breaks the chain.
+// The entries are never updated or deleted
+type AuditEntry struct {
+	Sequence      int64        `gorm:"primaryKey;autoIncrement:false" json:"sequence" example:"42"`
+	CreatedAt     time.Time    `gorm:"not null;index" json:"created_at"`
+	ActorAPIKeyID string       `gorm:"index" json:"actor_api_key_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	ActorIP       string       `gorm:"index" json:"actor_ip" example:"10.20.30.40"`
+	RequestID     string       `gorm:"index" json:"request_id" example:"5f0c5a39-0e3b-4d45-9d0f-26b5b8b2b41a"`
+	Action        string       `gorm:"not null" json:"action" example:"hard_delete"` // valid values: create, update, delete, hard_delete, recover
+	EntityType    string       `gorm:"not null;index:idx_audit_entity" json:"entity_type" example:"organization"`
+	EntityID      string       `gorm:"index:idx_audit_entity" json:"entity_id" example:"966c1a5d-1a0e-4b8b-9d6f-2b1e3d7c9a10"`
+	Before        JSONDocument `gorm:"type:jsonb" json:"before" swaggertype:"object"`
+	After         JSONDocument `gorm:"type:jsonb" json:"after" swaggertype:"object"`
+	PreviousHash  string       `gorm:"not null" json:"previous_hash"`
+	Hash          string       `gorm:"not null;uniqueIndex" json:"hash"`
+}
+
+// AuditFilter contains the filters of the audit log query. Empty fields are not applied
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string // the API key id or the IP of the actor
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
+
+// JSONDocument is a JSON value stored as it is. A nil document is stored as NULL
+type JSONDocument json.RawMessage
+
+// Scan implements the sql.Scanner interface
+func (document *JSONDocument) Scan(value interface{}) error {
+	switch typed := value.(type) {
+	case nil:
+		*document = nil
+	case []byte:
+		*document = append(JSONDocument{}, typed...)
+	case string:
+		*document = JSONDocument(typed)
+	default:
+		return fmt.Errorf("can not scan %T into a JSON document", value)
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (document JSONDocument) Value() (driver.Value, error) {
+	if len(document) == 0 {
+		return nil, nil
+	}
+	return string(document), nil
+}
+
+// MarshalJSON keeps the document as it is and encodes the empty ones as null
+func (document JSONDocument) MarshalJSON() ([]byte, error) {
+	if len(document) == 0 {
+		return []byte("null"), nil
+	}
+	return document, nil
+}
+
+// UnmarshalJSON keeps the document as it is
+func (document *JSONDocument) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*document = nil
+		return nil
+	}
+	*document = append(JSONDocument{}, data...)
+	return nil
+}
+
+// ##########################
+// #	Swagger/API Models	#
+// ##########################
+// The following models are used for swagger documentation
+
+// AuditVerification is the result of checking the hash chain of the audit log
+type AuditVerification struct {
+	Valid          bool   `json:"valid" example:"false"`
+	CheckedEntries int64  `json:"checked_entries" example:"1250"`
+	BrokenSequence *int64 `json:"broken_sequence,omitempty" example:"731"` // the first entry whose hash or link does not match
+	Reason         string `json:"reason,omitempty" example:"the hash of the entry does not match its content"`
+}
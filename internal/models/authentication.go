@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SubscriberSession keeps track of the refresh tokens issued for a subscriber.
+// Each login creates a new session. Refreshing the tokens rotates the RefreshTokenID
+// so a refresh token can only be used once
+type SubscriberSession struct {
+	gorm.Model
+	ID                string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"session_id"`
+	SubscriberID      string     `gorm:"type:uuid;not null;index" json:"subscriber_id"`
+	OrganizationID    string     `gorm:"type:uuid;not null;index" json:"organization_id"`
+	SubscriberGroupID string     `gorm:"type:uuid;not null;index" json:"subscriber_group_id"`
+	RefreshTokenID    string     `gorm:"type:uuid;not null;uniqueIndex" json:"-"`
+	ExpiresAt         time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevokedAt         *time.Time `gorm:"index" json:"revoked_at"`
+	ClientIP          string     `json:"client_ip"`
+	UserAgent         string     `json:"user_agent"`
+}
+
+// ##########################
+// #	Swagger/API Models	#
+// ##########################
+// The following models are used for swagger documentation
+type LoginRequest struct {
+	Username string `json:"username" example:"subscriber1"`
+	Password string `json:"password" example:"S3cret!"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RevokeTokenRequest accepts either an access token or a refresh token.
+// In both cases the whole session is revoked
+type RevokeTokenRequest struct {
+	Token string `json:"token"`
+}
+
+type TokenPair struct {
+	TokenType             string    `json:"token_type" example:"Bearer"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// The lifecycle statuses of the catalog entities. An entity is created as draft,
+// it can be used only while it is active and retired is the final status
+const (
+	LifecycleStatusDraft   = "draft"
+	LifecycleStatusActive  = "active"
+	LifecycleStatusRetired = "retired"
+)
+
+// ##########################
+// #	Swagger/API Models	#
+// ##########################
+// The following models are used for swagger documentation.
+// The catalog resources follow the shapes of TMF620 (Product Catalog Management)
+// so the BSS tools can consume them
+
+// TimePeriod is the validity period of a catalog entity. An empty end means that it is valid forever
+type TimePeriod struct {
+	StartDateTime *time.Time `json:"startDateTime,omitempty" example:"2024-01-01T00:00:00Z"`
+	EndDateTime   *time.Time `json:"endDateTime,omitempty" example:"2024-12-31T23:59:59Z"`
+}
+
+// ProductSpecificationRef is a reference to a product offering specification
+type ProductSpecificationRef struct {
+	ID   string `json:"id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Href string `json:"href,omitempty" example:"/catalog/productSpecification/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Name string `json:"name,omitempty" example:"Fiber Internet"`
+}
+
+// CatalogFilter contains the filters of the catalog listing endpoints. Empty fields are not applied
+type CatalogFilter struct {
+	Name            string
+	LifecycleStatus string
+	Type            string     // only used for the specifications
+	SpecificationID string     // only used for the offerings
+	ValidAt         *time.Time // the entities that are valid at the given time
+	Offset          int
+	Limit           int
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// CharacteristicSpecification defines the type of the characteristic value of the offerings
+// that refer to a specification and the constraints of the value. The constraints are stored
+// in their normalized form, e.g. 10Mbps is stored as 10000000bps
+type CharacteristicSpecification struct {
+	ValueType     string     `gorm:"" json:"valueType,omitempty" example:"bandwidth"` // valid values: integer, decimal, boolean, enum, duration, bandwidth, data_volume
+	MinValue      string     `gorm:"" json:"minValue,omitempty" example:"1000000bps"`
+	MaxValue      string     `gorm:"" json:"maxValue,omitempty" example:"1000000000bps"`
+	AllowedValues StringList `gorm:"type:jsonb" json:"allowedValues,omitempty" swaggertype:"array,string"`
+}
+
+// StringList is a list of strings stored as a JSON array
+type StringList []string
+
+// Scan implements the sql.Scanner interface
+func (list *StringList) Scan(value interface{}) error {
+	var data []byte
+	switch typed := value.(type) {
+	case nil:
+		*list = nil
+		return nil
+	case []byte:
+		data = typed
+	case string:
+		data = []byte(typed)
+	default:
+		return fmt.Errorf("can not scan %T into a string list", value)
+	}
+
+	return json.Unmarshal(data, (*[]string)(list))
+}
+
+// Value implements the driver.Valuer interface
+func (list StringList) Value() (driver.Value, error) {
+	if list == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal([]string(list))
+	return string(data), err
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The valid values of LedgerTransaction.Type
+const (
+	LedgerCredit     = "credit"     // top-up of the organization balance
+	LedgerDebit      = "debit"      // charge of the organization balance
+	LedgerAdjustment = "adjustment" // manual correction of the organization balance by an operator
+	LedgerReversal   = "reversal"   // cancellation of a previous transaction
+)
+
+// The accounts that the ledger entries are posted to. Each organization has its own
+// balance account and the others are the counter accounts of the operations
+const (
+	LedgerAccountOrganization = "organization_balance"
+	LedgerAccountTopUp        = "top_up"
+	LedgerAccountCharges      = "charges"
+	LedgerAccountAdjustments  = "adjustments"
+)
+
+// LedgerTransaction is a single operation on the balance of an organization.
+// Each transaction is posted as a pair of LedgerEntry rows which sum up to zero,
+// one on the organization balance account and one on the counter account of the operation.
+// The RequestKey makes the operations idempotent, retrying a request with the same key
+// returns the already created transaction instead of changing the balance twice
+type LedgerTransaction struct {
+	gorm.Model
+	ID                    string        `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"ledger_transaction_id"`
+	OrganizationID        string        `gorm:"type:uuid;not null;index;uniqueIndex:organization_request_key_idx" json:"organization_id"`
+	RequestKey            string        `gorm:"not null;uniqueIndex:organization_request_key_idx" json:"request_key"`
+	Type                  string        `gorm:"not null;index" json:"type"`
+	Currency              string        `gorm:"type:char(3);not null;default:''" json:"currency"`
+	Amount                Decimal       `gorm:"not null" json:"amount"` // signed change of the organization balance
+	BalanceAfter          Decimal       `gorm:"not null" json:"balance_after"`
+	Description           string        `json:"description"`
+	ReversedTransactionID *string       `gorm:"type:uuid;uniqueIndex" json:"reversed_transaction_id"`
+	Entries               []LedgerEntry `gorm:"foreignKey:TransactionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"entries"`
+}
+
+// LedgerEntry is one side of a LedgerTransaction. Positive amounts increase the account
+type LedgerEntry struct {
+	gorm.Model
+	ID             string  `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"ledger_entry_id"`
+	TransactionID  string  `gorm:"type:uuid;not null;index" json:"ledger_transaction_id"`
+	OrganizationID string  `gorm:"type:uuid;not null;index:organization_account_idx" json:"organization_id"`
+	Account        string  `gorm:"not null;index:organization_account_idx" json:"account"`
+	Amount         Decimal `gorm:"not null" json:"amount"`
+}
+
+// ##########################
+// #	Swagger/API Models	#
+// ##########################
+// The following models are used for swagger documentation
+
+// BalanceOperationRequest is used for credit, debit, adjustment and reversal requests.
+// The amount of credit and debit must be positive, the amount of adjustment is signed
+// and it is ignored for reversals. The currency is optional but if it is given, it must be
+// the currency of the organization balance
+type BalanceOperationRequest struct {
+	RequestKey  string  `json:"request_key" example:"top-up-2024-01-0001"` // Unique key of the request within the organization to make retries safe
+	Amount      Decimal `json:"amount" swaggertype:"string" example:"100.50"`
+	Currency    string  `json:"currency" example:"IRR"`
+	Description string  `json:"description" example:"monthly top-up"`
+}
+
+// LedgerTransactionResponse represents a ledger transaction without its database related fields
+type LedgerTransactionResponse struct {
+	ID                    string                `json:"ledger_transaction_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	OrganizationID        string                `json:"organization_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	RequestKey            string                `json:"request_key" example:"top-up-2024-01-0001"`
+	Type                  string                `json:"type" example:"credit"`
+	Currency              string                `json:"currency" example:"IRR"`
+	Amount                Decimal               `json:"amount" swaggertype:"string" example:"100.50"`
+	BalanceAfter          Decimal               `json:"balance_after" swaggertype:"string" example:"100.50"`
+	Description           string                `json:"description" example:"monthly top-up"`
+	ReversedTransactionID *string               `json:"reversed_transaction_id"`
+	CreatedAt             time.Time             `json:"created_at"`
+	Entries               []LedgerEntryResponse `json:"entries"`
+}
+
+type LedgerEntryResponse struct {
+	Account string  `json:"account" example:"organization_balance"`
+	Amount  Decimal `json:"amount" swaggertype:"string" example:"100.50"`
+}
+
+// BalanceReconciliation compares the stored balance of an organization with the sum of its ledger
+type BalanceReconciliation struct {
+	OrganizationID string  `json:"organization_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Currency       string  `json:"currency" example:"IRR"`
+	Balance        Decimal `json:"balance" swaggertype:"string" example:"100.50"`        // the balance stored on the organization
+	LedgerBalance  Decimal `json:"ledger_balance" swaggertype:"string" example:"100.50"` // the sum of the organization balance account entries
+	Difference     Decimal `json:"difference" swaggertype:"string" example:"0"`
+	Consistent     bool    `json:"consistent" example:"true"`
+}
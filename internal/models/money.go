@@ -0,0 +1,283 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// decimalScale is the number of fractional digits kept by Decimal.
+// It matches the scale of the DECIMAL columns in the database
+const decimalScale = 4
+
+// decimalFactor is 10^decimalScale
+const decimalFactor = 10000
+
+var (
+	// ErrInvalidDecimal is returned when a value can not be converted to Decimal without losing precision
+	ErrInvalidDecimal = errors.New("invalid decimal value")
+
+	// ErrCurrencyMismatch is returned when the amounts of different currencies are combined
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+
+	// ErrDecimalOverflow is returned when the result of an operation is out of the range of Decimal
+	ErrDecimalOverflow = errors.New("decimal overflow")
+)
+
+// Decimal is a fixed-point number with 4 fractional digits which is used for the amounts of money.
+// It is stored in the database as DECIMAL(19,4) and it is encoded in JSON as a string,
+// so the amounts are never converted to floating point numbers on the way
+type Decimal struct {
+	units int64 // the value multiplied by decimalFactor
+}
+
+// NewDecimal returns the Decimal of the given integer value
+func NewDecimal(value int64) Decimal {
+	return Decimal{units: value * decimalFactor}
+}
+
+// ParseDecimal parses a decimal number like "-1250.75". Values with more than 4 fractional
+// digits are rejected instead of being rounded
+func ParseDecimal(value string) (Decimal, error) {
+	text := strings.TrimSpace(value)
+	negative := strings.HasPrefix(text, "-")
+	if negative || strings.HasPrefix(text, "+") {
+		text = text[1:]
+	}
+
+	integerPart, fractionalPart, _ := strings.Cut(text, ".")
+	if integerPart == "" && fractionalPart == "" {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+	if len(fractionalPart) > decimalScale {
+		// trailing zeros do not change the value, DECIMAL columns may return them
+		fractionalPart = strings.TrimRight(fractionalPart, "0")
+		if len(fractionalPart) > decimalScale {
+			return Decimal{}, fmt.Errorf("%w: %q has more than %d fractional digits", ErrInvalidDecimal, value, decimalScale)
+		}
+	}
+	fractionalPart += strings.Repeat("0", decimalScale-len(fractionalPart))
+
+	if integerPart == "" {
+		integerPart = "0"
+	}
+	if strings.ContainsAny(integerPart+fractionalPart, "+-") {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+
+	units, err := strconv.ParseInt(integerPart+fractionalPart, 10, 64)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+
+	if negative {
+		units = -units
+	}
+
+	return Decimal{units: units}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if the value is invalid.
+// It should only be used for constant values
+func MustParseDecimal(value string) Decimal {
+	decimal, err := ParseDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return decimal
+}
+
+// Add returns the sum of the two values. An error is returned instead of a wrapped value
+// if the sum is out of the range of Decimal
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	if (other.units > 0 && d.units > math.MaxInt64-other.units) || (other.units < 0 && d.units < math.MinInt64-other.units) {
+		return Decimal{}, fmt.Errorf("%w: %s + %s", ErrDecimalOverflow, d, other)
+	}
+	return Decimal{units: d.units + other.units}, nil
+}
+
+// Sub returns the difference of the two values. An error is returned instead of a wrapped value
+// if the difference is out of the range of Decimal
+func (d Decimal) Sub(other Decimal) (Decimal, error) {
+	if (other.units < 0 && d.units > math.MaxInt64+other.units) || (other.units > 0 && d.units < math.MinInt64+other.units) {
+		return Decimal{}, fmt.Errorf("%w: %s - %s", ErrDecimalOverflow, d, other)
+	}
+	return Decimal{units: d.units - other.units}, nil
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{units: -d.units}
+}
+
+func (d Decimal) Abs() Decimal {
+	if d.units < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+// Cmp returns -1, 0 or +1 if d is less than, equal to or greater than other
+func (d Decimal) Cmp(other Decimal) int {
+	switch {
+	case d.units < other.units:
+		return -1
+	case d.units > other.units:
+		return 1
+	}
+	return 0
+}
+
+func (d Decimal) LessThan(other Decimal) bool {
+	return d.units < other.units
+}
+
+func (d Decimal) IsZero() bool {
+	return d.units == 0
+}
+
+func (d Decimal) IsNegative() bool {
+	return d.units < 0
+}
+
+func (d Decimal) IsPositive() bool {
+	return d.units > 0
+}
+
+// String returns the value without the trailing fractional zeros, e.g. "100" or "-0.25"
+func (d Decimal) String() string {
+	units := d.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+	}
+
+	// the absolute value is calculated in uint64 to support math.MinInt64
+	absolute := uint64(units)
+	if units < 0 {
+		absolute = uint64(-(units + 1)) + 1
+	}
+
+	integerPart := absolute / decimalFactor
+	fractionalPart := absolute % decimalFactor
+	if fractionalPart == 0 {
+		return fmt.Sprintf("%s%d", sign, integerPart)
+	}
+
+	fraction := strings.TrimRight(fmt.Sprintf("%0*d", decimalScale, fractionalPart), "0")
+	return fmt.Sprintf("%s%d.%s", sign, integerPart, fraction)
+}
+
+// MarshalJSON encodes the value as a string to avoid the precision loss of the JSON numbers
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts both strings and numbers, so the clients that used to send
+// the amounts as numbers keep working
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "null" || text == "" {
+		*d = Decimal{}
+		return nil
+	}
+
+	decimal, err := ParseDecimal(text)
+	if err != nil {
+		return err
+	}
+
+	*d = decimal
+	return nil
+}
+
+// Scan implements sql.Scanner. DECIMAL columns are returned as text by the postgres driver
+func (d *Decimal) Scan(value interface{}) error {
+	switch typedValue := value.(type) {
+	case nil:
+		*d = Decimal{}
+		return nil
+	case []byte:
+		return d.scanText(string(typedValue))
+	case string:
+		return d.scanText(typedValue)
+	case int64:
+		*d = NewDecimal(typedValue)
+		return nil
+	case float64:
+		*d = Decimal{units: int64(math.Round(typedValue * decimalFactor))}
+		return nil
+	}
+
+	return fmt.Errorf("%w: can not scan %T", ErrInvalidDecimal, value)
+}
+
+func (d *Decimal) scanText(value string) error {
+	decimal, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+
+	*d = decimal
+	return nil
+}
+
+// Value implements driver.Valuer
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// GormDataType is used by gorm to determine the column type in migrations
+func (Decimal) GormDataType() string {
+	return "decimal(19,4)"
+}
+
+// Money is an amount in a specific currency. The currency is the ISO-4217 alphabetic code like IRR or USD.
+// While embedding it into a model, a prefix should be given so the columns are named after the field
+type Money struct {
+	Amount   Decimal `gorm:"not null;default:0" json:"amount" swaggertype:"string" example:"100.50"`
+	Currency string  `gorm:"type:char(3);not null;default:''" json:"currency" example:"IRR"`
+}
+
+// NewMoney returns the money of the given amount and currency
+func NewMoney(amount Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Add returns the sum of the two amounts. Both of them must be in the same currency
+// and the sum must be in the range of Decimal
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	amount, err := m.Amount.Add(other.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Amount, m.Currency)
+}
+
+// IsValidCurrency checks whether the given currency is in the format of
+// the ISO-4217 alphabetic codes, three upper case letters
+func IsValidCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+
+	for _, letter := range currency {
+		if letter < 'A' || letter > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	type testCase struct {
+		name           string
+		value          string
+		expectedString string
+		expectedError  error
+	}
+
+	testCases := []testCase{
+		{
+			name:           "an integer is given. In this case, it should be parsed",
+			value:          "100",
+			expectedString: "100",
+		},
+		{
+			name:           "a negative value with fraction is given. In this case, it should be parsed",
+			value:          "-1250.75",
+			expectedString: "-1250.75",
+		},
+		{
+			name:           "a value returned by a DECIMAL(19,4) column is given. In this case, the trailing zeros should be ignored",
+			value:          "0.1000",
+			expectedString: "0.1",
+		},
+		{
+			name:           "a value without integer part is given. In this case, it should be parsed",
+			value:          ".5",
+			expectedString: "0.5",
+		},
+		{
+			name:          "a value with more than 4 fractional digits is given. In this case, it should be rejected instead of being rounded",
+			value:         "0.00001",
+			expectedError: ErrInvalidDecimal,
+		},
+		{
+			name:          "a value in scientific notation is given. In this case, it should be rejected",
+			value:         "1e5",
+			expectedError: ErrInvalidDecimal,
+		},
+		{
+			name:          "a value with two signs is given. In this case, it should be rejected",
+			value:         "--5",
+			expectedError: ErrInvalidDecimal,
+		},
+		{
+			name:          "an empty value is given. In this case, it should be rejected",
+			value:         "",
+			expectedError: ErrInvalidDecimal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			decimal, err := ParseDecimal(tc.value)
+			assert.ErrorIs(t, err, tc.expectedError)
+			if tc.expectedError == nil {
+				assert.Equal(t, tc.expectedString, decimal.String())
+			}
+		})
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	// 0.1 + 0.2 is the classic case which is not exact in floating point numbers
+	sum, err := MustParseDecimal("0.1").Add(MustParseDecimal("0.2"))
+	assert.Nil(t, err)
+	assert.Equal(t, MustParseDecimal("0.3"), sum)
+	assert.Equal(t, "-0.3", sum.Neg().String())
+	assert.Equal(t, "0.3", sum.Neg().Abs().String())
+	assert.True(t, sum.Neg().LessThan(sum))
+	difference, err := sum.Sub(MustParseDecimal("0.3"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, difference.Cmp(Decimal{}))
+}
+
+func TestDecimalOverflow(t *testing.T) {
+	maximum := Decimal{units: math.MaxInt64}
+	minimum := Decimal{units: math.MinInt64}
+
+	type testCase struct {
+		name          string
+		operation     func() (Decimal, error)
+		expected      Decimal
+		expectedError error
+	}
+
+	testCases := []testCase{
+		{
+			name:          "a positive value is added to the maximum. In this case, it should be rejected instead of wrapping to a negative value",
+			operation:     func() (Decimal, error) { return maximum.Add(MustParseDecimal("0.0001")) },
+			expectedError: ErrDecimalOverflow,
+		},
+		{
+			name:          "a negative value is added to the minimum. In this case, it should be rejected instead of wrapping to a positive value",
+			operation:     func() (Decimal, error) { return minimum.Add(MustParseDecimal("-0.0001")) },
+			expectedError: ErrDecimalOverflow,
+		},
+		{
+			name:          "a negative value is subtracted from the maximum. In this case, it should be rejected",
+			operation:     func() (Decimal, error) { return maximum.Sub(MustParseDecimal("-0.0001")) },
+			expectedError: ErrDecimalOverflow,
+		},
+		{
+			name:          "a positive value is subtracted from the minimum. In this case, it should be rejected",
+			operation:     func() (Decimal, error) { return minimum.Sub(MustParseDecimal("0.0001")) },
+			expectedError: ErrDecimalOverflow,
+		},
+		{
+			name:      "a negative value is added to the maximum. In this case, the sum should be calculated",
+			operation: func() (Decimal, error) { return maximum.Add(MustParseDecimal("-0.0001")) },
+			expected:  Decimal{units: math.MaxInt64 - 1},
+		},
+		{
+			name:      "the minimum is subtracted from the minimum. In this case, the difference should be zero",
+			operation: func() (Decimal, error) { return minimum.Sub(minimum) },
+			expected:  Decimal{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := tc.operation()
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	type testCase struct {
+		name          string
+		input         string
+		expectedMoney Money
+		expectedJSON  string
+	}
+
+	testCases := []testCase{
+		{
+			name:          "the amount is given as a string. In this case, it should be decoded without precision loss",
+			input:         `{"amount":"1234567890.1234","currency":"IRR"}`,
+			expectedMoney: NewMoney(MustParseDecimal("1234567890.1234"), "IRR"),
+			expectedJSON:  `{"amount":"1234567890.1234","currency":"IRR"}`,
+		},
+		{
+			name:          "the amount is given as a number by an older client. In this case, it should be accepted and encoded as a string",
+			input:         `{"amount":10.5,"currency":"USD"}`,
+			expectedMoney: NewMoney(MustParseDecimal("10.5"), "USD"),
+			expectedJSON:  `{"amount":"10.5","currency":"USD"}`,
+		},
+		{
+			name:          "the amount is not given. In this case, it should be zero",
+			input:         `{"currency":"USD"}`,
+			expectedMoney: NewMoney(Decimal{}, "USD"),
+			expectedJSON:  `{"amount":"0","currency":"USD"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var money Money
+			assert.Nil(t, json.Unmarshal([]byte(tc.input), &money))
+			assert.Equal(t, tc.expectedMoney, money)
+
+			encoded, err := json.Marshal(money)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedJSON, string(encoded))
+		})
+	}
+}
+
+func TestMoneyAdd(t *testing.T) {
+	_, err := NewMoney(NewDecimal(1), "IRR").Add(NewMoney(NewDecimal(1), "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	sum, err := NewMoney(NewDecimal(1), "IRR").Add(NewMoney(MustParseDecimal("0.25"), "IRR"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1.25 IRR", sum.String())
+
+	_, err = NewMoney(Decimal{units: math.MaxInt64}, "IRR").Add(NewMoney(NewDecimal(1), "IRR"))
+	assert.ErrorIs(t, err, ErrDecimalOverflow)
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -9,9 +11,9 @@ type Organization struct {
 	ID                       string              `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"organization_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
 	Details                  OrganizationDetails `gorm:"foreignKey:OrganizationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"organization_details"`
 	Owner                    OrganizationOwner   `gorm:"foreignKey:OrganizationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"organization_owner"`
-	Balance                  float64             `gorm:"not null;index" json:"balance"`
+	Balance                  Money               `gorm:"embedded;embeddedPrefix:balance_" json:"balance"`
 	AllowNagativeBalance     bool                `gorm:"not null;index" json:"allow_negative_balance"`
-	NegativeBalanceThreshold float64             `gorm:"not null;index" json:"negative_balance_threshold"`
+	NegativeBalanceThreshold Money               `gorm:"embedded;embeddedPrefix:negative_balance_threshold_" json:"negative_balance_threshold"`
 }
 
 type OrganizationDetails struct {
@@ -40,8 +42,53 @@ type OrganizationOwner struct {
 
 // This model is used while listing the organizations
 type OrganizationShortInfo struct {
-	ID   string `json:"organization_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"` // This field determines the unique id of the organization. The id is in uuid v4 format
-	Name string `json:"organization_name" example:"sample organization"`                // This field determines the name of the organization
+	ID        string     `json:"organization_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"` // This field determines the unique id of the organization. The id is in uuid v4 format
+	Name      string     `json:"organization_name" example:"sample organization"`                // This field determines the name of the organization
+	OwnerType string     `json:"owner_type" example:"legal"`                                     // valid values are: legal, individual
+	Balance   Money      `json:"balance"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at"` // null unless the organization is soft deleted
+}
+
+// The sort keys of the organization list. The list is sorted in ascending order by default
+// and a - before the key, like -balance, sorts it in descending order
+const (
+	OrganizationSortName      = "name"
+	OrganizationSortCreatedAt = "created_at"
+	OrganizationSortBalance   = "balance"
+)
+
+// The deleted states of the organization list filter
+const (
+	OrganizationDeletedExclude = "exclude" // only the organizations that are not deleted, the default
+	OrganizationDeletedInclude = "include" // both the soft deleted organizations and the others
+	OrganizationDeletedOnly    = "only"    // only the soft deleted organizations
+)
+
+// OrganizationFilter contains the filters of the organization list. Empty fields are not applied
+type OrganizationFilter struct {
+	NamePrefix  string // case-insensitive prefix of the organization name
+	OwnerType   string
+	Deleted     string // one of the OrganizationDeleted states, exclude if empty
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	BalanceMin  *Decimal // the balance amounts are compared regardless of their currencies
+	BalanceMax  *Decimal
+}
+
+// OrganizationListRequest selects a page of the organization list
+type OrganizationListRequest struct {
+	Filter OrganizationFilter
+	Sort   string // one of the OrganizationSort keys with an optional - prefix, created_at if empty
+	Limit  int
+	Cursor string // the next cursor of the previous page, empty for the first page
+}
+
+// OrganizationPage is a page of the organization list
+type OrganizationPage struct {
+	Items      []OrganizationShortInfo `json:"items"`
+	Total      int64                   `json:"total" example:"42"`                     // the number of the organizations that match the filters in all of the pages
+	NextCursor string                  `json:"next_cursor" example:"eyJzIjoibmFtZSJ9"` // the cursor of the next page, empty on the last page
 }
 
 // The following models are used to represent the raw details of the organization
@@ -51,9 +98,9 @@ type OrganizationResponse struct {
 	ID                       string                      `json:"organization_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
 	Details                  OrganizationDetailsResponse `json:"organization_details"`
 	Owner                    OrganizationOwnerResponse   `json:"organization_owner"`
-	Balance                  float64                     `json:"balance"`
+	Balance                  Money                       `json:"balance"`
 	AllowNagativeBalance     bool                        `json:"allow_negative_balance"`
-	NegativeBalanceThreshold float64                     `json:"negative_balance_threshold"`
+	NegativeBalanceThreshold Money                       `json:"negative_balance_threshold"`
 }
 
 type OrganizationDetailsResponse struct {
@@ -75,3 +122,24 @@ type OrganizationOwnerResponse struct {
 }
 
 // End of Reponse models!
+
+// ##########################
+// #	Swagger/API Models	#
+// ##########################
+// The following models are used for swagger documentation
+
+// OrganizationProfilePatch is a JSON Merge Patch (RFC 7396) of the organization profile.
+// Only the given fields are changed and null clears the field
+type OrganizationProfilePatch struct {
+	Details *OrganizationDetailsResponse `json:"organization_details"`
+	Owner   *OrganizationOwnerResponse   `json:"organization_owner"`
+}
+
+// BalancePolicyRequest is used to change the negative balance policy of an organization.
+// Fields that are not given are left untouched. The threshold is how far below zero the balance may go,
+// so it can not be negative. It is in the currency of the organization balance, the currency is only checked if it is given
+type BalancePolicyRequest struct {
+	AllowNagativeBalance     *bool    `json:"allow_negative_balance" example:"true"`
+	NegativeBalanceThreshold *Decimal `json:"negative_balance_threshold" swaggertype:"string" example:"1000"`
+	Currency                 string   `json:"currency" example:"IRR"`
+}
@@ -20,3 +20,61 @@ type PermissionAPI struct {
 	PermissionValue    string `json:"permission_value" example:"yes"`
 	PermissionCategory string `json:"permission_category" example:"REPORT_LEVEL"`
 }
+
+// PermissionCatalog is the versioned definition of the permissions that can be given to the subscriber groups.
+// It is loaded from the catalog file and is also the response of the permission catalog API
+type PermissionCatalog struct {
+	Version     string                 `json:"version" yaml:"version" example:"1"`
+	Permissions []PermissionDefinition `json:"permissions" yaml:"permissions"`
+}
+
+// PermissionDefinition defines a permission of the catalog and the values it accepts.
+// The value types are the same as the product characteristic types
+type PermissionDefinition struct {
+	PermissionCategory string     `json:"permission_category" yaml:"permission_category" example:"REPORT_LEVEL"`
+	PermissionName     string     `json:"permission_name" yaml:"permission_name" example:"CAN_VIEW_PAYMENT_HISTORY"`
+	Description        string     `json:"description,omitempty" yaml:"description" example:"allows the subscriber to see the payment history"`
+	ValueType          string     `json:"value_type" yaml:"value_type" example:"enum"`
+	MinValue           string     `json:"min_value,omitempty" yaml:"min_value"`
+	MaxValue           string     `json:"max_value,omitempty" yaml:"max_value"`
+	AllowedValues      StringList `json:"allowed_values,omitempty" yaml:"allowed_values" swaggertype:"array,string"`
+}
+
+const (
+	PermissionDecisionAllow = "allow"
+	PermissionDecisionDeny  = "deny"
+)
+
+// PermissionEvaluationRequest contains the permissions that should be evaluated for a subscriber.
+// If the value of a permission is given, the subscriber must hold the permission with the same value,
+// otherwise any value other than false or no allows it
+type PermissionEvaluationRequest struct {
+	Permissions []PermissionAPI `json:"permissions"`
+}
+
+// PermissionEvaluationResponse contains the decision of each requested permission in the request order
+type PermissionEvaluationResponse struct {
+	SubscriberID      string               `json:"subscriber_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	SubscriberGroupID string               `json:"subscriber_group_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7b"`
+	Decisions         []PermissionDecision `json:"decisions"`
+}
+
+// PermissionDecision is the result of evaluating a permission. MatchedRule is the permission
+// of the subscriber group that the decision is based on and it is empty if the group does not have it
+type PermissionDecision struct {
+	PermissionCategory string          `json:"permission_category" example:"REPORT_LEVEL"`
+	PermissionName     string          `json:"permission_name" example:"CAN_VIEW_PAYMENT_HISTORY"`
+	RequestedValue     string          `json:"requested_value,omitempty" example:"yes"`
+	Decision           string          `json:"decision" example:"allow"`
+	Reason             string          `json:"reason" example:"the subscriber group grants the permission"`
+	MatchedRule        *PermissionRule `json:"matched_rule,omitempty"`
+}
+
+// PermissionRule is a permission of the subscriber group
+type PermissionRule struct {
+	PermissionID       string `json:"permission_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7c"`
+	SubscriberGroupID  string `json:"subscriber_group_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7b"`
+	PermissionCategory string `json:"permission_category" example:"REPORT_LEVEL"`
+	PermissionName     string `json:"permission_name" example:"CAN_VIEW_PAYMENT_HISTORY"`
+	PermissionValue    string `json:"permission_value" example:"yes"`
+}
@@ -1,14 +1,55 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type ProductOffering struct {
 	gorm.Model
-	ID                      string `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"product_offering_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
-	Name                    string `gorm:"not null;index;unique" json:"product_offering_name"`
-	PersianName             string `gorm:"index;unique" json:"product_offering_persian_name"`
-	CharacteristicValue     string `gorm:"" json:"product_offering_characteristic_value"`
-	CharacteristicValueType string `gorm:"" json:"product_offering_characteristic_value_type"`
-	SpecificationID         string `gorm:"type:uuid" json:"product_offering_specification_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
-	Description             string `json:"product_offering_description"`
+	ID                      string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"product_offering_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Name                    string     `gorm:"not null;index;unique" json:"product_offering_name"`
+	PersianName             string     `gorm:"index;unique" json:"product_offering_persian_name"`
+	CharacteristicValue     string     `gorm:"" json:"product_offering_characteristic_value"` // normalized form of the value, e.g. 100000000bps for 100Mbps
+	CharacteristicValueType string     `gorm:"" json:"product_offering_characteristic_value_type"`
+	SpecificationID         string     `gorm:"type:uuid" json:"product_offering_specification_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Description             string     `json:"product_offering_description"`
+	LifecycleStatus         string     `gorm:"not null;index;default:'draft'" json:"product_offering_lifecycle_status"`
+	ValidFrom               time.Time  `gorm:"not null;index;default:current_timestamp()" json:"product_offering_valid_from"`
+	ValidTo                 *time.Time `gorm:"index" json:"product_offering_valid_to"`
+}
+
+// ##########################
+// #	Swagger/API Models	#
+// ##########################
+// The following models are used for swagger documentation
+
+// ProductOfferingResource is the TMF620 representation of a product offering
+type ProductOfferingResource struct {
+	ID                      string                   `json:"id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Href                    string                   `json:"href" example:"/catalog/productOffering/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Name                    string                   `json:"name" example:"Fiber 100M Monthly"`
+	PersianName             string                   `json:"persianName,omitempty"`
+	Description             string                   `json:"description,omitempty"`
+	LifecycleStatus         string                   `json:"lifecycleStatus" example:"active"`
+	ValidFor                TimePeriod               `json:"validFor"`
+	LastUpdate              time.Time                `json:"lastUpdate"`
+	ProductSpecification    *ProductSpecificationRef `json:"productSpecification,omitempty"`
+	CharacteristicValue     string                   `json:"characteristicValue,omitempty" example:"100000000bps"` // normalized by its type
+	CharacteristicValueType string                   `json:"characteristicValueType,omitempty" example:"bandwidth"`
+	Type                    string                   `json:"@type" example:"ProductOffering"`
+}
+
+// ProductOfferingRequest is used to create and to partially update a product offering.
+// Fields that are not given are left untouched while updating
+type ProductOfferingRequest struct {
+	Name                    *string                  `json:"name" example:"Fiber 100M Monthly"`
+	PersianName             *string                  `json:"persianName"`
+	Description             *string                  `json:"description"`
+	LifecycleStatus         *string                  `json:"lifecycleStatus" example:"draft"`
+	ValidFor                *TimePeriod              `json:"validFor"`
+	ProductSpecification    *ProductSpecificationRef `json:"productSpecification"`
+	CharacteristicValue     *string                  `json:"characteristicValue" example:"100Mbps"`
+	CharacteristicValueType *string                  `json:"characteristicValueType" example:"bandwidth"`
 }
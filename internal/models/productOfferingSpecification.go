@@ -1,12 +1,54 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type ProductOfferingSpecification struct {
 	gorm.Model
-	ID          string `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"product_offering_specification_id"`
-	Name        string `gorm:"not null;index;unique" json:"product_offering_specification_name"`
-	PersianName string `gorm:"index;unique" json:"product_offering_specification_persian_name"`
-	Type        string `gorm:"not null;" json:"product_offering_specification_type"` // valid values: product, service
-	Description string `json:"product_offering_specification_description"`
+	ID              string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"product_offering_specification_id"`
+	Name            string     `gorm:"not null;index;unique" json:"product_offering_specification_name"`
+	PersianName     string     `gorm:"index;unique" json:"product_offering_specification_persian_name"`
+	Type            string     `gorm:"not null;" json:"product_offering_specification_type"` // valid values: product, service
+	Description     string     `json:"product_offering_specification_description"`
+	LifecycleStatus string     `gorm:"not null;index;default:'draft'" json:"product_offering_specification_lifecycle_status"`
+	ValidFrom       time.Time  `gorm:"not null;index;default:current_timestamp()" json:"product_offering_specification_valid_from"`
+	ValidTo         *time.Time `gorm:"index" json:"product_offering_specification_valid_to"`
+
+	// Characteristic constrains the characteristic values of the offerings that refer to this specification
+	Characteristic CharacteristicSpecification `gorm:"embedded;embeddedPrefix:characteristic_" json:"product_offering_specification_characteristic"`
+}
+
+// ##########################
+// #	Swagger/API Models	#
+// ##########################
+// The following models are used for swagger documentation
+
+// ProductSpecificationResource is the TMF620 representation of a product offering specification
+type ProductSpecificationResource struct {
+	ID              string                       `json:"id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Href            string                       `json:"href" example:"/catalog/productSpecification/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Name            string                       `json:"name" example:"Fiber Internet"`
+	PersianName     string                       `json:"persianName,omitempty"`
+	Description     string                       `json:"description,omitempty"`
+	SpecType        string                       `json:"productSpecificationType" example:"service"` // valid values: product, service
+	LifecycleStatus string                       `json:"lifecycleStatus" example:"active"`
+	ValidFor        TimePeriod                   `json:"validFor"`
+	LastUpdate      time.Time                    `json:"lastUpdate"`
+	Characteristic  *CharacteristicSpecification `json:"productSpecCharacteristic,omitempty"`
+	Type            string                       `json:"@type" example:"ProductSpecification"`
+}
+
+// ProductSpecificationRequest is used to create and to partially update a product offering specification.
+// Fields that are not given are left untouched while updating
+type ProductSpecificationRequest struct {
+	Name            *string                      `json:"name" example:"Fiber Internet"`
+	PersianName     *string                      `json:"persianName"`
+	Description     *string                      `json:"description"`
+	SpecType        *string                      `json:"productSpecificationType" example:"service"`
+	LifecycleStatus *string                      `json:"lifecycleStatus" example:"draft"`
+	ValidFor        *TimePeriod                  `json:"validFor"`
+	Characteristic  *CharacteristicSpecification `json:"productSpecCharacteristic"` // replaces the whole characteristic specification when given
 }
@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
 
 type Subscriber struct {
 	gorm.Model
@@ -8,8 +12,8 @@ type Subscriber struct {
 	Details           SubscriberDetails `gorm:"foreignKey:SubscriberID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"subscriber_details"`
 	Credentials       Credentials       `gorm:"foreignKey:SubscriberID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"subscriber_credentials"`
 	OrganizationID    string            `gorm:"type:uuid;not null;index;" json:"organization_id"`
-	SubscriberGroupID string            `gorm:"type:uuid;not null;index" json:"subsdriber_group_id"`
-	// Offers            []Offer
+	SubscriberGroupID string            `gorm:"type:uuid;not null;index" json:"subscriber_group_id"`
+	Subscriptions     []Subscription    `gorm:"foreignKey:SubscriberID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"` // managed by the subscription endpoints only
 }
 
 type SubscriberDetails struct {
@@ -21,14 +25,59 @@ type SubscriberDetails struct {
 	PassportID   string `gorm:"index;unique" json:"passport_id"`
 	Mobile       string `gorm:"not null;index;unique" json:"subscriber_mobile"`
 	Phone        string `gorm:"index;unique" json:"subscriber_phone"`
-	SubscriberID string `gorm:"type:uuid;not null;index" json:"subsdriber_id"`
+	SubscriberID string `gorm:"type:uuid;not null;index" json:"subscriber_id"`
 }
 
+// Credentials keeps the password as an argon2id hash in PHC string format.
+// The plain password is only accepted from the API requests and is hashed before being stored.
+// CHAPSecret keeps an encrypted copy of the password, only if CHAP is enabled for RADIUS
 type Credentials struct {
 	gorm.Model
-	ID                  string `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"subscriber_credential_id"`
-	Username            string `gorm:"not null;index;unique" json:"subscriber_username"`
-	Password            string `gorm:"not null;index;unique" json:"subscriber_password"`
-	AuthenticationToken string `gorm:"not null;index;unique" json:"subscriber_authentication_token"`
-	SubscriberID        string `gorm:"type:uuid;not null;index" json:"subsdriber_id"`
+	ID           string `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"subscriber_credential_id"`
+	Username     string `gorm:"not null;index;unique" json:"subscriber_username"`
+	Password     string `gorm:"not null" json:"subscriber_password"`
+	CHAPSecret   string `gorm:"" json:"-"`
+	SubscriberID string `gorm:"type:uuid;not null;index" json:"subscriber_id"`
+}
+
+// MarshalJSON makes sure that the password is never sent back in API responses, even if a raw model is encoded by mistake
+func (c Credentials) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID           string `json:"subscriber_credential_id"`
+		Username     string `json:"subscriber_username"`
+		SubscriberID string `json:"subscriber_id"`
+	}{
+		ID:           c.ID,
+		Username:     c.Username,
+		SubscriberID: c.SubscriberID,
+	})
+}
+
+// SubscriberShortInfo is used while listing the subscribers
+type SubscriberShortInfo struct {
+	ID                string `json:"subscriber_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`       // This field determines the unique id of the subscriber. The id is in uuid v4 format
+	Name              string `json:"subscriber_name" example:"sample subscriber"`                        // This field determines the name of the subscriber
+	SubscriberGroupID string `json:"subscriber_group_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7b"` // This field determines the group which the subscriber belongs to
 }
+
+// The following models are used to represent the raw details of the subscriber
+// in API responses to avoid expose unnecessary details like credentials
+// Start
+type SubscriberResponse struct {
+	ID                string                    `json:"subscriber_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Details           SubscriberDetailsResponse `json:"subscriber_details"`
+	Username          string                    `json:"subscriber_username"`
+	OrganizationID    string                    `json:"organization_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	SubscriberGroupID string                    `json:"subscriber_group_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7b"`
+}
+
+type SubscriberDetailsResponse struct {
+	Name       string `json:"subscriber_name"`
+	Email      string `json:"subscriber_email"`
+	NationalID string `json:"subscriber_national_id"`
+	PassportID string `json:"passport_id"`
+	Mobile     string `json:"subscriber_mobile"`
+	Phone      string `json:"subscriber_phone"`
+}
+
+// End of Reponse models!
@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"sort"
+
+	"gorm.io/gorm"
+)
 
 type SubscriberGroup struct {
 	gorm.Model
@@ -11,6 +15,8 @@ type SubscriberGroup struct {
 	OrganizationID string       `gorm:"type:uuid;not null;uniqueIndex:org_name_idx;" json:"organization_id"`
 }
 
+// Beautify returns the version 1 format of the group which keeps one permission per category.
+// It is kept for the existing clients, BeautifyV2 should be used to have all of the permissions
 func (sg *SubscriberGroup) Beautify() SubscriberGroupAPI {
 	beautified := SubscriberGroupAPI{
 		ID:             sg.ID,
@@ -36,6 +42,58 @@ func (sg *SubscriberGroup) Beautify() SubscriberGroupAPI {
 	return beautified
 }
 
+// BeautifyV2 returns the version 2 format of the group in which the permissions
+// are nested by their category and name, so no permission is lost
+func (sg *SubscriberGroup) BeautifyV2() SubscriberGroupAPIV2 {
+	beautified := SubscriberGroupAPIV2{
+		ID:             sg.ID,
+		Name:           sg.Name,
+		Description:    sg.Description,
+		OrganizationID: sg.OrganizationID,
+		Permissions:    PermissionSet{},
+	}
+
+	for _, perm := range sg.Permissions {
+		beautified.Permissions.Set(perm.PermissionCategory, perm.PermissionName, perm.PermissionValue)
+	}
+
+	return beautified
+}
+
+// PermissionSet keeps the permissions as category -> permission name -> value
+type PermissionSet map[string]map[string]string
+
+// Set adds the permission to the set or replaces its value
+func (ps PermissionSet) Set(category string, name string, value string) {
+	if ps[category] == nil {
+		ps[category] = map[string]string{}
+	}
+	ps[category][name] = value
+}
+
+// List returns the permissions of the set ordered by their category and name
+func (ps PermissionSet) List() []PermissionAPI {
+	permissions := []PermissionAPI{}
+	for category, names := range ps {
+		for name, value := range names {
+			permissions = append(permissions, PermissionAPI{
+				PermissionCategory: category,
+				PermissionName:     name,
+				PermissionValue:    value,
+			})
+		}
+	}
+
+	sort.Slice(permissions, func(i, j int) bool {
+		if permissions[i].PermissionCategory != permissions[j].PermissionCategory {
+			return permissions[i].PermissionCategory < permissions[j].PermissionCategory
+		}
+		return permissions[i].PermissionName < permissions[j].PermissionName
+	})
+
+	return permissions
+}
+
 // ##########################
 // #	Swagger/API Models	#
 // ##########################
@@ -60,3 +118,80 @@ type SubscriberGroupMinimal struct {
 	ID   string `json:"subscriber_group_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
 	Name string `json:"subscriber_group_name" example:"sample group"`
 }
+
+// SubscriberGroupUpdateRequest is used to partially update a subscriber group. The permissions are
+// identified by their category and name. The whole permission set can be replaced by
+// subscriber_group_permissions, or individual permissions can be added, modified and removed,
+// but both can not be used in the same request
+type SubscriberGroupUpdateRequest struct {
+	Name              *string          `json:"subscriber_group_name" example:"sample group"`
+	Description       *string          `json:"subscriber_group_description" example:"sample description"`
+	Permissions       *[]PermissionAPI `json:"subscriber_group_permissions"` // replaces the whole permission set when given
+	AddPermissions    []PermissionAPI  `json:"add_permissions"`
+	UpdatePermissions []PermissionAPI  `json:"update_permissions"` // changes the value of the existing permissions
+	RemovePermissions []PermissionAPI  `json:"remove_permissions"` // the value is ignored
+}
+
+// SubscriberGroupAPIV2 is the version 2 format of the subscriber group.
+// The permissions are nested as category -> permission name -> value
+type SubscriberGroupAPIV2 struct {
+	ID             string        `json:"subscriber_group_id"`
+	Name           string        `json:"subscriber_group_name"`
+	Description    string        `json:"subscriber_group_description"`
+	Permissions    PermissionSet `json:"subscriber_group_permissions" swaggertype:"object,object" example:"REPORT_LEVEL:{CAN_VIEW_PAYMENT_HISTORY:yes}"`
+	OrganizationID string        `json:"organization_id"`
+}
+
+// SubscriberGroupCreateRequestV2 is used to create a subscriber group with the version 2 format
+type SubscriberGroupCreateRequestV2 struct {
+	Name        string        `json:"subscriber_group_name" example:"sample group"`
+	Description string        `json:"subscriber_group_description" example:"sample description"`
+	Permissions PermissionSet `json:"subscriber_group_permissions" swaggertype:"object,object"`
+}
+
+// SubscriberGroup returns the group that should be created for the request
+func (r *SubscriberGroupCreateRequestV2) SubscriberGroup() SubscriberGroup {
+	group := SubscriberGroup{
+		Name:        r.Name,
+		Description: r.Description,
+	}
+
+	for _, permission := range r.Permissions.List() {
+		group.Permissions = append(group.Permissions, Permission{
+			PermissionCategory: permission.PermissionCategory,
+			PermissionName:     permission.PermissionName,
+			PermissionValue:    permission.PermissionValue,
+		})
+	}
+
+	return group
+}
+
+// SubscriberGroupUpdateRequestV2 is the version 2 format of SubscriberGroupUpdateRequest.
+// The values of the permissions to remove are ignored
+type SubscriberGroupUpdateRequestV2 struct {
+	Name              *string        `json:"subscriber_group_name" example:"sample group"`
+	Description       *string        `json:"subscriber_group_description" example:"sample description"`
+	Permissions       *PermissionSet `json:"subscriber_group_permissions" swaggertype:"object,object"`
+	AddPermissions    PermissionSet  `json:"add_permissions" swaggertype:"object,object"`
+	UpdatePermissions PermissionSet  `json:"update_permissions" swaggertype:"object,object"`
+	RemovePermissions PermissionSet  `json:"remove_permissions" swaggertype:"object,object"`
+}
+
+// UpdateRequest converts the request to SubscriberGroupUpdateRequest
+func (r *SubscriberGroupUpdateRequestV2) UpdateRequest() SubscriberGroupUpdateRequest {
+	request := SubscriberGroupUpdateRequest{
+		Name:              r.Name,
+		Description:       r.Description,
+		AddPermissions:    r.AddPermissions.List(),
+		UpdatePermissions: r.UpdatePermissions.List(),
+		RemovePermissions: r.RemovePermissions.List(),
+	}
+
+	if r.Permissions != nil {
+		permissions := r.Permissions.List()
+		request.Permissions = &permissions
+	}
+
+	return request
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeautifyV2(t *testing.T) {
+	group := SubscriberGroup{
+		ID:   "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a",
+		Name: "sample group",
+		Permissions: []Permission{
+			{PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_PAYMENT_HISTORY", PermissionValue: "yes"},
+			{PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_USAGE_HISTORY", PermissionValue: "no"},
+			{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN", PermissionValue: "yes"},
+		},
+	}
+
+	type testCase struct {
+		name string
+		run  func(t *testing.T)
+	}
+
+	testCases := []testCase{
+		{
+			name: "a group with two permissions in the same category is beautified. In this case, both of them should be kept",
+			run: func(t *testing.T) {
+				beautified := group.BeautifyV2()
+				assert.Equal(t, PermissionSet{
+					"REPORT_LEVEL": {"CAN_VIEW_PAYMENT_HISTORY": "yes", "CAN_VIEW_USAGE_HISTORY": "no"},
+					"ACCESS_LEVEL": {"CAN_LOGIN": "yes"},
+				}, beautified.Permissions)
+			},
+		},
+		{
+			name: "the version 2 response is sent back as a create request. In this case, the same permissions should be created",
+			run: func(t *testing.T) {
+				response, err := json.Marshal(group.BeautifyV2())
+				assert.NoError(t, err)
+
+				var request SubscriberGroupCreateRequestV2
+				assert.NoError(t, json.Unmarshal(response, &request))
+
+				created := request.SubscriberGroup()
+				assert.Equal(t, group.Name, created.Name)
+				assert.ElementsMatch(t, group.Permissions, created.Permissions)
+			},
+		},
+		{
+			name: "the permission set is replaced by the version 2 update request. In this case, the permissions should be listed in order",
+			run: func(t *testing.T) {
+				permissions := group.BeautifyV2().Permissions
+				request := SubscriberGroupUpdateRequestV2{Permissions: &permissions}
+
+				assert.Equal(t, []PermissionAPI{
+					{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN", PermissionValue: "yes"},
+					{PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_PAYMENT_HISTORY", PermissionValue: "yes"},
+					{PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_USAGE_HISTORY", PermissionValue: "no"},
+				}, *request.UpdateRequest().Permissions)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.run(t)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The valid values of Subscription.Status. A subscription is pending until its start date,
+// it can be suspended and resumed while it is active and terminated is the final status
+const (
+	SubscriptionPending    = "pending"
+	SubscriptionActive     = "active"
+	SubscriptionSuspended  = "suspended"
+	SubscriptionTerminated = "terminated"
+)
+
+// The valid values of SubscriptionEvent.Action
+const (
+	SubscriptionActionSubscribe  = "subscribe"
+	SubscriptionActionActivate   = "activate"
+	SubscriptionActionChangePlan = "change_plan"
+	SubscriptionActionSuspend    = "suspend"
+	SubscriptionActionResume     = "resume"
+	SubscriptionActionTerminate  = "terminate"
+)
+
+// Subscription assigns a product offering to a subscriber for a period of time.
+// Changing the plan terminates the subscription and starts a new one for the new offering,
+// so each subscription always refers to a single offering and the entitlements of the
+// subscriber at any point in time can be resolved from the history of its subscriptions
+type Subscription struct {
+	gorm.Model
+	ID                     string              `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"subscription_id"`
+	SubscriberID           string              `gorm:"type:uuid;not null;index" json:"subscriber_id"`
+	ProductOfferingID      string              `gorm:"type:uuid;not null;index" json:"product_offering_id"`
+	Status                 string              `gorm:"not null;index;default:'pending'" json:"subscription_status"`
+	StartDate              time.Time           `gorm:"not null;index" json:"start_date"`
+	EndDate                *time.Time          `gorm:"index" json:"end_date"`
+	PreviousSubscriptionID *string             `gorm:"type:uuid;index" json:"previous_subscription_id"` // the subscription that is replaced by this one when the plan is changed
+	History                []SubscriptionEvent `gorm:"foreignKey:SubscriptionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"subscription_history"`
+}
+
+// SubscriptionEvent records a change of a subscription. The status of the subscription
+// at any point in time is the status of the latest event effective at that time
+type SubscriptionEvent struct {
+	gorm.Model
+	ID                string    `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"subscription_event_id"`
+	SubscriptionID    string    `gorm:"type:uuid;not null;index" json:"subscription_id"`
+	Action            string    `gorm:"not null" json:"action"`
+	FromStatus        string    `gorm:"" json:"from_status"`
+	ToStatus          string    `gorm:"not null" json:"to_status"`
+	ProductOfferingID string    `gorm:"type:uuid" json:"product_offering_id"` // the other offering of the plan changes
+	Reason            string    `json:"reason"`
+	EffectiveAt       time.Time `gorm:"not null;index" json:"effective_at"`
+}
+
+// ##########################
+// #	Swagger/API Models	#
+// ##########################
+// The following models are used for swagger documentation
+
+// SubscriptionRequest is used to subscribe a subscriber to a product offering.
+// The subscription starts now if the start date is not given and it is pending
+// until the start date if it is in the future. An empty end date means no end
+type SubscriptionRequest struct {
+	ProductOfferingID string     `json:"product_offering_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	StartDate         *time.Time `json:"start_date" example:"2024-01-01T00:00:00Z"`
+	EndDate           *time.Time `json:"end_date" example:"2024-12-31T23:59:59Z"`
+	Reason            string     `json:"reason" example:"new customer"`
+}
+
+// SubscriptionActionRequest is used to change the plan of, suspend, resume or terminate a subscription.
+// The product offering is only used to change the plan
+type SubscriptionActionRequest struct {
+	ProductOfferingID string `json:"product_offering_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Reason            string `json:"reason" example:"upgrade"`
+}
+
+// SubscriptionResponse represents a subscription and its history without the database related fields
+type SubscriptionResponse struct {
+	ID                     string                      `json:"subscription_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	SubscriberID           string                      `json:"subscriber_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	ProductOfferingID      string                      `json:"product_offering_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Status                 string                      `json:"subscription_status" example:"active"`
+	StartDate              time.Time                   `json:"start_date"`
+	EndDate                *time.Time                  `json:"end_date"`
+	PreviousSubscriptionID *string                     `json:"previous_subscription_id"`
+	History                []SubscriptionEventResponse `json:"subscription_history"`
+}
+
+type SubscriptionEventResponse struct {
+	Action            string    `json:"action" example:"subscribe"`
+	FromStatus        string    `json:"from_status" example:""`
+	ToStatus          string    `json:"to_status" example:"active"`
+	ProductOfferingID string    `json:"product_offering_id,omitempty"`
+	Reason            string    `json:"reason" example:"new customer"`
+	EffectiveAt       time.Time `json:"effective_at"`
+}
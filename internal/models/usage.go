@@ -0,0 +1,127 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The valid values of AccountingRecord.StatusType
+const (
+	AccountingStatusStart         = "Start"
+	AccountingStatusInterimUpdate = "Interim-Update"
+	AccountingStatusStop          = "Stop"
+)
+
+// The valid values of AccountingSession.Status
+const (
+	AccountingSessionActive  = "active"
+	AccountingSessionStopped = "stopped"
+)
+
+// AccountingSession keeps the latest known usage of a subscriber session reported by
+// a network access server through RADIUS accounting (RFC 2866). A session is identified
+// by its Acct-Session-Id together with the identity of the NAS that reported it
+type AccountingSession struct {
+	gorm.Model
+	ID               string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"accounting_session_id"`
+	AcctSessionID    string     `gorm:"not null;uniqueIndex:nas_session_idx" json:"acct_session_id"`
+	NASIdentifier    string     `gorm:"not null;uniqueIndex:nas_session_idx" json:"nas_identifier"`
+	NASIPAddress     string     `gorm:"not null;uniqueIndex:nas_session_idx" json:"nas_ip_address"`
+	NASPortID        string     `json:"nas_port_id"`
+	SubscriberID     string     `gorm:"type:uuid;not null;index" json:"subscriber_id"`
+	OrganizationID   string     `gorm:"type:uuid;not null;index" json:"organization_id"`
+	Username         string     `gorm:"index" json:"username"`
+	FramedIPAddress  string     `json:"framed_ip_address"`
+	CallingStationID string     `json:"calling_station_id"`
+	Status           string     `gorm:"not null;index" json:"status"`
+	StartedAt        time.Time  `gorm:"not null;index" json:"started_at"`
+	LastUpdatedAt    time.Time  `gorm:"not null" json:"last_updated_at"`
+	StoppedAt        *time.Time `gorm:"index" json:"stopped_at"`
+	SessionTime      int64      `gorm:"not null" json:"session_time"` // in seconds
+	InputOctets      int64      `gorm:"not null" json:"input_octets"`
+	OutputOctets     int64      `gorm:"not null" json:"output_octets"`
+	InputPackets     int64      `gorm:"not null" json:"input_packets"`
+	OutputPackets    int64      `gorm:"not null" json:"output_packets"`
+	TerminateCause   string     `json:"terminate_cause"`
+}
+
+// AccountingRecord keeps each accounting request of a session as it is received
+type AccountingRecord struct {
+	gorm.Model
+	ID                  string    `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"accounting_record_id"`
+	AccountingSessionID string    `gorm:"type:uuid;not null;index" json:"accounting_session_id"`
+	StatusType          string    `gorm:"not null" json:"status_type"`
+	EventTime           time.Time `gorm:"not null;index" json:"event_time"`
+	SessionTime         int64     `gorm:"not null" json:"session_time"`
+	InputOctets         int64     `gorm:"not null" json:"input_octets"`
+	OutputOctets        int64     `gorm:"not null" json:"output_octets"`
+	InputPackets        int64     `gorm:"not null" json:"input_packets"`
+	OutputPackets       int64     `gorm:"not null" json:"output_packets"`
+	TerminateCause      string    `json:"terminate_cause"`
+}
+
+// AccountingRequest is the information extracted from a RADIUS Accounting-Request.
+// It is not stored as is, but it is used to create or update the AccountingSession
+// and its AccountingRecord
+type AccountingRequest struct {
+	StatusType       string
+	AcctSessionID    string
+	NASIdentifier    string
+	NASIPAddress     string
+	NASPortID        string
+	Username         string
+	SubscriberID     string // taken from the Class attribute set in the Access-Accept, if any
+	FramedIPAddress  string
+	CallingStationID string
+	EventTime        time.Time
+	SessionTime      int64
+	InputOctets      int64
+	OutputOctets     int64
+	InputPackets     int64
+	OutputPackets    int64
+	TerminateCause   string
+}
+
+// ##########################
+// #	Swagger/API Models	#
+// ##########################
+// The following models are used for swagger documentation
+
+// UsageSummary is the total usage of a subscriber or an organization in a time range
+type UsageSummary struct {
+	SubscriberID   string    `json:"subscriber_id,omitempty"`
+	OrganizationID string    `json:"organization_id,omitempty"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	SessionCount   int64     `json:"session_count"`
+	SessionTime    int64     `json:"session_time"`
+	InputOctets    int64     `json:"input_octets"`
+	OutputOctets   int64     `json:"output_octets"`
+	InputPackets   int64     `json:"input_packets"`
+	OutputPackets  int64     `json:"output_packets"`
+}
+
+// AccountingSessionInfo is used while listing the sessions of a subscriber
+type AccountingSessionInfo struct {
+	ID               string     `json:"accounting_session_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	AcctSessionID    string     `json:"acct_session_id" example:"81200004"`
+	NASIdentifier    string     `json:"nas_identifier" example:"bras-01"`
+	NASIPAddress     string     `json:"nas_ip_address" example:"10.0.0.1"`
+	NASPortID        string     `json:"nas_port_id"`
+	SubscriberID     string     `json:"subscriber_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	OrganizationID   string     `json:"organization_id" example:"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"`
+	Username         string     `json:"username"`
+	FramedIPAddress  string     `json:"framed_ip_address" example:"100.64.0.10"`
+	CallingStationID string     `json:"calling_station_id"`
+	Status           string     `json:"status" example:"active"`
+	StartedAt        time.Time  `json:"started_at"`
+	LastUpdatedAt    time.Time  `json:"last_updated_at"`
+	StoppedAt        *time.Time `json:"stopped_at"`
+	SessionTime      int64      `json:"session_time"` // in seconds
+	InputOctets      int64      `json:"input_octets"`
+	OutputOctets     int64      `json:"output_octets"`
+	InputPackets     int64      `json:"input_packets"`
+	OutputPackets    int64      `json:"output_packets"`
+	TerminateCause   string     `json:"terminate_cause"`
+}
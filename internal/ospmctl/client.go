@@ -0,0 +1,105 @@
+package ospmctl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"ospm/internal/models"
+	"strings"
+	"time"
+)
+
+// Client sends the requests of ospmctl to the REST API of OSPM
+type Client struct {
+	Server     string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// APIError is returned when OSPM responds with a status other than 2xx
+type APIError struct {
+	StatusCode int
+	models.APIError
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" || e.Message == e.APIError.Error {
+		return fmt.Sprintf("ospm responded %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.APIError.Error)
+	}
+	return fmt.Sprintf("ospm responded %d %s: %s, %s", e.StatusCode, http.StatusText(e.StatusCode), e.APIError.Error, e.Message)
+}
+
+// NewClient returns a client of the OSPM at server
+func NewClient(server string, apiKey string, timeout time.Duration) *Client {
+	return &Client{
+		Server:     strings.TrimSuffix(server, "/"),
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Do sends the request and decodes the JSON response into a generic value, so it can be printed
+// in any format without losing the fields that ospmctl does not know about.
+// body is sent as JSON if it is not nil
+func (c *Client) Do(method string, path string, query url.Values, body interface{}) (interface{}, error) {
+	endpoint := c.Server + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var requestBody io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		requestBody = bytes.NewReader(content)
+	}
+
+	request, err := http.NewRequest(method, endpoint, requestBody)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if c.APIKey != "" {
+		request.Header.Set("X-API-Key", c.APIKey)
+	}
+
+	response, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		apiError := &APIError{StatusCode: response.StatusCode}
+		if err := json.Unmarshal(content, &apiError.APIError); err != nil || apiError.APIError.Error == "" {
+			apiError.APIError.Error = strings.TrimSpace(string(content))
+		}
+		return nil, apiError
+	}
+
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, nil
+	}
+
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	// the numbers are kept as they are, so the amounts of money do not lose their precision
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode the response of %s %s, error: %w", method, path, err)
+	}
+	return decoded, nil
+}
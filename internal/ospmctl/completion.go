@@ -0,0 +1,160 @@
+package ospmctl
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// completeCommandName is the hidden command that is called by the completion scripts
+// with the words of the command line to print the candidates of the last word
+const completeCommandName = "__complete"
+
+// completionScripts are the completion scripts of the shells. They only pass the words to
+// ospmctl __complete, so the candidates always match the installed version of ospmctl
+var completionScripts = map[string]string{
+	"bash": `# bash completion of ospmctl, load it by: source <(ospmctl completion bash)
+_ospmctl() {
+    local IFS=$'\n'
+    COMPREPLY=($(ospmctl __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _ospmctl ospmctl
+`,
+	"zsh": `#compdef ospmctl
+# zsh completion of ospmctl, load it by: source <(ospmctl completion zsh)
+_ospmctl() {
+    local -a candidates
+    candidates=("${(@f)$(ospmctl __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    if (( ${#candidates[@]} == 0 )) || [[ -z "${candidates[1]}" ]]; then
+        _files
+    else
+        compadd -a candidates
+    fi
+}
+compdef _ospmctl ospmctl
+`,
+	"fish": `# fish completion of ospmctl, load it by: ospmctl completion fish | source
+function __ospmctl_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    ospmctl __complete $tokens[2..-1] 2>/dev/null
+end
+complete -c ospmctl -f -a '(__ospmctl_complete)'
+`,
+}
+
+func completionCommand() *command {
+	return &command{
+		name:      "completion",
+		summary:   "prints the shell completion script of bash, zsh or fish",
+		arguments: "<bash|zsh|fish>",
+		complete: func(ctl *CTL) []string {
+			return []string{"bash", "fish", "zsh"}
+		},
+		run: func(ctl *CTL, flags *flag.FlagSet, args []string) error {
+			if err := requireArguments(args, 1, "<bash|zsh|fish>"); err != nil {
+				return err
+			}
+
+			script, found := completionScripts[args[0]]
+			if !found {
+				return fmt.Errorf("%w: completion is not supported for %s, supported shells are: bash, zsh, fish", ErrUsage, args[0])
+			}
+			_, err := io.WriteString(ctl.Stdout, script)
+			return err
+		},
+	}
+}
+
+// complete prints the candidates of the last word. The words are not parsed as the flags of ospmctl
+func (ctl *CTL) complete(words []string) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	ctl.options.configPath = DefaultConfigPath()
+	for i, word := range words[:len(words)-1] {
+		switch {
+		case (word == "-config" || word == "--config") && i+1 < len(words)-1:
+			ctl.options.configPath = words[i+1]
+		case strings.HasPrefix(word, "-config="), strings.HasPrefix(word, "--config="):
+			ctl.options.configPath = word[strings.Index(word, "=")+1:]
+		}
+	}
+
+	for _, candidate := range ctl.completions(words) {
+		fmt.Fprintln(ctl.Stdout, candidate)
+	}
+}
+
+// completions returns the candidates of the last word, which is the one being completed
+func (ctl *CTL) completions(words []string) []string {
+	current, previous := words[len(words)-1], words[:len(words)-1]
+
+	// the values of the global flags
+	if len(previous) > 0 {
+		switch strings.TrimLeft(previous[len(previous)-1], "-") {
+		case "context":
+			config, err := ctl.config()
+			if err != nil {
+				return nil
+			}
+			return withPrefix(config.ContextNames(), current)
+		case "output", "o":
+			return withPrefix(OutputFormats, current)
+		}
+	}
+
+	node := ctl.root
+	for i := 0; i < len(previous); i++ {
+		word := previous[i]
+		if strings.HasPrefix(word, "-") {
+			if takesValue(commandFlags(node), word) {
+				i++
+			}
+			continue
+		}
+		if node.run != nil {
+			continue
+		}
+		if subcommand := node.find(word); subcommand != nil {
+			node = subcommand
+		}
+	}
+
+	candidates := []string{}
+	switch {
+	case strings.HasPrefix(current, "-"):
+		commandFlags(node).VisitAll(func(defined *flag.Flag) {
+			candidates = append(candidates, "-"+defined.Name)
+		})
+	case node.run == nil:
+		for _, subcommand := range node.subcommands {
+			candidates = append(candidates, subcommand.name)
+		}
+	case node.complete != nil:
+		candidates = node.complete(ctl)
+	}
+
+	return withPrefix(candidates, current)
+}
+
+// commandFlags returns the flags of the command including the global ones
+func commandFlags(node *command) *flag.FlagSet {
+	flags := flag.NewFlagSet(node.name, flag.ContinueOnError)
+	(&options{}).register(flags)
+	if node.flags != nil {
+		node.flags(flags)
+	}
+	return flags
+}
+
+func withPrefix(candidates []string, prefix string) []string {
+	matched := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matched = append(matched, candidate)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,126 @@
+package ospmctl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultServer is the address of OSPM when neither the context nor the flags give one,
+// which is the default listen address of the API
+const DefaultServer = "http://127.0.0.1:9898"
+
+// ErrContextNotFound is returned when the requested context is not in the config file
+var ErrContextNotFound = errors.New("context not found")
+
+// Config keeps the contexts of the OSPM deployments that ospmctl talks to.
+// It is stored as YAML in $OSPMCTL_CONFIG or in ospmctl/config.yaml of the user config directory
+type Config struct {
+	CurrentContext string    `yaml:"current_context" json:"current_context"`
+	Contexts       []Context `yaml:"contexts" json:"contexts"`
+
+	path string
+}
+
+// Context is an OSPM deployment and the API key to use for it
+type Context struct {
+	Name   string `yaml:"name" json:"name"`
+	Server string `yaml:"server" json:"server"`
+	APIKey string `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+}
+
+// DefaultConfigPath returns the path of the config file when it is not given by the -config flag
+func DefaultConfigPath() string {
+	if path := os.Getenv("OSPMCTL_CONFIG"); path != "" {
+		return path
+	}
+
+	directory, err := os.UserConfigDir()
+	if err != nil {
+		return ".ospmctl.yaml"
+	}
+	return filepath.Join(directory, "ospmctl", "config.yaml")
+}
+
+// LoadConfig reads the config file. A missing file is an empty config
+func LoadConfig(path string) (*Config, error) {
+	config := &Config{path: path}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the config file %s, error: %w", path, err)
+	}
+	return config, nil
+}
+
+// Save writes the config file. It is only readable by the user since it keeps the API keys
+func (c *Config) Save() error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, content, 0o600)
+}
+
+// Context returns the context with the given name
+func (c *Config) Context(name string) (Context, error) {
+	for _, context := range c.Contexts {
+		if context.Name == name {
+			return context, nil
+		}
+	}
+	return Context{}, fmt.Errorf("%w: %s", ErrContextNotFound, name)
+}
+
+// SetContext adds the context or replaces the one with the same name.
+// The first context becomes the current one
+func (c *Config) SetContext(context Context) {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == context.Name {
+			c.Contexts[i] = context
+			return
+		}
+	}
+
+	c.Contexts = append(c.Contexts, context)
+	if c.CurrentContext == "" {
+		c.CurrentContext = context.Name
+	}
+}
+
+// DeleteContext removes the context. The current context is cleared if it is the removed one
+func (c *Config) DeleteContext(name string) error {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			c.Contexts = append(c.Contexts[:i], c.Contexts[i+1:]...)
+			if c.CurrentContext == name {
+				c.CurrentContext = ""
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrContextNotFound, name)
+}
+
+// ContextNames returns the names of the contexts in the order of the config file
+func (c *Config) ContextNames() []string {
+	names := []string{}
+	for _, context := range c.Contexts {
+		names = append(names, context.Name)
+	}
+	return names
+}
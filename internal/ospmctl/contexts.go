@@ -0,0 +1,186 @@
+package ospmctl
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// contextColumns are the columns of the context list in the table format
+var contextColumns = []Column{
+	{Header: "CURRENT", Path: "current"},
+	{Header: "NAME", Path: "name"},
+	{Header: "SERVER", Path: "server"},
+	{Header: "API KEY", Path: "api_key"},
+}
+
+func configCommand() *command {
+	contextNames := func(ctl *CTL) []string {
+		config, err := ctl.config()
+		if err != nil {
+			return nil
+		}
+		return config.ContextNames()
+	}
+
+	return &command{
+		name:    "config",
+		summary: "manages the contexts of the OSPM deployments",
+		subcommands: []*command{
+			{
+				name:    "get-contexts",
+				summary: "lists the contexts",
+				run:     getContexts,
+			},
+			{
+				name:    "current-context",
+				summary: "shows the name of the current context",
+				run:     currentContext,
+			},
+			{
+				name:      "use-context",
+				summary:   "changes the current context",
+				arguments: "<name>",
+				complete:  contextNames,
+				run:       useContext,
+			},
+			{
+				name:      "set-context",
+				summary:   "adds a context or changes the server and the API key of a context by the -server and -api-key flags",
+				arguments: "<name>",
+				complete:  contextNames,
+				run:       setContext,
+			},
+			{
+				name:      "delete-context",
+				summary:   "removes a context",
+				arguments: "<name>",
+				complete:  contextNames,
+				run:       deleteContext,
+			},
+		},
+	}
+}
+
+func getContexts(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 0, "no argument"); err != nil {
+		return err
+	}
+	config, err := ctl.config()
+	if err != nil {
+		return err
+	}
+
+	contexts := []interface{}{}
+	for _, context := range config.Contexts {
+		current := ""
+		if context.Name == config.CurrentContext {
+			current = "*"
+		}
+		contexts = append(contexts, map[string]interface{}{
+			"current": current,
+			"name":    context.Name,
+			"server":  context.Server,
+			"api_key": maskAPIKey(context.APIKey),
+		})
+	}
+	return ctl.print(contexts, contextColumns)
+}
+
+func currentContext(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 0, "no argument"); err != nil {
+		return err
+	}
+	config, err := ctl.config()
+	if err != nil {
+		return err
+	}
+
+	if config.CurrentContext == "" {
+		return fmt.Errorf("%w: the current context is not set, use ospmctl config use-context", ErrContextNotFound)
+	}
+	fmt.Fprintln(ctl.Stdout, config.CurrentContext)
+	return nil
+}
+
+func useContext(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 1, "<name>"); err != nil {
+		return err
+	}
+	config, err := ctl.config()
+	if err != nil {
+		return err
+	}
+
+	if _, err := config.Context(args[0]); err != nil {
+		return err
+	}
+	config.CurrentContext = args[0]
+	if err := config.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctl.Stdout, "switched to context %s\n", args[0])
+	return nil
+}
+
+func setContext(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 1, "<name>"); err != nil {
+		return err
+	}
+	config, err := ctl.config()
+	if err != nil {
+		return err
+	}
+
+	context, err := config.Context(args[0])
+	if err != nil {
+		context = Context{Name: args[0], Server: DefaultServer}
+	}
+
+	// only the flags that are given explicitly change the context, not the ones from the environment
+	flags.Visit(func(given *flag.Flag) {
+		switch given.Name {
+		case "server":
+			context.Server = strings.TrimSuffix(given.Value.String(), "/")
+		case "api-key":
+			context.APIKey = given.Value.String()
+		}
+	})
+
+	config.SetContext(context)
+	if err := config.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctl.Stdout, "context %s is saved\n", context.Name)
+	return nil
+}
+
+func deleteContext(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 1, "<name>"); err != nil {
+		return err
+	}
+	config, err := ctl.config()
+	if err != nil {
+		return err
+	}
+
+	if err := config.DeleteContext(args[0]); err != nil {
+		return err
+	}
+	if err := config.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctl.Stdout, "context %s is deleted\n", args[0])
+	return nil
+}
+
+// maskAPIKey hides the API key except its last characters, so the keys can be told apart
+func maskAPIKey(apiKey string) string {
+	if len(apiKey) <= 8 {
+		return strings.Repeat("*", len(apiKey))
+	}
+	return strings.Repeat("*", 8) + apiKey[len(apiKey)-4:]
+}
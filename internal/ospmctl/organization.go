@@ -0,0 +1,243 @@
+package ospmctl
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// organizationColumns are the columns of the organization list in the table format
+var organizationColumns = []Column{
+	{Header: "ID", Path: "organization_id"},
+	{Header: "NAME", Path: "organization_name"},
+	{Header: "OWNER TYPE", Path: "owner_type"},
+	{Header: "BALANCE", Path: "balance.amount"},
+	{Header: "CURRENCY", Path: "balance.currency"},
+	{Header: "CREATED AT", Path: "created_at"},
+	{Header: "DELETED AT", Path: "deleted_at"},
+}
+
+// organizationListFlags maps the flags of the organization list to the query parameters of the API
+var organizationListFlags = map[string]string{
+	"limit":        "limit",
+	"cursor":       "cursor",
+	"sort":         "sort",
+	"name-prefix":  "name_prefix",
+	"owner-type":   "owner_type",
+	"deleted":      "deleted",
+	"created-from": "created_from",
+	"created-to":   "created_to",
+	"balance-min":  "balance_min",
+	"balance-max":  "balance_max",
+}
+
+func organizationCommand() *command {
+	return &command{
+		name:    "organization",
+		aliases: []string{"organizations", "org"},
+		summary: "manages the organizations",
+		subcommands: []*command{
+			{
+				name:    "list",
+				aliases: []string{"ls"},
+				summary: "lists a page of the organizations",
+				flags: func(flags *flag.FlagSet) {
+					flags.Bool("all", false, "includes the soft deleted organizations, the same as -deleted include")
+					flags.Int("limit", 0, "maximum number of the organizations in the page (default of OSPM is 100)")
+					flags.String("cursor", "", "next cursor of the previous page")
+					flags.String("sort", "", "sort key: name, created_at or balance, a - prefix sorts in descending order")
+					flags.String("name-prefix", "", "only the organizations whose name starts with the value, case-insensitive")
+					flags.String("owner-type", "", "only the organizations whose owner is legal or individual")
+					flags.String("deleted", "", "deleted state: exclude, include or only")
+					flags.String("created-from", "", "only the organizations created at or after the RFC3339 time")
+					flags.String("created-to", "", "only the organizations created at or before the RFC3339 time")
+					flags.String("balance-min", "", "only the organizations whose balance is at least the value")
+					flags.String("balance-max", "", "only the organizations whose balance is at most the value")
+				},
+				run: listOrganizations,
+			},
+			{
+				name:    "profile",
+				aliases: []string{"get"},
+				summary: "shows the profile of an organization",
+				flags:   organizationLookupFlags,
+				run:     organizationProfile,
+			},
+			{
+				name:    "create",
+				summary: "creates an organization from a JSON or YAML file of its details and owner",
+				flags: func(flags *flag.FlagSet) {
+					flags.String("f", "", "JSON or YAML file of the organization, - reads the standard input")
+				},
+				run: createOrganization,
+			},
+			{
+				name:    "delete",
+				summary: "deletes an organization, it can be recovered unless -hard is given",
+				flags: func(flags *flag.FlagSet) {
+					organizationLookupFlags(flags)
+					flags.Bool("hard", false, "deletes the organization permanently")
+				},
+				run: deleteOrganization,
+			},
+			{
+				name:    "recover",
+				summary: "recovers a soft deleted organization",
+				flags:   organizationLookupFlags,
+				run:     recoverOrganization,
+			},
+		},
+	}
+}
+
+// organizationLookupFlags defines the flags that identify an organization by its id or name
+func organizationLookupFlags(flags *flag.FlagSet) {
+	flags.String("id", "", "organization id")
+	flags.String("name", "", "organization name")
+}
+
+// organizationLookup returns the query that identifies the organization by the -id and -name flags
+func organizationLookup(flags *flag.FlagSet) (url.Values, error) {
+	query := url.Values{}
+	if id := flagValue(flags, "id"); id != "" {
+		query.Set("id", id)
+	}
+	if name := flagValue(flags, "name"); name != "" {
+		query.Set("name", name)
+	}
+
+	if len(query) == 0 {
+		return nil, fmt.Errorf("%w: either -id or -name of the organization should be given", ErrUsage)
+	}
+	return query, nil
+}
+
+func listOrganizations(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 0, "no argument"); err != nil {
+		return err
+	}
+
+	client, err := ctl.client()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if flagValue(flags, "all") == "true" {
+		query.Set("deleted", "include")
+	}
+	flags.Visit(func(given *flag.Flag) {
+		if parameter, found := organizationListFlags[given.Name]; found {
+			query.Set(parameter, given.Value.String())
+		}
+	})
+
+	page, err := client.Do(http.MethodGet, "/organization", query, nil)
+	if err != nil {
+		return err
+	}
+	if ctl.options.output != OutputTable {
+		return ctl.print(page, nil)
+	}
+
+	// the table only has the organizations of the page, the total and the next cursor are printed after it
+	envelope, _ := page.(map[string]interface{})
+	if err := ctl.print(envelope["items"], organizationColumns); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctl.Stdout, "\ntotal: %s\n", cell(envelope["total"]))
+	if nextCursor := cell(envelope["next_cursor"]); nextCursor != "" {
+		fmt.Fprintf(ctl.Stdout, "next page: -cursor %s\n", nextCursor)
+	}
+	return nil
+}
+
+func organizationProfile(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 0, "no argument"); err != nil {
+		return err
+	}
+	query, err := organizationLookup(flags)
+	if err != nil {
+		return err
+	}
+
+	client, err := ctl.client()
+	if err != nil {
+		return err
+	}
+
+	profile, err := client.Do(http.MethodGet, "/organization/profile", query, nil)
+	if err != nil {
+		return err
+	}
+	return ctl.print(profile, nil)
+}
+
+func createOrganization(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 0, "no argument"); err != nil {
+		return err
+	}
+	body, err := ctl.readInput(flagValue(flags, "f"))
+	if err != nil {
+		return err
+	}
+
+	client, err := ctl.client()
+	if err != nil {
+		return err
+	}
+
+	created, err := client.Do(http.MethodPost, "/organization", nil, body)
+	if err != nil {
+		return err
+	}
+	return ctl.print(created, nil)
+}
+
+func deleteOrganization(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 0, "no argument"); err != nil {
+		return err
+	}
+	query, err := organizationLookup(flags)
+	if err != nil {
+		return err
+	}
+
+	query.Set("mode", "soft")
+	if flagValue(flags, "hard") == "true" {
+		query.Set("mode", "hard")
+	}
+
+	client, err := ctl.client()
+	if err != nil {
+		return err
+	}
+
+	deleted, err := client.Do(http.MethodDelete, "/organization", query, nil)
+	if err != nil {
+		return err
+	}
+	return ctl.print(deleted, nil)
+}
+
+func recoverOrganization(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 0, "no argument"); err != nil {
+		return err
+	}
+	query, err := organizationLookup(flags)
+	if err != nil {
+		return err
+	}
+
+	client, err := ctl.client()
+	if err != nil {
+		return err
+	}
+
+	recovered, err := client.Do(http.MethodPatch, "/organization/recover/profile", query, nil)
+	if err != nil {
+		return err
+	}
+	return ctl.print(recovered, nil)
+}
@@ -0,0 +1,322 @@
+// Package ospmctl is the administrative command-line tool of OSPM. It talks to the REST API of the
+// OSPM deployments which are kept as contexts in its config file, so the operators do not need to
+// craft the requests by hand
+package ospmctl
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUsage is returned when the command is not used correctly, its usage is printed with the error
+var ErrUsage = errors.New("invalid usage")
+
+// command is a command of ospmctl. The commands that have subcommands only group them
+type command struct {
+	name      string
+	aliases   []string
+	summary   string
+	arguments string // usage of the positional arguments, e.g. <organization-id>
+
+	subcommands []*command
+
+	// flags defines the flags of the command, besides the global ones
+	flags func(flags *flag.FlagSet)
+
+	// complete returns the candidates of the positional arguments for the shell completion
+	complete func(ctl *CTL) []string
+
+	run func(ctl *CTL, flags *flag.FlagSet, args []string) error
+}
+
+// find returns the subcommand by its name or alias
+func (c *command) find(name string) *command {
+	for _, subcommand := range c.subcommands {
+		if subcommand.name == name {
+			return subcommand
+		}
+		for _, alias := range subcommand.aliases {
+			if alias == name {
+				return subcommand
+			}
+		}
+	}
+	return nil
+}
+
+// options are the global flags, which are accepted before and after the commands
+type options struct {
+	configPath string
+	context    string
+	server     string
+	apiKey     string
+	output     string
+	timeout    time.Duration
+}
+
+func (o *options) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.configPath, "config", DefaultConfigPath(), "path of the config file")
+	flags.StringVar(&o.context, "context", os.Getenv("OSPMCTL_CONTEXT"), "context to use instead of the current context")
+	flags.StringVar(&o.server, "server", "", "address of OSPM, overrides the server of the context")
+	flags.StringVar(&o.apiKey, "api-key", os.Getenv("OSPMCTL_API_KEY"), "API key, overrides the API key of the context")
+	flags.StringVar(&o.output, "output", OutputTable, "output format: "+strings.Join(OutputFormats, ", "))
+	flags.StringVar(&o.output, "o", OutputTable, "shorthand for -output")
+	flags.DurationVar(&o.timeout, "timeout", 30*time.Second, "timeout of the requests")
+}
+
+// CTL runs the commands of ospmctl
+type CTL struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	options options
+	root    *command
+}
+
+// Run runs ospmctl with its arguments and returns the exit code
+func Run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	ctl := &CTL{Stdin: stdin, Stdout: stdout, Stderr: stderr}
+	ctl.root = rootCommand()
+
+	err := ctl.run(args)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, ErrUsage):
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+
+	fmt.Fprintln(stderr, "error:", err)
+	return 1
+}
+
+func rootCommand() *command {
+	return &command{
+		name: "ospmctl",
+		subcommands: []*command{
+			organizationCommand(),
+			subscriberGroupCommand(),
+			configCommand(),
+			completionCommand(),
+		},
+	}
+}
+
+func (ctl *CTL) run(args []string) error {
+	if len(args) > 0 && args[0] == completeCommandName {
+		ctl.complete(args[1:])
+		return nil
+	}
+
+	globalFlags := flag.NewFlagSet("ospmctl", flag.ContinueOnError)
+	ctl.options.register(globalFlags)
+
+	// the command is found by its words, the flags before it are passed to the command
+	// since every command accepts the global flags
+	node, path, rest := ctl.root, []string{}, []string{}
+	for i := 0; i < len(args); i++ {
+		if node.run != nil {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		arg := args[i]
+		if arg == "-h" || arg == "-help" || arg == "--help" || arg == "help" {
+			ctl.usage(ctl.Stdout, node, path)
+			return flag.ErrHelp
+		}
+		if strings.HasPrefix(arg, "-") {
+			rest = append(rest, arg)
+			if takesValue(globalFlags, arg) && i+1 < len(args) {
+				i++
+				rest = append(rest, args[i])
+			}
+			continue
+		}
+
+		subcommand := node.find(arg)
+		if subcommand == nil {
+			ctl.usage(ctl.Stderr, node, path)
+			return fmt.Errorf("%w: unknown command %s", ErrUsage, strings.TrimSpace(strings.Join(append(path, arg), " ")))
+		}
+		node, path = subcommand, append(path, subcommand.name)
+	}
+
+	if node.run == nil {
+		ctl.usage(ctl.Stderr, node, path)
+		return fmt.Errorf("%w: a command is required", ErrUsage)
+	}
+
+	flags := flag.NewFlagSet("ospmctl "+strings.Join(path, " "), flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	ctl.options.register(flags)
+	if node.flags != nil {
+		node.flags(flags)
+	}
+
+	positional, err := parseInterspersed(flags, rest)
+	if errors.Is(err, flag.ErrHelp) {
+		ctl.usage(ctl.Stdout, node, path)
+		return err
+	}
+	if err != nil {
+		ctl.usage(ctl.Stderr, node, path)
+		return fmt.Errorf("%w: %s", ErrUsage, err.Error())
+	}
+
+	return node.run(ctl, flags, positional)
+}
+
+// parseInterspersed parses the flags that are given before, between or after the positional arguments
+// and returns the positional arguments. The arguments after -- are not parsed as flags
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional, args = append(positional, args[0]), args[1:]
+	}
+}
+
+// takesValue determines whether the flag is given without its value and the next argument is its value
+func takesValue(flags *flag.FlagSet, arg string) bool {
+	name := strings.TrimLeft(arg, "-")
+	if strings.Contains(name, "=") {
+		return false
+	}
+
+	defined := flags.Lookup(name)
+	if defined == nil {
+		return false
+	}
+	if boolFlag, ok := defined.Value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+		return false
+	}
+	return true
+}
+
+func (ctl *CTL) usage(output io.Writer, node *command, path []string) {
+	name := strings.TrimSpace("ospmctl " + strings.Join(path, " "))
+
+	if node.run != nil {
+		fmt.Fprintf(output, "usage: %s\n\n%s\n\nflags:\n", strings.TrimSpace(name+" [flags] "+node.arguments), node.summary)
+		flags := flag.NewFlagSet(name, flag.ContinueOnError)
+		flags.SetOutput(output)
+		if node.flags != nil {
+			node.flags(flags)
+		}
+		flags.PrintDefaults()
+	} else {
+		fmt.Fprintf(output, "usage: %s <command> [flags]\n\ncommands:\n", name)
+		writer := tabwriter.NewWriter(output, 0, 0, 3, ' ', 0)
+		for _, subcommand := range node.subcommands {
+			names := strings.Join(append([]string{subcommand.name}, subcommand.aliases...), ", ")
+			fmt.Fprintf(writer, "  %s\t%s\n", names, subcommand.summary)
+		}
+		writer.Flush()
+	}
+
+	fmt.Fprintln(output, "\nglobal flags:")
+	globalFlags := flag.NewFlagSet(name, flag.ContinueOnError)
+	globalFlags.SetOutput(output)
+	(&options{}).register(globalFlags)
+	globalFlags.PrintDefaults()
+}
+
+// config loads the config file of the -config flag
+func (ctl *CTL) config() (*Config, error) {
+	return LoadConfig(ctl.options.configPath)
+}
+
+// client returns the client of the context. The -server and -api-key flags override the context
+func (ctl *CTL) client() (*Client, error) {
+	config, err := ctl.config()
+	if err != nil {
+		return nil, err
+	}
+
+	context := Context{Server: DefaultServer}
+	name := ctl.options.context
+	if name == "" {
+		name = config.CurrentContext
+	}
+	if name != "" {
+		if context, err = config.Context(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if ctl.options.server != "" {
+		context.Server = ctl.options.server
+	}
+	if ctl.options.apiKey != "" {
+		context.APIKey = ctl.options.apiKey
+	}
+
+	return NewClient(context.Server, context.APIKey, ctl.options.timeout), nil
+}
+
+// print writes the value in the format of the -output flag
+func (ctl *CTL) print(value interface{}, columns []Column) error {
+	return Print(ctl.Stdout, ctl.options.output, value, columns)
+}
+
+// readInput reads the JSON or YAML file of the request body. - reads the standard input
+func (ctl *CTL) readInput(path string) (interface{}, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: the request body should be given by -f, use - to read it from the standard input", ErrUsage)
+	}
+
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(ctl.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is also valid YAML, so both are decoded by the YAML decoder
+	var body interface{}
+	if err := yaml.Unmarshal(content, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse %s as JSON or YAML, error: %w", path, err)
+	}
+	if _, ok := body.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("%w: the request body in %s should be an object", ErrUsage, path)
+	}
+	return body, nil
+}
+
+// requireArguments checks the number of the positional arguments
+func requireArguments(args []string, count int, names string) error {
+	if len(args) != count {
+		return fmt.Errorf("%w: expected %s, %d arguments are given", ErrUsage, names, len(args))
+	}
+	return nil
+}
+
+// flagValue returns the value of the flag as a string
+func flagValue(flags *flag.FlagSet, name string) string {
+	return flags.Lookup(name).Value.String()
+}
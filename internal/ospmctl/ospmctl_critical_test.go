@@ -0,0 +1,187 @@
+package ospmctl
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	// the test server responds like OSPM and keeps the last request
+	var lastRequest *http.Request
+	var lastBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lastRequest, lastBody = r, string(body)
+
+		if r.Header.Get("X-API-Key") != "secret-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Unauthorized","message":"a valid API key should be provided in X-API-Key header"}`))
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/organization":
+			w.Write([]byte(`{"items":[{"organization_id":"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a","organization_name":"owl","owner_type":"legal",` +
+				`"balance":{"amount":"12.5","currency":"IRR"},"created_at":"2024-07-06T20:49:32Z","deleted_at":null}],"total":3,"next_cursor":"eyJzIjoibmFtZSJ9"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/organization/profile":
+			w.Write([]byte(`{"organization_id":"ed83a2ba-c55c-4297-b2ac-df7b02abdd7a","organization_details":{"name":"owl"},"balance":{"amount":12.5,"currency":"IRR"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/subscriber_group/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"message":"new subscriber group successfully added","name":"basic","id":"9a1f"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/subscriber_group/9a1f":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Not Found","message":"record not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	type testCase struct {
+		name             string
+		args             []string
+		stdin            string
+		expectedCode     int
+		expectedOutput   string // expected to be contained by the standard output
+		expectedError    string // expected to be contained by the standard error
+		expectedRequest  string // method and path of the last request
+		expectedQuery    string
+		expectedBody     string
+		skipRequestCheck bool
+	}
+
+	// the test cases run in order since the first ones create the contexts of the next ones
+	testCases := []testCase{
+		{
+			name:             "a context is added with the server and the API key. In this case, it should become the current context",
+			args:             []string{"config", "set-context", "production", "-server", server.URL + "/", "-api-key", "secret-key"},
+			expectedOutput:   "context production is saved",
+			skipRequestCheck: true,
+		},
+		{
+			name:             "another context is added. In this case, the first context should stay the current one and the API keys should be masked",
+			args:             []string{"config", "set-context", "staging", "-api-key", "wrong-key-of-staging", "-server", server.URL},
+			expectedOutput:   "context staging is saved",
+			skipRequestCheck: true,
+		},
+		{
+			name:             "the contexts are listed. In this case, the current one should be marked and the API keys should be masked",
+			args:             []string{"config", "get-contexts"},
+			expectedOutput:   "*         production   " + server.URL + "   ********-key",
+			skipRequestCheck: true,
+		},
+		{
+			name:            "a page of the organizations is listed with the global flags after the command. In this case, the page should be printed as a table with its cursor",
+			args:            []string{"org", "list", "-all", "-o", "table", "-sort", "-name", "-limit", "1"},
+			expectedOutput:  "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a   owl    legal        12.5      IRR        2024-07-06T20:49:32Z   \n\ntotal: 3\nnext page: -cursor eyJzIjoibmFtZSJ9\n",
+			expectedRequest: "GET /organization",
+			expectedQuery:   "deleted=include&limit=1&sort=-name",
+		},
+		{
+			name:            "the profile is requested as YAML. In this case, the numbers should be kept as numbers",
+			args:            []string{"-output", "yaml", "organization", "profile", "-name", "owl"},
+			expectedOutput:  "balance:\n  amount: 12.5\n  currency: IRR\n",
+			expectedRequest: "GET /organization/profile",
+			expectedQuery:   "name=owl",
+		},
+		{
+			name:            "the profile is requested in the table format. In this case, the nested fields should be flattened",
+			args:            []string{"organization", "profile", "-id", "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"},
+			expectedOutput:  "organization_details.name   owl",
+			expectedRequest: "GET /organization/profile",
+			expectedQuery:   "id=ed83a2ba-c55c-4297-b2ac-df7b02abdd7a",
+		},
+		{
+			name:            "a subscriber group is created from YAML on the standard input. In this case, it should be sent as JSON in the version 2 format",
+			args:            []string{"sg", "create", "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a", "-f", "-", "-o", "json"},
+			stdin:           "subscriber_group_name: basic\nsubscriber_group_permissions:\n  ACCESS_LEVEL:\n    CAN_LOGIN: \"yes\"\n",
+			expectedOutput:  `"id": "9a1f"`,
+			expectedRequest: "POST /subscriber_group/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a",
+			expectedQuery:   "version=2",
+			expectedBody:    `{"subscriber_group_name":"basic","subscriber_group_permissions":{"ACCESS_LEVEL":{"CAN_LOGIN":"yes"}}}`,
+		},
+		{
+			name:            "a subscriber group is deleted. In this case, the empty response should be reported as a message",
+			args:            []string{"sg", "delete", "9a1f"},
+			expectedOutput:  "subscriber group 9a1f successfully deleted",
+			expectedRequest: "DELETE /subscriber_group/9a1f",
+		},
+		{
+			name:            "an organization that does not exist is deleted. In this case, the error of OSPM should be reported",
+			args:            []string{"org", "delete", "-name", "unknown", "-hard"},
+			expectedCode:    1,
+			expectedError:   "ospm responded 404 Not Found: Not Found, record not found",
+			expectedRequest: "DELETE /organization",
+			expectedQuery:   "mode=hard&name=unknown",
+		},
+		{
+			name:            "the other context is used by the -context flag. In this case, its API key should be sent",
+			args:            []string{"org", "list", "-context", "staging"},
+			expectedCode:    1,
+			expectedError:   "ospm responded 401 Unauthorized",
+			expectedRequest: "GET /organization",
+		},
+		{
+			name:             "an organization is deleted without its id or name. In this case, it should be rejected without any request",
+			args:             []string{"org", "delete"},
+			expectedCode:     2,
+			expectedError:    "either -id or -name of the organization should be given",
+			skipRequestCheck: true,
+		},
+		{
+			name:             "an unknown command is given. In this case, it should be rejected with the usage",
+			args:             []string{"org", "rename"},
+			expectedCode:     2,
+			expectedError:    "unknown command organization rename",
+			skipRequestCheck: true,
+		},
+		{
+			name:             "the context names are completed. In this case, the contexts that start with the word should be listed",
+			args:             []string{"__complete", "org", "list", "-context", "st"},
+			expectedOutput:   "staging\n",
+			skipRequestCheck: true,
+		},
+		{
+			name:             "the commands of a group are completed. In this case, the commands that start with the word should be listed",
+			args:             []string{"__complete", "-o", "json", "sg", "u"},
+			expectedOutput:   "update\n",
+			skipRequestCheck: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		lastRequest, lastBody = nil, ""
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+		args := append([]string{"-config", configPath}, tc.args...)
+		if tc.args[0] == completeCommandName {
+			args = append([]string{completeCommandName, "-config", configPath}, tc.args[1:]...)
+		}
+
+		code := Run(args, strings.NewReader(tc.stdin), stdout, stderr)
+		assert.Equal(t, tc.expectedCode, code, tc.name)
+		assert.Contains(t, stdout.String(), tc.expectedOutput, tc.name)
+		assert.Contains(t, stderr.String(), tc.expectedError, tc.name)
+
+		if tc.skipRequestCheck {
+			assert.Nil(t, lastRequest, tc.name)
+			continue
+		}
+		if assert.NotNil(t, lastRequest, tc.name) {
+			assert.Equal(t, tc.expectedRequest, lastRequest.Method+" "+lastRequest.URL.Path, tc.name)
+			assert.Equal(t, tc.expectedQuery, lastRequest.URL.RawQuery, tc.name)
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, lastBody, tc.name)
+			}
+		}
+	}
+}
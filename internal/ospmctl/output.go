@@ -0,0 +1,206 @@
+package ospmctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// OutputFormats are the valid values of the -output flag
+var OutputFormats = []string{OutputTable, OutputJSON, OutputYAML}
+
+// Column is a column of the table output. Path is the dot separated path of the field in each item of the list
+type Column struct {
+	Header string
+	Path   string
+}
+
+// Print writes the decoded response in the format. The lists are printed as tables with the columns,
+// or with all of the fields of their items if no column is given. The objects are printed as
+// field and value pairs in the table format
+func Print(output io.Writer, format string, value interface{}, columns []Column) error {
+	switch format {
+	case OutputJSON:
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case OutputYAML:
+		if value == nil {
+			return nil
+		}
+		encoder := yaml.NewEncoder(output)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(yamlValue(value)); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case OutputTable:
+		return printTable(output, value, columns)
+	}
+
+	return fmt.Errorf("invalid output format: %s, valid values are: %s", format, strings.Join(OutputFormats, ", "))
+}
+
+func printTable(output io.Writer, value interface{}, columns []Column) error {
+	writer := tabwriter.NewWriter(output, 0, 0, 3, ' ', 0)
+
+	switch typed := value.(type) {
+	case nil:
+		return nil
+
+	case []interface{}:
+		if len(columns) == 0 {
+			columns = defaultColumns(typed)
+		}
+
+		headers := []string{}
+		for _, column := range columns {
+			headers = append(headers, column.Header)
+		}
+		fmt.Fprintln(writer, strings.Join(headers, "\t"))
+
+		for _, item := range typed {
+			cells := []string{}
+			for _, column := range columns {
+				cells = append(cells, cell(lookup(item, column.Path)))
+			}
+			fmt.Fprintln(writer, strings.Join(cells, "\t"))
+		}
+
+	case map[string]interface{}:
+		fields := map[string]string{}
+		flatten("", typed, fields)
+
+		paths := []string{}
+		for path := range fields {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		fmt.Fprintln(writer, "FIELD\tVALUE")
+		for _, path := range paths {
+			fmt.Fprintf(writer, "%s\t%s\n", path, fields[path])
+		}
+
+	default:
+		fmt.Fprintln(writer, cell(typed))
+	}
+
+	return writer.Flush()
+}
+
+// defaultColumns returns a column for each field of the items, sorted by their names
+func defaultColumns(items []interface{}) []Column {
+	names := map[string]bool{}
+	for _, item := range items {
+		if object, ok := item.(map[string]interface{}); ok {
+			for name := range object {
+				names[name] = true
+			}
+		}
+	}
+
+	columns := []Column{}
+	for name := range names {
+		columns = append(columns, Column{Header: strings.ToUpper(name), Path: name})
+	}
+	sort.Slice(columns, func(i, j int) bool { return columns[i].Path < columns[j].Path })
+	return columns
+}
+
+// lookup returns the field of the value at the dot separated path, or nil if it does not exist
+func lookup(value interface{}, path string) interface{} {
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+// flatten adds the scalar fields of the value to fields by their dot separated paths
+func flatten(prefix string, value interface{}, fields map[string]string) {
+	join := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "." + name
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		if len(typed) == 0 && prefix != "" {
+			fields[prefix] = ""
+		}
+		for name, field := range typed {
+			flatten(join(name), field, fields)
+		}
+	case []interface{}:
+		if len(typed) == 0 && prefix != "" {
+			fields[prefix] = ""
+		}
+		for i, item := range typed {
+			flatten(join(strconv.Itoa(i)), item, fields)
+		}
+	default:
+		fields[prefix] = cell(typed)
+	}
+}
+
+func cell(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case map[string]interface{}, []interface{}:
+		content, _ := json.Marshal(typed)
+		return string(content)
+	}
+	return fmt.Sprint(value)
+}
+
+// yamlNumber keeps the numbers of the response as they are in the YAML output instead of quoting them
+type yamlNumber json.Number
+
+func (n yamlNumber) MarshalYAML() (interface{}, error) {
+	tag := "!!float"
+	if _, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		tag = "!!int"
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: string(n)}, nil
+}
+
+func yamlValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case json.Number:
+		return yamlNumber(typed)
+	case map[string]interface{}:
+		converted := map[string]interface{}{}
+		for name, field := range typed {
+			converted[name] = yamlValue(field)
+		}
+		return converted
+	case []interface{}:
+		converted := []interface{}{}
+		for _, item := range typed {
+			converted = append(converted, yamlValue(item))
+		}
+		return converted
+	}
+	return value
+}
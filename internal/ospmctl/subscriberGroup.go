@@ -0,0 +1,162 @@
+package ospmctl
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// subscriberGroupColumns are the columns of the subscriber group list in the table format
+var subscriberGroupColumns = []Column{
+	{Header: "ID", Path: "subscriber_group_id"},
+	{Header: "NAME", Path: "subscriber_group_name"},
+}
+
+// subscriberGroupFormat requests the version 2 format of the subscriber groups,
+// which nests the permissions by their category and name so none of them is lost
+var subscriberGroupFormat = url.Values{"version": []string{"2"}}
+
+func subscriberGroupCommand() *command {
+	inputFlag := func(flags *flag.FlagSet) {
+		flags.String("f", "", "JSON or YAML file of the group in the version 2 format, - reads the standard input")
+	}
+
+	return &command{
+		name:    "subscriber-group",
+		aliases: []string{"subscriber-groups", "sg"},
+		summary: "manages the subscriber groups of the organizations",
+		subcommands: []*command{
+			{
+				name:      "list",
+				aliases:   []string{"ls"},
+				summary:   "lists the subscriber groups of an organization",
+				arguments: "<organization-id>",
+				run:       listSubscriberGroups,
+			},
+			{
+				name:      "get",
+				summary:   "shows a subscriber group with its permissions",
+				arguments: "<subscriber-group-id>",
+				run:       getSubscriberGroup,
+			},
+			{
+				name:      "create",
+				summary:   "creates a subscriber group in an organization",
+				arguments: "<organization-id>",
+				flags:     inputFlag,
+				run:       createSubscriberGroup,
+			},
+			{
+				name:      "update",
+				summary:   "partially updates a subscriber group and its permissions",
+				arguments: "<subscriber-group-id>",
+				flags:     inputFlag,
+				run:       updateSubscriberGroup,
+			},
+			{
+				name:      "delete",
+				summary:   "deletes a subscriber group permanently",
+				arguments: "<subscriber-group-id>",
+				run:       deleteSubscriberGroup,
+			},
+		},
+	}
+}
+
+func listSubscriberGroups(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 1, "<organization-id>"); err != nil {
+		return err
+	}
+
+	client, err := ctl.client()
+	if err != nil {
+		return err
+	}
+
+	subscriberGroups, err := client.Do(http.MethodGet, "/subscriber_group/list/"+url.PathEscape(args[0]), nil, nil)
+	if err != nil {
+		return err
+	}
+	return ctl.print(subscriberGroups, subscriberGroupColumns)
+}
+
+func getSubscriberGroup(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 1, "<subscriber-group-id>"); err != nil {
+		return err
+	}
+
+	client, err := ctl.client()
+	if err != nil {
+		return err
+	}
+
+	subscriberGroup, err := client.Do(http.MethodGet, "/subscriber_group/"+url.PathEscape(args[0]), subscriberGroupFormat, nil)
+	if err != nil {
+		return err
+	}
+	return ctl.print(subscriberGroup, nil)
+}
+
+func createSubscriberGroup(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 1, "<organization-id>"); err != nil {
+		return err
+	}
+	body, err := ctl.readInput(flagValue(flags, "f"))
+	if err != nil {
+		return err
+	}
+
+	client, err := ctl.client()
+	if err != nil {
+		return err
+	}
+
+	created, err := client.Do(http.MethodPost, "/subscriber_group/"+url.PathEscape(args[0]), subscriberGroupFormat, body)
+	if err != nil {
+		return err
+	}
+	return ctl.print(created, nil)
+}
+
+func updateSubscriberGroup(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 1, "<subscriber-group-id>"); err != nil {
+		return err
+	}
+	body, err := ctl.readInput(flagValue(flags, "f"))
+	if err != nil {
+		return err
+	}
+
+	client, err := ctl.client()
+	if err != nil {
+		return err
+	}
+
+	updated, err := client.Do(http.MethodPatch, "/subscriber_group/"+url.PathEscape(args[0]), subscriberGroupFormat, body)
+	if err != nil {
+		return err
+	}
+	return ctl.print(updated, nil)
+}
+
+func deleteSubscriberGroup(ctl *CTL, flags *flag.FlagSet, args []string) error {
+	if err := requireArguments(args, 1, "<subscriber-group-id>"); err != nil {
+		return err
+	}
+
+	client, err := ctl.client()
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Do(http.MethodDelete, "/subscriber_group/"+url.PathEscape(args[0]), nil, nil); err != nil {
+		return err
+	}
+
+	// the group is deleted without a response body
+	return ctl.print(map[string]interface{}{
+		"message":             fmt.Sprintf("subscriber group %s successfully deleted", args[0]),
+		"subscriber_group_id": args[0],
+	}, nil)
+}
@@ -0,0 +1,98 @@
+package radius
+
+import (
+	"errors"
+	"ospm/internal/models"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/usage"
+	"time"
+
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+	"layeh.com/radius/rfc2866"
+	"layeh.com/radius/rfc2869"
+)
+
+// AccountingRecorder stores the accounting requests received from the network access servers
+type AccountingRecorder interface {
+	RecordAccounting(request models.AccountingRequest) error
+}
+
+// AccountingRecorderFunc allows a function to be used as an AccountingRecorder
+type AccountingRecorderFunc func(request models.AccountingRequest) error
+
+func (f AccountingRecorderFunc) RecordAccounting(request models.AccountingRequest) error {
+	return f(request)
+}
+
+// handleAccountingRequest records the Start, Interim-Update and Stop requests (RFC 2866).
+// The Accounting-Response is only sent if the request is recorded, so the NAS retransmits
+// the requests that failed to be stored
+func (s *Server) handleAccountingRequest(w radius.ResponseWriter, r *radius.Request) {
+	if r.Code != radius.CodeAccountingRequest {
+		logger.OSPMLogger.Warnf("unexpected radius packet code %s from %s is dropped", r.Code, r.RemoteAddr)
+		return
+	}
+
+	statusType := rfc2866.AcctStatusType_Get(r.Packet)
+	switch statusType {
+	case rfc2866.AcctStatusType_Value_Start, rfc2866.AcctStatusType_Value_InterimUpdate, rfc2866.AcctStatusType_Value_Stop:
+		request := parseAccountingRequest(r)
+
+		err := s.Recorder.RecordAccounting(request)
+		if errors.Is(err, usage.ErrUnknownSubscriber) {
+			// retransmitting will not help, so the request is acknowledged
+			logger.OSPMLogger.Warnf("accounting request of unknown username %s from %s is ignored", request.Username, r.RemoteAddr)
+		} else if err != nil {
+			logger.OSPMLogger.Errorf("failed to record the accounting request of session %s from %s, error: %+v", request.AcctSessionID, r.RemoteAddr, err)
+			return
+		}
+
+	default:
+		// Accounting-On/Off and the other types do not carry usage, they are just acknowledged
+		logger.OSPMLogger.Debugf("radius accounting request of type %s from %s is acknowledged", statusType, r.RemoteAddr)
+	}
+
+	if err := w.Write(r.Response(radius.CodeAccountingResponse)); err != nil {
+		logger.OSPMLogger.Errorf("failed to send the radius response to %s, error: %+v", r.RemoteAddr, err)
+	}
+}
+
+// parseAccountingRequest extracts the session identity and usage from the accounting request.
+// The octet counters are combined with their gigawords (RFC 2869) to support more than 4GB
+func parseAccountingRequest(r *radius.Request) models.AccountingRequest {
+	request := models.AccountingRequest{
+		StatusType:       rfc2866.AcctStatusType_Get(r.Packet).String(),
+		AcctSessionID:    rfc2866.AcctSessionID_GetString(r.Packet),
+		NASIdentifier:    rfc2865.NASIdentifier_GetString(r.Packet),
+		NASPortID:        rfc2869.NASPortID_GetString(r.Packet),
+		Username:         rfc2865.UserName_GetString(r.Packet),
+		SubscriberID:     rfc2865.Class_GetString(r.Packet),
+		CallingStationID: rfc2865.CallingStationID_GetString(r.Packet),
+		SessionTime:      int64(rfc2866.AcctSessionTime_Get(r.Packet)),
+		InputOctets:      int64(rfc2869.AcctInputGigawords_Get(r.Packet))<<32 | int64(rfc2866.AcctInputOctets_Get(r.Packet)),
+		OutputOctets:     int64(rfc2869.AcctOutputGigawords_Get(r.Packet))<<32 | int64(rfc2866.AcctOutputOctets_Get(r.Packet)),
+		InputPackets:     int64(rfc2866.AcctInputPackets_Get(r.Packet)),
+		OutputPackets:    int64(rfc2866.AcctOutputPackets_Get(r.Packet)),
+	}
+
+	if nasIP := rfc2865.NASIPAddress_Get(r.Packet); nasIP != nil {
+		request.NASIPAddress = nasIP.String()
+	}
+
+	if framedIP := rfc2865.FramedIPAddress_Get(r.Packet); framedIP != nil {
+		request.FramedIPAddress = framedIP.String()
+	}
+
+	if _, err := rfc2866.AcctTerminateCause_Lookup(r.Packet); err == nil {
+		request.TerminateCause = rfc2866.AcctTerminateCause_Get(r.Packet).String()
+	}
+
+	// the event time is the time the event happened on the NAS, not when the request is received
+	request.EventTime = rfc2869.EventTimestamp_Get(r.Packet)
+	if request.EventTime.IsZero() {
+		request.EventTime = time.Now().Add(-time.Duration(rfc2866.AcctDelayTime_Get(r.Packet)) * time.Second)
+	}
+
+	return request
+}
@@ -0,0 +1,110 @@
+package radius
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"errors"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/password"
+
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+)
+
+// handleAccessRequest authenticates the Access-Requests by PAP or CHAP.
+// The subscribers of soft deleted organizations and the organizations which are
+// over their negative balance threshold are rejected
+func (s *Server) handleAccessRequest(w radius.ResponseWriter, r *radius.Request) {
+	if r.Code != radius.CodeAccessRequest {
+		logger.OSPMLogger.Warnf("unexpected radius packet code %s from %s is dropped", r.Code, r.RemoteAddr)
+		return
+	}
+
+	username := rfc2865.UserName_GetString(r.Packet)
+	account, rejectReason := s.authenticate(username, r.Packet)
+
+	var response *radius.Packet
+	if rejectReason != "" {
+		logger.OSPMLogger.Infof("radius access-request of username %s from %s is rejected: %s", username, r.RemoteAddr, rejectReason)
+		response = r.Response(radius.CodeAccessReject)
+		_ = rfc2865.ReplyMessage_SetString(response, rejectReason)
+	} else {
+		logger.OSPMLogger.Debugf("radius access-request of username %s from %s is accepted", username, r.RemoteAddr)
+		response = r.Response(radius.CodeAccessAccept)
+		// the NAS sends back the class in the accounting requests of the session
+		_ = rfc2865.Class_SetString(response, account.SubscriberID)
+	}
+
+	if err := w.Write(response); err != nil {
+		logger.OSPMLogger.Errorf("failed to send the radius response to %s, error: %+v", r.RemoteAddr, err)
+	}
+}
+
+// authenticate returns the account of the given username if the request is authenticated,
+// otherwise the returned reason determines why it is rejected
+func (s *Server) authenticate(username string, packet *radius.Packet) (Account, string) {
+	if username == "" {
+		return Account{}, "username is missing"
+	}
+
+	account, err := s.Store.LookupAccount(username)
+	if errors.Is(err, ErrAccountNotFound) {
+		// a dummy hash keeps the response time of unknown usernames similar to the known ones
+		_, _ = password.Hash(username)
+		return Account{}, "invalid username or password"
+	} else if err != nil {
+		logger.OSPMLogger.Errorf("failed to lookup radius account of username %s, error: %+v", username, err)
+		return Account{}, "authentication is not available"
+	}
+
+	switch {
+	case packet.Get(rfc2865.UserPassword_Type) != nil:
+		plainPassword := rfc2865.UserPassword_GetString(packet)
+		if matched, err := password.Verify(plainPassword, account.PasswordHash); err != nil || !matched {
+			return Account{}, "invalid username or password"
+		}
+
+	case packet.Get(rfc2865.CHAPPassword_Type) != nil:
+		if account.CHAPSecret == "" {
+			return Account{}, "chap authentication is not available for the subscriber"
+		}
+		if !verifyCHAP(packet, account.CHAPSecret) {
+			return Account{}, "invalid username or password"
+		}
+
+	default:
+		return Account{}, "unsupported authentication method"
+	}
+
+	if account.OrganizationDeleted {
+		return Account{}, "the organization of the subscriber is not active"
+	}
+
+	if account.OrganizationOverThreshold {
+		return Account{}, "the organization of the subscriber is over its negative balance threshold"
+	}
+
+	return account, ""
+}
+
+// verifyCHAP checks the CHAP-Password attribute (RFC 1994) which is the MD5 of
+// the CHAP identifier, the secret and the challenge. If there is no CHAP-Challenge attribute,
+// the request authenticator is used as the challenge
+func verifyCHAP(packet *radius.Packet, secret string) bool {
+	chapPassword := rfc2865.CHAPPassword_Get(packet)
+	if len(chapPassword) != 17 {
+		return false
+	}
+
+	challenge := rfc2865.CHAPChallenge_Get(packet)
+	if len(challenge) == 0 {
+		challenge = packet.Authenticator[:]
+	}
+
+	hash := md5.New()
+	hash.Write(chapPassword[:1])
+	hash.Write([]byte(secret))
+	hash.Write(challenge)
+
+	return subtle.ConstantTimeCompare(hash.Sum(nil), chapPassword[1:]) == 1
+}
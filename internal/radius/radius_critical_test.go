@@ -0,0 +1,300 @@
+package radius
+
+import (
+	"context"
+	"crypto/md5"
+	"net"
+	"ospm/config"
+	"ospm/internal/models"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/password"
+	"ospm/internal/service/usage"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+	"layeh.com/radius/rfc2866"
+	"layeh.com/radius/rfc2869"
+)
+
+const testSharedSecret = "test-shared-secret"
+
+type memoryAccountStore map[string]Account
+
+func (store memoryAccountStore) LookupAccount(username string) (Account, error) {
+	account, found := store[username]
+	if !found {
+		return Account{}, ErrAccountNotFound
+	}
+	return account, nil
+}
+
+// startTestServer starts the authentication listener on a random loopback port
+func startTestServer(t *testing.T, store AccountStore) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen on loopback, error: %s", err)
+	}
+
+	server := NewServer(testSharedSecret, store, nil)
+	go server.ServeAuth(conn)
+	t.Cleanup(func() {
+		server.Shutdown(context.Background())
+	})
+
+	return conn.LocalAddr().String()
+}
+
+// memoryAccountingRecorder keeps the recorded requests by their session id
+type memoryAccountingRecorder struct {
+	mutex    sync.Mutex
+	requests map[string]models.AccountingRequest
+}
+
+func (recorder *memoryAccountingRecorder) RecordAccounting(request models.AccountingRequest) error {
+	switch request.Username {
+	case "unknown":
+		return usage.ErrUnknownSubscriber
+	case "failing":
+		return assert.AnError
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.requests[request.AcctSessionID] = request
+	return nil
+}
+
+// startTestAccountingServer starts the accounting listener on a random loopback port
+func startTestAccountingServer(t *testing.T, recorder AccountingRecorder) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen on loopback, error: %s", err)
+	}
+
+	server := NewServer(testSharedSecret, memoryAccountStore{}, recorder)
+	go server.ServeAccounting(conn)
+	t.Cleanup(func() {
+		server.Shutdown(context.Background())
+	})
+
+	return conn.LocalAddr().String()
+}
+
+func TestAccessRequest(t *testing.T) {
+	config.LoadOSPMConfigs()
+	logger.InitLogger()
+
+	passwordHash, _ := password.Hash("S3cret!")
+	store := memoryAccountStore{
+		"active": {
+			SubscriberID: "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a",
+			PasswordHash: passwordHash,
+			CHAPSecret:   "S3cret!",
+		},
+		"no-chap": {
+			SubscriberID: "ed83a2ba-c55c-4297-b2ac-df7b02abdd7b",
+			PasswordHash: passwordHash,
+		},
+		"deleted-organization": {
+			SubscriberID:        "ed83a2ba-c55c-4297-b2ac-df7b02abdd7c",
+			PasswordHash:        passwordHash,
+			OrganizationDeleted: true,
+		},
+		"over-threshold": {
+			SubscriberID:              "ed83a2ba-c55c-4297-b2ac-df7b02abdd7d",
+			PasswordHash:              passwordHash,
+			OrganizationOverThreshold: true,
+		},
+	}
+	address := startTestServer(t, store)
+
+	type testCase struct {
+		name         string
+		username     string
+		password     string
+		useCHAP      bool
+		expectedCode radius.Code
+	}
+
+	testCases := []testCase{
+		{
+			name:         "correct password is given by PAP. In this case, the request should be accepted",
+			username:     "active",
+			password:     "S3cret!",
+			expectedCode: radius.CodeAccessAccept,
+		},
+		{
+			name:         "wrong password is given by PAP. In this case, the request should be rejected",
+			username:     "active",
+			password:     "wrong",
+			expectedCode: radius.CodeAccessReject,
+		},
+		{
+			name:         "unknown username is given. In this case, the request should be rejected",
+			username:     "unknown",
+			password:     "S3cret!",
+			expectedCode: radius.CodeAccessReject,
+		},
+		{
+			name:         "correct password is given by CHAP. In this case, the request should be accepted",
+			username:     "active",
+			password:     "S3cret!",
+			useCHAP:      true,
+			expectedCode: radius.CodeAccessAccept,
+		},
+		{
+			name:         "wrong password is given by CHAP. In this case, the request should be rejected",
+			username:     "active",
+			password:     "wrong",
+			useCHAP:      true,
+			expectedCode: radius.CodeAccessReject,
+		},
+		{
+			name:         "CHAP is used for a subscriber without chap secret. In this case, the request should be rejected",
+			username:     "no-chap",
+			password:     "S3cret!",
+			useCHAP:      true,
+			expectedCode: radius.CodeAccessReject,
+		},
+		{
+			name:         "the organization of the subscriber is soft deleted. In this case, the request should be rejected",
+			username:     "deleted-organization",
+			password:     "S3cret!",
+			expectedCode: radius.CodeAccessReject,
+		},
+		{
+			name:         "the organization of the subscriber is over its negative balance threshold. In this case, the request should be rejected",
+			username:     "over-threshold",
+			password:     "S3cret!",
+			expectedCode: radius.CodeAccessReject,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			packet := radius.New(radius.CodeAccessRequest, []byte(testSharedSecret))
+			rfc2865.UserName_SetString(packet, tc.username)
+
+			if tc.useCHAP {
+				challenge := []byte("0123456789abcdef")
+				hash := md5.Sum(append(append([]byte{7}, []byte(tc.password)...), challenge...))
+				rfc2865.CHAPChallenge_Set(packet, challenge)
+				rfc2865.CHAPPassword_Set(packet, append([]byte{7}, hash[:]...))
+			} else {
+				rfc2865.UserPassword_SetString(packet, tc.password)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			response, err := radius.Exchange(ctx, packet, address)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedCode, response.Code)
+
+			if tc.expectedCode == radius.CodeAccessAccept {
+				assert.Equal(t, store[tc.username].SubscriberID, rfc2865.Class_GetString(response))
+			}
+		})
+	}
+}
+
+func TestAccountingRequest(t *testing.T) {
+	config.LoadOSPMConfigs()
+	logger.InitLogger()
+
+	recorder := &memoryAccountingRecorder{requests: map[string]models.AccountingRequest{}}
+	address := startTestAccountingServer(t, recorder)
+
+	type testCase struct {
+		name             string
+		username         string
+		sessionID        string
+		statusType       rfc2866.AcctStatusType
+		expectedResponse bool
+		expectedRecord   bool
+	}
+
+	testCases := []testCase{
+		{
+			name:             "a start request is received. In this case, it should be recorded and acknowledged",
+			username:         "active",
+			sessionID:        "session-start",
+			statusType:       rfc2866.AcctStatusType_Value_Start,
+			expectedResponse: true,
+			expectedRecord:   true,
+		},
+		{
+			name:             "a stop request is received. In this case, it should be recorded and acknowledged",
+			username:         "active",
+			sessionID:        "session-stop",
+			statusType:       rfc2866.AcctStatusType_Value_Stop,
+			expectedResponse: true,
+			expectedRecord:   true,
+		},
+		{
+			name:             "the subscriber of the request is unknown. In this case, it should be acknowledged without being recorded",
+			username:         "unknown",
+			sessionID:        "session-unknown",
+			statusType:       rfc2866.AcctStatusType_Value_InterimUpdate,
+			expectedResponse: true,
+		},
+		{
+			name:       "the request is failed to be recorded. In this case, it should not be acknowledged so the NAS retransmits it",
+			username:   "failing",
+			sessionID:  "session-failing",
+			statusType: rfc2866.AcctStatusType_Value_InterimUpdate,
+		},
+		{
+			name:             "an accounting-on request is received. In this case, it should be acknowledged without being recorded",
+			username:         "active",
+			sessionID:        "session-accounting-on",
+			statusType:       rfc2866.AcctStatusType_Value_AccountingOn,
+			expectedResponse: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			packet := radius.New(radius.CodeAccountingRequest, []byte(testSharedSecret))
+			rfc2866.AcctStatusType_Set(packet, tc.statusType)
+			rfc2866.AcctSessionID_SetString(packet, tc.sessionID)
+			rfc2865.UserName_SetString(packet, tc.username)
+			rfc2865.NASIdentifier_SetString(packet, "test-nas")
+			rfc2866.AcctSessionTime_Set(packet, 120)
+			rfc2866.AcctInputOctets_Set(packet, 1000)
+			rfc2869.AcctInputGigawords_Set(packet, 1)
+			rfc2866.AcctOutputOctets_Set(packet, 2000)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			response, err := radius.Exchange(ctx, packet, address)
+			if tc.expectedResponse {
+				assert.Nil(t, err)
+				assert.Equal(t, radius.CodeAccountingResponse, response.Code)
+			} else {
+				assert.NotNil(t, err)
+			}
+
+			recorder.mutex.Lock()
+			record, recorded := recorder.requests[tc.sessionID]
+			recorder.mutex.Unlock()
+
+			assert.Equal(t, tc.expectedRecord, recorded)
+			if tc.expectedRecord {
+				assert.Equal(t, "test-nas", record.NASIdentifier)
+				assert.Equal(t, int64(120), record.SessionTime)
+				assert.Equal(t, int64(1)<<32+1000, record.InputOctets)
+				assert.Equal(t, int64(2000), record.OutputOctets)
+			}
+		})
+	}
+}
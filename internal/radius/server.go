@@ -0,0 +1,84 @@
+package radius
+
+import (
+	"context"
+	"net"
+
+	"layeh.com/radius"
+)
+
+// Server answers the RADIUS requests of the network access servers
+// based on the subscribers known by OSPM
+type Server struct {
+	Secret   []byte
+	Store    AccountStore
+	Recorder AccountingRecorder
+
+	authServer       *radius.PacketServer
+	accountingServer *radius.PacketServer
+}
+
+// NewServer returns a RADIUS server that uses the given shared secret, loads the
+// subscribers from the given store and records the accounting requests by the given recorder
+func NewServer(secret string, store AccountStore, recorder AccountingRecorder) *Server {
+	server := &Server{
+		Secret:   []byte(secret),
+		Store:    store,
+		Recorder: recorder,
+	}
+
+	server.authServer = &radius.PacketServer{
+		Network:      "udp",
+		SecretSource: radius.StaticSecretSource(server.Secret),
+		Handler:      radius.HandlerFunc(server.handleAccessRequest),
+	}
+
+	server.accountingServer = &radius.PacketServer{
+		Network:      "udp",
+		SecretSource: radius.StaticSecretSource(server.Secret),
+		Handler:      radius.HandlerFunc(server.handleAccountingRequest),
+	}
+
+	return server
+}
+
+// ListenAndServeAuth starts the authentication listener (RFC 2865) on the given address.
+// It blocks until the server is shut down
+func (s *Server) ListenAndServeAuth(address string) error {
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return err
+	}
+
+	return s.ServeAuth(conn)
+}
+
+// ServeAuth serves the authentication requests received on the given connection
+func (s *Server) ServeAuth(conn net.PacketConn) error {
+	return s.authServer.Serve(conn)
+}
+
+// ListenAndServeAccounting starts the accounting listener (RFC 2866) on the given address.
+// It blocks until the server is shut down
+func (s *Server) ListenAndServeAccounting(address string) error {
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return err
+	}
+
+	return s.ServeAccounting(conn)
+}
+
+// ServeAccounting serves the accounting requests received on the given connection
+func (s *Server) ServeAccounting(conn net.PacketConn) error {
+	return s.accountingServer.Serve(conn)
+}
+
+// Shutdown stops the listeners gracefully
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.authServer.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	return s.accountingServer.Shutdown(ctx)
+}
@@ -0,0 +1,83 @@
+package radius
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/organization"
+	"ospm/internal/service/password"
+
+	"gorm.io/gorm"
+)
+
+// ErrAccountNotFound is returned when there is no active subscriber with the given username
+var ErrAccountNotFound = errors.New("account not found")
+
+// Account keeps what the RADIUS server needs to know about a subscriber
+// to answer an Access-Request
+type Account struct {
+	SubscriberID              string
+	OrganizationID            string
+	SubscriberGroupID         string
+	PasswordHash              string
+	CHAPSecret                string // the decrypted password, empty if CHAP is not available for the subscriber
+	OrganizationDeleted       bool
+	OrganizationOverThreshold bool
+}
+
+// AccountStore looks up the subscriber accounts by their username
+type AccountStore interface {
+	LookupAccount(username string) (Account, error)
+}
+
+// DatabaseAccountStore loads the accounts from the subscriber credentials stored in the database
+type DatabaseAccountStore struct {
+	// CHAPSecretKey is used to decrypt the CHAP secrets. CHAP is not available if it is empty
+	CHAPSecretKey string
+}
+
+func (store DatabaseAccountStore) LookupAccount(username string) (Account, error) {
+	var credentials models.Credentials
+	err := cockroachdb.DB.First(&credentials, "username = ?", username).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Account{}, ErrAccountNotFound
+	} else if err != nil {
+		return Account{}, fmt.Errorf("failed to load the credentials of username %s, error: %w", username, err)
+	}
+
+	var subscriber models.Subscriber
+	err = cockroachdb.DB.First(&subscriber, "id = ?", credentials.SubscriberID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Account{}, ErrAccountNotFound
+	} else if err != nil {
+		return Account{}, fmt.Errorf("failed to load the subscriber of username %s, error: %w", username, err)
+	}
+
+	// soft deleted organizations are loaded too so they can be rejected explicitly
+	var subscriberOrganization models.Organization
+	err = cockroachdb.DB.Unscoped().First(&subscriberOrganization, "id = ?", subscriber.OrganizationID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Account{}, ErrAccountNotFound
+	} else if err != nil {
+		return Account{}, fmt.Errorf("failed to load the organization of username %s, error: %w", username, err)
+	}
+
+	account := Account{
+		SubscriberID:              subscriber.ID,
+		OrganizationID:            subscriber.OrganizationID,
+		SubscriberGroupID:         subscriber.SubscriberGroupID,
+		PasswordHash:              credentials.Password,
+		OrganizationDeleted:       subscriberOrganization.DeletedAt.Valid,
+		OrganizationOverThreshold: organization.IsOverNegativeBalanceThreshold(&subscriberOrganization),
+	}
+
+	if store.CHAPSecretKey != "" && credentials.CHAPSecret != "" {
+		account.CHAPSecret, err = password.Decrypt(credentials.CHAPSecret, store.CHAPSecretKey)
+		if err != nil {
+			return Account{}, fmt.Errorf("failed to decrypt the chap secret of username %s, error: %w", username, err)
+		}
+	}
+
+	return account, nil
+}
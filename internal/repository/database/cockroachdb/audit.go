@@ -0,0 +1,28 @@
+package cockroachdb
+
+import (
+	"ospm/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuditRepository is the GORM implementation of repository.AuditRepository
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository returns an audit repository on the given database or transaction
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) LastAuditEntry() (models.AuditEntry, error) {
+	var last models.AuditEntry
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Order("sequence desc").Limit(1).Find(&last).Error
+	return last, err
+}
+
+func (r *AuditRepository) AppendAuditEntry(entry *models.AuditEntry) error {
+	return r.db.Create(entry).Error
+}
@@ -1,6 +1,7 @@
 package cockroachdb
 
 import (
+	"fmt"
 	"log"
 	"ospm/config"
 	"ospm/internal/models"
@@ -11,10 +12,69 @@ import (
 
 var DB *gorm.DB
 
+// legacyIndexes contains the indexes (including the ones backing unique constraints)
+// which are no longer defined by the models, mapped by their table
+var legacyIndexes = map[string][]string{
+	"credentials": {
+		// credentials.password keeps the password hash and must not be unique or indexed
+		"uni_credentials_password",
+		"idx_credentials_password",
+		// credentials.authentication_token is replaced by the subscriber_sessions table
+		"uni_credentials_authentication_token",
+		"idx_credentials_authentication_token",
+	},
+	"organizations": {
+		// the float balance columns are replaced by the money columns, see legacyMoneyColumns
+		"idx_organizations_balance",
+		"idx_organizations_negative_balance_threshold",
+	},
+}
+
+// legacyColumns contains the columns which are no longer defined by the models, mapped by their table
+var legacyColumns = map[string][]string{
+	"credentials": {"authentication_token"},
+}
+
+// legacyMoneyColumns contains the float columns which are replaced by models.Money, mapped by their table.
+// The value of each column is copied into <column>_amount before the column is dropped
+var legacyMoneyColumns = map[string][]string{
+	"organizations": {"balance", "negative_balance_threshold"},
+}
+
+// currencyColumns contains the currency columns which are filled by the default currency
+// if they are empty, like the balances migrated from the float columns
+var currencyColumns = map[string][]string{
+	"organizations":       {"balance_currency", "negative_balance_threshold_currency"},
+	"ledger_transactions": {"currency"},
+}
+
+// InitialDB connects to the database and applies or checks the schema migrations
+// depending on OSPM_COCKROACHDB_MIGRATIONS
 func InitialDB() {
+	Connect()
+
+	switch config.Current().RDMS.Migrations {
+	case config.MigrationsAuto:
+		if _, err := MigrateUp(0); err != nil {
+			log.Fatal("failed to migrate database: ", err)
+		}
+	case config.MigrationsCheck:
+		pending, err := PendingMigrations()
+		if err != nil {
+			log.Fatal("failed to check the database migrations: ", err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("the database schema is behind, %d migrations are pending starting from %04d_%s. run ospm migrate up to apply them",
+				len(pending), pending[0].Version, pending[0].Name)
+		}
+	}
+}
+
+// Connect connects to the database without changing its schema
+func Connect() {
 	var err error
 
-	DB, err = gorm.Open(postgres.Open(config.OSPM.RDMS.DSN()), &gorm.Config{})
+	DB, err = gorm.Open(postgres.Open(config.Current().RDMS.DSN()), &gorm.Config{})
 	if err != nil {
 		log.Fatal("failed to connect to database: ", err)
 	}
@@ -30,15 +90,43 @@ func InitialDB() {
 		log.Fatal("failed to connect to the database: ", err)
 	}
 
+	log.Println("database connection established successfully")
+}
+
+// adoptLegacySchema brings the schema that is created by the auto migration of the earlier versions
+// up to the baseline migration, so it can be recorded as applied instead of being created again
+func adoptLegacySchema() error {
 	// Create the uuid-ossp extension
-	err = DB.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"").Error
+	err := DB.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"").Error
 	if err != nil {
-		log.Fatal("failed to create uuid-ossp extension: ", err)
+		return fmt.Errorf("failed to create uuid-ossp extension: %w", err)
 	}
 
-	log.Println("database connection established successfully")
+	// Drop the indexes and constraints that are removed from the models
+	// since auto migration is not able to drop them on cockroachdb
+	for table, indexes := range legacyIndexes {
+		for _, index := range indexes {
+			if !DB.Migrator().HasIndex(table, index) {
+				continue
+			}
+			if err := DB.Exec(fmt.Sprintf("DROP INDEX %s@%s CASCADE", table, index)).Error; err != nil {
+				return fmt.Errorf("failed to drop the legacy index: %w", err)
+			}
+		}
+	}
+
+	for table, columns := range legacyColumns {
+		for _, column := range columns {
+			if !DB.Migrator().HasColumn(table, column) {
+				continue
+			}
+			if err := DB.Migrator().DropColumn(table, column); err != nil {
+				return fmt.Errorf("failed to drop the legacy column: %w", err)
+			}
+		}
+	}
 
-	// Run auto migration
+	// Run auto migration for the last time, the later changes of the schema are made by the migrations
 	err = DB.AutoMigrate(
 		&models.Organization{},
 		&models.OrganizationDetails{},
@@ -46,11 +134,56 @@ func InitialDB() {
 		&models.Subscriber{},
 		&models.SubscriberDetails{},
 		&models.Credentials{},
+		&models.SubscriberSession{},
+		&models.AccountingSession{},
+		&models.AccountingRecord{},
+		&models.LedgerTransaction{},
+		&models.LedgerEntry{},
 		&models.SubscriberGroup{},
 		&models.Permission{},
 		&models.ProductOffering{},
-		&models.ProductOfferingSpecification{})
+		&models.ProductOfferingSpecification{},
+		&models.Subscription{},
+		&models.SubscriptionEvent{},
+		&models.APIKey{},
+		&models.AuditEntry{})
 	if err != nil {
-		log.Fatal("failed to migrate database: ", err)
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return migrateMoneyColumns()
+}
+
+// migrateMoneyColumns moves the values of the float columns into the decimal columns
+// that are added by the auto migration and sets the currency of the migrated values
+func migrateMoneyColumns() error {
+	for table, columns := range legacyMoneyColumns {
+		for _, column := range columns {
+			if !DB.Migrator().HasColumn(table, column) {
+				continue
+			}
+
+			query := fmt.Sprintf("UPDATE %s SET %s_amount = %s::DECIMAL(19,4)", table, column, column)
+			if err := DB.Exec(query).Error; err != nil {
+				return fmt.Errorf("failed to migrate the legacy money column: %w", err)
+			}
+
+			if err := DB.Migrator().DropColumn(table, column); err != nil {
+				return fmt.Errorf("failed to drop the legacy money column: %w", err)
+			}
+
+			log.Printf("the values of %s.%s are migrated to %s.%s_amount", table, column, table, column)
+		}
 	}
+
+	for table, columns := range currencyColumns {
+		for _, column := range columns {
+			query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ''", table, column, column)
+			if err := DB.Exec(query, config.Current().Billing.DefaultCurrency).Error; err != nil {
+				return fmt.Errorf("failed to set the default currency: %w", err)
+			}
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,580 @@
+package cockroachdb
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"ospm/internal/models"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// migrationFiles are the up and down SQL files of the migrations, named <version>_<name>.up.sql and
+// <version>_<name>.down.sql. The versions are applied in ascending order and the down file is optional
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+const (
+	// MigrationsDirectory is where the migration files are kept in the source tree
+	MigrationsDirectory = "internal/repository/database/cockroachdb/migrations"
+
+	// baselineVersion is the migration that creates the schema which the auto migration used to create
+	baselineVersion = 1
+
+	// migrationLockWait is how long to wait for the migration lock that is held by another instance
+	migrationLockWait = 5 * time.Minute
+
+	// migrationLockRetryInterval is how often the migration lock is tried while waiting for it
+	migrationLockRetryInterval = time.Second
+
+	// migrationLockRefreshInterval is how often the holder of the migration lock refreshes it while migrating
+	migrationLockRefreshInterval = time.Minute
+
+	// migrationLockExpiryMinutes is the age after which the migration lock is considered abandoned,
+	// e.g. by an instance that crashed while migrating, and can be taken by another instance. The lock
+	// is refreshed while the migrations run, but a refresh may be delayed by a long migration that
+	// keeps the database busy, so it is longer than the longest expected migration
+	migrationLockExpiryMinutes = 120
+)
+
+var (
+	// ErrInvalidMigration is returned when the migration files are not valid
+	ErrInvalidMigration = errors.New("invalid migration")
+
+	// ErrMigrationLocked is returned when the migration lock is not released in time by another instance
+	ErrMigrationLocked = errors.New("migrations are locked by another instance")
+
+	// ErrMigrationLockLost is returned when the migration lock is taken by another instance while migrating
+	ErrMigrationLockLost = errors.New("migration lock is taken by another instance")
+
+	// ErrModifiedMigration is returned when the up file of an applied migration is changed
+	ErrModifiedMigration = errors.New("applied migrations are modified")
+
+	// ErrIrreversibleMigration is returned when a migration without a down file is rolled back
+	ErrIrreversibleMigration = errors.New("migration can not be rolled back")
+
+	migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	migrationName     = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
+
+// Migration is a versioned change of the schema
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string // empty if the migration can not be rolled back
+	Checksum string // of the up file, to detect the applied migrations that are changed later
+}
+
+// MigrationState is the state of a migration in the database
+type MigrationState struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"` // nil if the migration is pending
+
+	// Modified is true if the up file is changed after the migration is applied
+	Modified bool `json:"modified"`
+
+	// Unknown is true if the migration is applied but this version of OSPM does not have it,
+	// e.g. it is applied by a newer version
+	Unknown bool `json:"unknown"`
+}
+
+// schemaMigration is a row of schema_migrations which keeps the applied migrations
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrations returns the migrations of this version of OSPM in ascending order
+func Migrations() ([]Migration, error) {
+	return parseMigrations(migrationFiles, "migrations")
+}
+
+// MigrateUp applies the pending migrations in ascending order. steps limits the number of
+// the migrations to apply and 0 applies all of them. The applied migrations are returned
+func MigrateUp(steps int) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	err = withMigrationLock(func(lock migrationLock) error {
+		if err := adoptLegacyDatabase(migrations); err != nil {
+			return err
+		}
+
+		appliedMigrations, err := loadAppliedMigrations()
+		if err != nil {
+			return err
+		}
+		if err := checkModifiedMigrations(migrations, appliedMigrations); err != nil {
+			return err
+		}
+
+		for _, migration := range pendingMigrations(migrations, appliedMigrations) {
+			if steps > 0 && len(applied) == steps {
+				break
+			}
+
+			log.Printf("applying migration %04d_%s", migration.Version, migration.Name)
+			err := DB.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				if err := lock.verify(tx); err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					Checksum:  migration.Checksum,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %04d_%s, error: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// MigrateDown rolls back the last applied migrations in descending order. steps is the number of
+// the migrations to roll back. The rolled back migrations are returned
+func MigrateDown(steps int) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[int64]Migration{}
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+
+	var rolledBack []Migration
+	err = withMigrationLock(func(lock migrationLock) error {
+		appliedMigrations, err := loadAppliedMigrations()
+		if err != nil {
+			return err
+		}
+
+		versions := []int64{}
+		for version := range appliedMigrations {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, version := range versions {
+			if len(rolledBack) == steps {
+				break
+			}
+
+			migration, found := known[version]
+			if !found {
+				return fmt.Errorf("%w: migration %04d_%s is not known by this version of OSPM",
+					ErrIrreversibleMigration, version, appliedMigrations[version].Name)
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("%w: migration %04d_%s does not have a down file", ErrIrreversibleMigration, version, migration.Name)
+			}
+
+			log.Printf("rolling back migration %04d_%s", migration.Version, migration.Name)
+			err := DB.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				if err := lock.verify(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, "version = ?", migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration %04d_%s, error: %w", migration.Version, migration.Name, err)
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// MigrationStatus returns the state of the known and the applied migrations in ascending order
+func MigrationStatus() ([]MigrationState, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := createMigrationTables(); err != nil {
+		return nil, err
+	}
+	appliedMigrations, err := loadAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return migrationStates(migrations, appliedMigrations), nil
+}
+
+// PendingMigrations returns the migrations that are not applied yet in ascending order.
+// ErrModifiedMigration is returned if an applied migration is changed after it is applied
+func PendingMigrations() ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := createMigrationTables(); err != nil {
+		return nil, err
+	}
+	appliedMigrations, err := loadAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	// the schema created by the auto migration is adopted by the next migrate up without running the baseline
+	if len(appliedMigrations) == 0 && DB.Migrator().HasTable(&models.Organization{}) {
+		log.Printf("the schema is created by the auto migration of an earlier version, it is adopted as migration %04d by the next migrate up", baselineVersion)
+	}
+	if err := checkModifiedMigrations(migrations, appliedMigrations); err != nil {
+		return nil, err
+	}
+
+	return pendingMigrations(migrations, appliedMigrations), nil
+}
+
+// CreateMigration adds the empty up and down files of a new migration to the directory.
+// The version of the new migration is the next version after the ones in the directory
+func CreateMigration(directory string, name string) (upFile string, downFile string, err error) {
+	if !migrationName.MatchString(name) {
+		return "", "", fmt.Errorf("%w: the name of the migration should only contain lower case letters, digits and _, given value is: %s",
+			ErrInvalidMigration, name)
+	}
+
+	migrations, err := parseMigrations(os.DirFS(directory), ".")
+	if err != nil {
+		return "", "", err
+	}
+
+	version := int64(1)
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	prefix := filepath.Join(directory, fmt.Sprintf("%04d_%s", version, name))
+	upFile, downFile = prefix+".up.sql", prefix+".down.sql"
+
+	upContent := fmt.Sprintf("-- %04d_%s\n-- The statements of the migration. They are applied in one transaction.\n", version, name)
+	if err := os.WriteFile(upFile, []byte(upContent), 0o644); err != nil {
+		return "", "", err
+	}
+
+	downContent := fmt.Sprintf("-- %04d_%s\n-- The statements that roll back the migration. Delete this file if it can not be rolled back.\n", version, name)
+	if err := os.WriteFile(downFile, []byte(downContent), 0o644); err != nil {
+		return "", "", err
+	}
+
+	return upFile, downFile, nil
+}
+
+// parseMigrations reads the migration files of the directory of fsys and validates them
+func parseMigrations(fsys fs.FS, directory string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, directory)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		parts := migrationFileName.FindStringSubmatch(entry.Name())
+		if parts == nil {
+			return nil, fmt.Errorf("%w: file %s should be named <version>_<name>.up.sql or <version>_<name>.down.sql", ErrInvalidMigration, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("%w: file %s has an invalid version", ErrInvalidMigration, entry.Name())
+		}
+
+		migration, found := migrations[version]
+		if !found {
+			migration = &Migration{Version: version, Name: parts[2]}
+			migrations[version] = migration
+		}
+		if migration.Name != parts[2] {
+			return nil, fmt.Errorf("%w: version %d is used by both %s and %s", ErrInvalidMigration, version, migration.Name, parts[2])
+		}
+
+		content, err := fs.ReadFile(fsys, filepath.ToSlash(filepath.Join(directory, entry.Name())))
+		if err != nil {
+			return nil, err
+		}
+
+		if parts[3] == "up" {
+			migration.Up = string(content)
+			checksum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(checksum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	sorted := []Migration{}
+	for _, migration := range migrations {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%w: migration %04d_%s does not have an up file", ErrInvalidMigration, migration.Version, migration.Name)
+		}
+		sorted = append(sorted, *migration)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	return sorted, nil
+}
+
+// pendingMigrations returns the migrations that are not applied in ascending order
+func pendingMigrations(migrations []Migration, appliedMigrations map[int64]schemaMigration) []Migration {
+	pending := []Migration{}
+	for _, migration := range migrations {
+		if _, applied := appliedMigrations[migration.Version]; !applied {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// checkModifiedMigrations returns ErrModifiedMigration with the versions of the applied migrations whose
+// up file is changed since they are applied. The pending migrations are picked by version only, so the
+// schema would silently differ from the migration files if the changed ones were skipped
+func checkModifiedMigrations(migrations []Migration, appliedMigrations map[int64]schemaMigration) error {
+	modified := []string{}
+	for _, migration := range migrations {
+		if applied, found := appliedMigrations[migration.Version]; found && applied.Checksum != migration.Checksum {
+			modified = append(modified, fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("%w: %s. restore their up files and add new migrations for the changes", ErrModifiedMigration, strings.Join(modified, ", "))
+	}
+	return nil
+}
+
+// migrationStates merges the known migrations with the applied ones in ascending order
+func migrationStates(migrations []Migration, appliedMigrations map[int64]schemaMigration) []MigrationState {
+	states := []MigrationState{}
+	known := map[int64]bool{}
+
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		state := MigrationState{Version: migration.Version, Name: migration.Name}
+		if applied, found := appliedMigrations[migration.Version]; found {
+			appliedAt := applied.AppliedAt
+			state.AppliedAt = &appliedAt
+			state.Modified = applied.Checksum != migration.Checksum
+		}
+		states = append(states, state)
+	}
+
+	for version, applied := range appliedMigrations {
+		if !known[version] {
+			appliedAt := applied.AppliedAt
+			states = append(states, MigrationState{Version: version, Name: applied.Name, AppliedAt: &appliedAt, Unknown: true})
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+
+	return states
+}
+
+// adoptLegacyDatabase records the baseline migration as applied when the schema is created by
+// the auto migration of an earlier version, after bringing the schema up to the baseline
+func adoptLegacyDatabase(migrations []Migration) error {
+	appliedMigrations, err := loadAppliedMigrations()
+	if err != nil {
+		return err
+	}
+	if len(appliedMigrations) > 0 || !DB.Migrator().HasTable(&models.Organization{}) {
+		return nil
+	}
+
+	log.Printf("the schema is created by the auto migration of an earlier version, it is adopted as migration %04d", baselineVersion)
+	if err := adoptLegacySchema(); err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if migration.Version > baselineVersion {
+			break
+		}
+		err := DB.Create(&schemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Checksum:  migration.Checksum,
+			AppliedAt: time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func loadAppliedMigrations() (map[int64]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := DB.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load the applied migrations, error: %w", err)
+	}
+
+	appliedMigrations := map[int64]schemaMigration{}
+	for _, row := range rows {
+		appliedMigrations[row.Version] = row
+	}
+	return appliedMigrations, nil
+}
+
+// createMigrationTables creates the tables that keep the applied migrations and the migration lock
+func createMigrationTables() error {
+	err := DB.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create the schema_migrations table, error: %w", err)
+	}
+
+	err = DB.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+		id INT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		locked_at TIMESTAMPTZ NOT NULL
+	)`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create the schema_migrations_lock table, error: %w", err)
+	}
+
+	return nil
+}
+
+// migrationLock is the migration lock that is held by this instance
+type migrationLock struct {
+	owner string
+}
+
+// verify checks that the lock is still held by this instance. It is called in the transaction of each
+// migration, so a migration is never committed after another instance has taken the lock
+func (l migrationLock) verify(tx *gorm.DB) error {
+	var owner string
+	if err := tx.Raw("SELECT owner FROM schema_migrations_lock WHERE id = 1 FOR UPDATE").Scan(&owner).Error; err != nil {
+		return fmt.Errorf("failed to check the migration lock, error: %w", err)
+	}
+	if owner != l.owner {
+		return fmt.Errorf("%w: it is held by %q, the remaining migrations are aborted", ErrMigrationLockLost, owner)
+	}
+	return nil
+}
+
+// withMigrationLock runs fn while holding the migration lock, so the instances that start at the same time
+// do not migrate the schema together. The lock is a row of schema_migrations_lock instead of a transaction
+// since the migrations run in their own transactions. It is refreshed while fn runs so it does not expire
+// during the long migrations, and fn should verify it in the transaction of each migration
+func withMigrationLock(fn func(lock migrationLock) error) error {
+	if err := createMigrationTables(); err != nil {
+		return err
+	}
+
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s/%d/%s", hostname, os.Getpid(), uuid.NewString())
+	deadline := time.Now().Add(migrationLockWait)
+
+	for {
+		result := DB.Exec(fmt.Sprintf(`INSERT INTO schema_migrations_lock (id, owner, locked_at) VALUES (1, ?, now())
+			ON CONFLICT (id) DO UPDATE SET owner = excluded.owner, locked_at = excluded.locked_at
+			WHERE schema_migrations_lock.locked_at < now() - INTERVAL '%d minutes'`, migrationLockExpiryMinutes), owner)
+		if result.Error != nil {
+			return fmt.Errorf("failed to take the migration lock, error: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: the lock is not released after %s", ErrMigrationLocked, migrationLockWait)
+		}
+		log.Println("waiting for another instance to finish the migrations")
+		time.Sleep(migrationLockRetryInterval)
+	}
+
+	stopRefresh := make(chan struct{})
+	refreshStopped := make(chan struct{})
+	go refreshMigrationLock(owner, stopRefresh, refreshStopped)
+
+	defer func() {
+		close(stopRefresh)
+		<-refreshStopped
+
+		if err := DB.Exec("DELETE FROM schema_migrations_lock WHERE id = 1 AND owner = ?", owner).Error; err != nil {
+			log.Printf("failed to release the migration lock, it is released after %d minutes. error: %+v", migrationLockExpiryMinutes, err)
+		}
+	}()
+
+	return fn(migrationLock{owner: owner})
+}
+
+// refreshMigrationLock keeps the lock of the owner from expiring until stop is closed
+func refreshMigrationLock(owner string, stop <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(migrationLockRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			result := DB.Exec("UPDATE schema_migrations_lock SET locked_at = now() WHERE id = 1 AND owner = ?", owner)
+			if result.Error != nil {
+				log.Printf("failed to refresh the migration lock, error: %+v", result.Error)
+				continue
+			}
+			if result.RowsAffected == 0 {
+				// the next migration fails to verify the lock, so nothing else is committed by this instance
+				log.Println("the migration lock is taken by another instance")
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package cockroachdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMigrations(t *testing.T) {
+	type testCase struct {
+		name             string
+		files            fstest.MapFS
+		expectedError    error
+		expectedVersions []int64
+	}
+
+	testCases := []testCase{
+		{
+			name: "the migrations are given out of order and one of them does not have a down file. In this case, they should be sorted by their versions",
+			files: fstest.MapFS{
+				"migrations/0010_add_notes.up.sql":        {Data: []byte("ALTER TABLE subscribers ADD COLUMN notes TEXT;")},
+				"migrations/0002_add_index.up.sql":        {Data: []byte("CREATE INDEX idx_a ON a (b);")},
+				"migrations/0002_add_index.down.sql":      {Data: []byte("DROP INDEX a@idx_a;")},
+				"migrations/0001_initial_schema.up.sql":   {Data: []byte("CREATE TABLE a (b INT);")},
+				"migrations/0001_initial_schema.down.sql": {Data: []byte("DROP TABLE a;")},
+			},
+			expectedVersions: []int64{1, 2, 10},
+		},
+		{
+			name: "a migration only has a down file. In this case, it should be rejected",
+			files: fstest.MapFS{
+				"migrations/0001_initial_schema.down.sql": {Data: []byte("DROP TABLE a;")},
+			},
+			expectedError: ErrInvalidMigration,
+		},
+		{
+			name: "two migrations have the same version. In this case, it should be rejected",
+			files: fstest.MapFS{
+				"migrations/0001_initial_schema.up.sql": {Data: []byte("CREATE TABLE a (b INT);")},
+				"migrations/0001_add_index.up.sql":      {Data: []byte("CREATE INDEX idx_a ON a (b);")},
+			},
+			expectedError: ErrInvalidMigration,
+		},
+		{
+			name: "a file does not follow the naming of the migrations. In this case, it should be rejected",
+			files: fstest.MapFS{
+				"migrations/initial_schema.sql": {Data: []byte("CREATE TABLE a (b INT);")},
+			},
+			expectedError: ErrInvalidMigration,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			migrations, err := parseMigrations(tc.files, "migrations")
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+
+			versions := []int64{}
+			for _, migration := range migrations {
+				versions = append(versions, migration.Version)
+				assert.NotEmpty(t, migration.Checksum)
+			}
+			assert.Equal(t, tc.expectedVersions, versions)
+		})
+	}
+}
+
+func TestCheckModifiedMigrations(t *testing.T) {
+	type testCase struct {
+		name              string
+		appliedMigrations map[int64]schemaMigration
+		expectedError     error
+	}
+
+	migrations := []Migration{
+		{Version: 1, Name: "initial_schema", Checksum: "a"},
+		{Version: 2, Name: "add_index", Checksum: "b"},
+	}
+
+	testCases := []testCase{
+		{
+			name:              "the applied migrations are not changed. In this case, it should be accepted",
+			appliedMigrations: map[int64]schemaMigration{1: {Version: 1, Checksum: "a"}},
+		},
+		{
+			name:              "nothing is applied yet. In this case, it should be accepted",
+			appliedMigrations: map[int64]schemaMigration{},
+		},
+		{
+			name:              "the up file of an applied migration is changed. In this case, it should be rejected",
+			appliedMigrations: map[int64]schemaMigration{1: {Version: 1, Checksum: "a"}, 2: {Version: 2, Checksum: "c"}},
+			expectedError:     ErrModifiedMigration,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := checkModifiedMigrations(migrations, tc.appliedMigrations)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.ErrorContains(t, err, "0002_add_index")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	assert.NoError(t, err)
+
+	// the baseline should exist and every migration should be reversible unless it is documented otherwise
+	assert.NotEmpty(t, migrations)
+	assert.Equal(t, int64(baselineVersion), migrations[0].Version)
+	for _, migration := range migrations {
+		assert.NotEmpty(t, migration.Down, migration.Name)
+	}
+}
+
+func TestCreateMigration(t *testing.T) {
+	directory := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, "0001_initial_schema.up.sql"), []byte("CREATE TABLE a (b INT);"), 0o644))
+
+	upFile, downFile, err := CreateMigration(directory, "add_notes")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(directory, "0002_add_notes.up.sql"), upFile)
+	assert.Equal(t, filepath.Join(directory, "0002_add_notes.down.sql"), downFile)
+
+	migrations, err := parseMigrations(os.DirFS(directory), ".")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(migrations))
+
+	_, _, err = CreateMigration(directory, "Add Notes")
+	assert.ErrorIs(t, err, ErrInvalidMigration)
+}
@@ -0,0 +1,21 @@
+-- Drops the whole schema of OSPM, all of the data is lost.
+
+DROP TABLE IF EXISTS "audit_entries" CASCADE;
+DROP TABLE IF EXISTS "api_keys" CASCADE;
+DROP TABLE IF EXISTS "subscription_events" CASCADE;
+DROP TABLE IF EXISTS "subscriptions" CASCADE;
+DROP TABLE IF EXISTS "product_offering_specifications" CASCADE;
+DROP TABLE IF EXISTS "product_offerings" CASCADE;
+DROP TABLE IF EXISTS "permissions" CASCADE;
+DROP TABLE IF EXISTS "subscriber_groups" CASCADE;
+DROP TABLE IF EXISTS "ledger_entries" CASCADE;
+DROP TABLE IF EXISTS "ledger_transactions" CASCADE;
+DROP TABLE IF EXISTS "accounting_records" CASCADE;
+DROP TABLE IF EXISTS "accounting_sessions" CASCADE;
+DROP TABLE IF EXISTS "subscriber_sessions" CASCADE;
+DROP TABLE IF EXISTS "credentials" CASCADE;
+DROP TABLE IF EXISTS "subscriber_details" CASCADE;
+DROP TABLE IF EXISTS "subscribers" CASCADE;
+DROP TABLE IF EXISTS "organization_owners" CASCADE;
+DROP TABLE IF EXISTS "organization_details" CASCADE;
+DROP TABLE IF EXISTS "organizations" CASCADE;
@@ -0,0 +1,415 @@
+-- The schema of OSPM before the versioned migrations were introduced.
+-- It is the same schema that the auto migration of the models used to create.
+
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS "organizations" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "balance_amount" decimal(19,4) NOT NULL DEFAULT 0,
+    "balance_currency" char(3) NOT NULL DEFAULT '',
+    "allow_nagative_balance" boolean NOT NULL,
+    "negative_balance_threshold_amount" decimal(19,4) NOT NULL DEFAULT 0,
+    "negative_balance_threshold_currency" char(3) NOT NULL DEFAULT '',
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_organizations_allow_nagative_balance" ON "organizations" ("allow_nagative_balance");
+CREATE INDEX IF NOT EXISTS "idx_organizations_deleted_at" ON "organizations" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "organization_details" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text,
+    "address" text,
+    "email" text,
+    "mobile" text,
+    "phone" text,
+    "organization_id" uuid NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_organizations_details" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "uni_organization_details_name" UNIQUE ("name"),
+    CONSTRAINT "uni_organization_details_email" UNIQUE ("email"),
+    CONSTRAINT "uni_organization_details_mobile" UNIQUE ("mobile")
+);
+CREATE INDEX IF NOT EXISTS "idx_organization_details_address" ON "organization_details" ("address");
+CREATE INDEX IF NOT EXISTS "idx_organization_details_deleted_at" ON "organization_details" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_organization_details_email" ON "organization_details" ("email");
+CREATE INDEX IF NOT EXISTS "idx_organization_details_mobile" ON "organization_details" ("mobile");
+CREATE INDEX IF NOT EXISTS "idx_organization_details_name" ON "organization_details" ("name");
+CREATE INDEX IF NOT EXISTS "idx_organization_details_organization_id" ON "organization_details" ("organization_id");
+CREATE INDEX IF NOT EXISTS "idx_organization_details_phone" ON "organization_details" ("phone");
+
+CREATE TABLE IF NOT EXISTS "organization_owners" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "type" text,
+    "name" text,
+    "address" text,
+    "email" text NOT NULL,
+    "mobile" text,
+    "phone" text,
+    "legal_national_id" text,
+    "organization_id" uuid NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_organizations_owner" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "uni_organization_owners_name" UNIQUE ("name"),
+    CONSTRAINT "uni_organization_owners_email" UNIQUE ("email"),
+    CONSTRAINT "uni_organization_owners_mobile" UNIQUE ("mobile")
+);
+CREATE INDEX IF NOT EXISTS "idx_organization_owners_address" ON "organization_owners" ("address");
+CREATE INDEX IF NOT EXISTS "idx_organization_owners_deleted_at" ON "organization_owners" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_organization_owners_email" ON "organization_owners" ("email");
+CREATE INDEX IF NOT EXISTS "idx_organization_owners_legal_national_id" ON "organization_owners" ("legal_national_id");
+CREATE INDEX IF NOT EXISTS "idx_organization_owners_mobile" ON "organization_owners" ("mobile");
+CREATE INDEX IF NOT EXISTS "idx_organization_owners_name" ON "organization_owners" ("name");
+CREATE INDEX IF NOT EXISTS "idx_organization_owners_organization_id" ON "organization_owners" ("organization_id");
+CREATE INDEX IF NOT EXISTS "idx_organization_owners_phone" ON "organization_owners" ("phone");
+CREATE INDEX IF NOT EXISTS "idx_organization_owners_type" ON "organization_owners" ("type");
+
+CREATE TABLE IF NOT EXISTS "subscribers" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "organization_id" uuid NOT NULL,
+    "subscriber_group_id" uuid NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_subscribers_deleted_at" ON "subscribers" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_subscribers_organization_id" ON "subscribers" ("organization_id");
+CREATE INDEX IF NOT EXISTS "idx_subscribers_subscriber_group_id" ON "subscribers" ("subscriber_group_id");
+
+CREATE TABLE IF NOT EXISTS "subscriber_details" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text NOT NULL,
+    "email" text NOT NULL,
+    "national_id" text,
+    "passport_id" text,
+    "mobile" text NOT NULL,
+    "phone" text,
+    "subscriber_id" uuid NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_subscribers_details" FOREIGN KEY ("subscriber_id") REFERENCES "subscribers"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "uni_subscriber_details_phone" UNIQUE ("phone"),
+    CONSTRAINT "uni_subscriber_details_name" UNIQUE ("name"),
+    CONSTRAINT "uni_subscriber_details_email" UNIQUE ("email"),
+    CONSTRAINT "uni_subscriber_details_national_id" UNIQUE ("national_id"),
+    CONSTRAINT "uni_subscriber_details_passport_id" UNIQUE ("passport_id"),
+    CONSTRAINT "uni_subscriber_details_mobile" UNIQUE ("mobile")
+);
+CREATE INDEX IF NOT EXISTS "idx_subscriber_details_deleted_at" ON "subscriber_details" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_details_email" ON "subscriber_details" ("email");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_details_mobile" ON "subscriber_details" ("mobile");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_details_name" ON "subscriber_details" ("name");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_details_national_id" ON "subscriber_details" ("national_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_details_passport_id" ON "subscriber_details" ("passport_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_details_phone" ON "subscriber_details" ("phone");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_details_subscriber_id" ON "subscriber_details" ("subscriber_id");
+
+CREATE TABLE IF NOT EXISTS "credentials" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "username" text NOT NULL,
+    "password" text NOT NULL,
+    "chap_secret" text,
+    "subscriber_id" uuid NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_subscribers_credentials" FOREIGN KEY ("subscriber_id") REFERENCES "subscribers"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "uni_credentials_username" UNIQUE ("username")
+);
+CREATE INDEX IF NOT EXISTS "idx_credentials_deleted_at" ON "credentials" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_credentials_subscriber_id" ON "credentials" ("subscriber_id");
+CREATE INDEX IF NOT EXISTS "idx_credentials_username" ON "credentials" ("username");
+
+CREATE TABLE IF NOT EXISTS "subscriber_sessions" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "subscriber_id" uuid NOT NULL,
+    "organization_id" uuid NOT NULL,
+    "subscriber_group_id" uuid NOT NULL,
+    "refresh_token_id" uuid NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "revoked_at" timestamptz,
+    "client_ip" text,
+    "user_agent" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_subscriber_sessions_deleted_at" ON "subscriber_sessions" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_sessions_expires_at" ON "subscriber_sessions" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_sessions_organization_id" ON "subscriber_sessions" ("organization_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_sessions_revoked_at" ON "subscriber_sessions" ("revoked_at");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_sessions_subscriber_group_id" ON "subscriber_sessions" ("subscriber_group_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_sessions_subscriber_id" ON "subscriber_sessions" ("subscriber_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_subscriber_sessions_refresh_token_id" ON "subscriber_sessions" ("refresh_token_id");
+
+CREATE TABLE IF NOT EXISTS "accounting_sessions" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "acct_session_id" text NOT NULL,
+    "nas_identifier" text NOT NULL,
+    "nas_ip_address" text NOT NULL,
+    "nas_port_id" text,
+    "subscriber_id" uuid NOT NULL,
+    "organization_id" uuid NOT NULL,
+    "username" text,
+    "framed_ip_address" text,
+    "calling_station_id" text,
+    "status" text NOT NULL,
+    "started_at" timestamptz NOT NULL,
+    "last_updated_at" timestamptz NOT NULL,
+    "stopped_at" timestamptz,
+    "session_time" bigint NOT NULL,
+    "input_octets" bigint NOT NULL,
+    "output_octets" bigint NOT NULL,
+    "input_packets" bigint NOT NULL,
+    "output_packets" bigint NOT NULL,
+    "terminate_cause" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_accounting_sessions_deleted_at" ON "accounting_sessions" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_accounting_sessions_organization_id" ON "accounting_sessions" ("organization_id");
+CREATE INDEX IF NOT EXISTS "idx_accounting_sessions_started_at" ON "accounting_sessions" ("started_at");
+CREATE INDEX IF NOT EXISTS "idx_accounting_sessions_status" ON "accounting_sessions" ("status");
+CREATE INDEX IF NOT EXISTS "idx_accounting_sessions_stopped_at" ON "accounting_sessions" ("stopped_at");
+CREATE INDEX IF NOT EXISTS "idx_accounting_sessions_subscriber_id" ON "accounting_sessions" ("subscriber_id");
+CREATE INDEX IF NOT EXISTS "idx_accounting_sessions_username" ON "accounting_sessions" ("username");
+CREATE UNIQUE INDEX IF NOT EXISTS "nas_session_idx" ON "accounting_sessions" ("acct_session_id","nas_identifier","nas_ip_address");
+
+CREATE TABLE IF NOT EXISTS "accounting_records" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "accounting_session_id" uuid NOT NULL,
+    "status_type" text NOT NULL,
+    "event_time" timestamptz NOT NULL,
+    "session_time" bigint NOT NULL,
+    "input_octets" bigint NOT NULL,
+    "output_octets" bigint NOT NULL,
+    "input_packets" bigint NOT NULL,
+    "output_packets" bigint NOT NULL,
+    "terminate_cause" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_accounting_records_accounting_session_id" ON "accounting_records" ("accounting_session_id");
+CREATE INDEX IF NOT EXISTS "idx_accounting_records_deleted_at" ON "accounting_records" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_accounting_records_event_time" ON "accounting_records" ("event_time");
+
+CREATE TABLE IF NOT EXISTS "ledger_transactions" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "organization_id" uuid NOT NULL,
+    "request_key" text NOT NULL,
+    "type" text NOT NULL,
+    "currency" char(3) NOT NULL DEFAULT '',
+    "amount" decimal(19,4) NOT NULL,
+    "balance_after" decimal(19,4) NOT NULL,
+    "description" text,
+    "reversed_transaction_id" uuid,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_ledger_transactions_deleted_at" ON "ledger_transactions" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_ledger_transactions_organization_id" ON "ledger_transactions" ("organization_id");
+CREATE INDEX IF NOT EXISTS "idx_ledger_transactions_type" ON "ledger_transactions" ("type");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_ledger_transactions_reversed_transaction_id" ON "ledger_transactions" ("reversed_transaction_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "organization_request_key_idx" ON "ledger_transactions" ("organization_id","request_key");
+
+CREATE TABLE IF NOT EXISTS "ledger_entries" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "transaction_id" uuid NOT NULL,
+    "organization_id" uuid NOT NULL,
+    "account" text NOT NULL,
+    "amount" decimal(19,4) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_ledger_transactions_entries" FOREIGN KEY ("transaction_id") REFERENCES "ledger_transactions"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_ledger_entries_deleted_at" ON "ledger_entries" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_ledger_entries_transaction_id" ON "ledger_entries" ("transaction_id");
+CREATE INDEX IF NOT EXISTS "organization_account_idx" ON "ledger_entries" ("organization_id","account");
+
+CREATE TABLE IF NOT EXISTS "subscriber_groups" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text NOT NULL,
+    "description" text,
+    "organization_id" uuid NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_subscriber_groups_deleted_at" ON "subscriber_groups" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_subscriber_groups_name" ON "subscriber_groups" ("name");
+CREATE UNIQUE INDEX IF NOT EXISTS "org_name_idx" ON "subscriber_groups" ("name","organization_id");
+
+CREATE TABLE IF NOT EXISTS "permissions" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "subscriber_group_id" uuid NOT NULL,
+    "permission_name" text,
+    "permission_value" text,
+    "permission_category" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_subscriber_groups_permissions" FOREIGN KEY ("subscriber_group_id") REFERENCES "subscriber_groups"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_permissions_deleted_at" ON "permissions" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_permissions_permission_category" ON "permissions" ("permission_category");
+CREATE INDEX IF NOT EXISTS "idx_permissions_permission_name" ON "permissions" ("permission_name");
+CREATE INDEX IF NOT EXISTS "idx_permissions_subscriber_group_id" ON "permissions" ("subscriber_group_id");
+
+CREATE TABLE IF NOT EXISTS "product_offerings" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text NOT NULL,
+    "persian_name" text,
+    "characteristic_value" text,
+    "characteristic_value_type" text,
+    "specification_id" uuid,
+    "description" text,
+    "lifecycle_status" text NOT NULL DEFAULT 'draft',
+    "valid_from" timestamptz NOT NULL DEFAULT current_timestamp(),
+    "valid_to" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_product_offerings_persian_name" UNIQUE ("persian_name"),
+    CONSTRAINT "uni_product_offerings_name" UNIQUE ("name")
+);
+CREATE INDEX IF NOT EXISTS "idx_product_offerings_deleted_at" ON "product_offerings" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_product_offerings_lifecycle_status" ON "product_offerings" ("lifecycle_status");
+CREATE INDEX IF NOT EXISTS "idx_product_offerings_name" ON "product_offerings" ("name");
+CREATE INDEX IF NOT EXISTS "idx_product_offerings_persian_name" ON "product_offerings" ("persian_name");
+CREATE INDEX IF NOT EXISTS "idx_product_offerings_valid_from" ON "product_offerings" ("valid_from");
+CREATE INDEX IF NOT EXISTS "idx_product_offerings_valid_to" ON "product_offerings" ("valid_to");
+
+CREATE TABLE IF NOT EXISTS "product_offering_specifications" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text NOT NULL,
+    "persian_name" text,
+    "type" text NOT NULL,
+    "description" text,
+    "lifecycle_status" text NOT NULL DEFAULT 'draft',
+    "valid_from" timestamptz NOT NULL DEFAULT current_timestamp(),
+    "valid_to" timestamptz,
+    "characteristic_value_type" text,
+    "characteristic_min_value" text,
+    "characteristic_max_value" text,
+    "characteristic_allowed_values" jsonb,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_product_offering_specifications_name" UNIQUE ("name"),
+    CONSTRAINT "uni_product_offering_specifications_persian_name" UNIQUE ("persian_name")
+);
+CREATE INDEX IF NOT EXISTS "idx_product_offering_specifications_deleted_at" ON "product_offering_specifications" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_product_offering_specifications_lifecycle_status" ON "product_offering_specifications" ("lifecycle_status");
+CREATE INDEX IF NOT EXISTS "idx_product_offering_specifications_name" ON "product_offering_specifications" ("name");
+CREATE INDEX IF NOT EXISTS "idx_product_offering_specifications_persian_name" ON "product_offering_specifications" ("persian_name");
+CREATE INDEX IF NOT EXISTS "idx_product_offering_specifications_valid_from" ON "product_offering_specifications" ("valid_from");
+CREATE INDEX IF NOT EXISTS "idx_product_offering_specifications_valid_to" ON "product_offering_specifications" ("valid_to");
+
+CREATE TABLE IF NOT EXISTS "subscriptions" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "subscriber_id" uuid NOT NULL,
+    "product_offering_id" uuid NOT NULL,
+    "status" text NOT NULL DEFAULT 'pending',
+    "start_date" timestamptz NOT NULL,
+    "end_date" timestamptz,
+    "previous_subscription_id" uuid,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_subscribers_subscriptions" FOREIGN KEY ("subscriber_id") REFERENCES "subscribers"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_deleted_at" ON "subscriptions" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_end_date" ON "subscriptions" ("end_date");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_previous_subscription_id" ON "subscriptions" ("previous_subscription_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_product_offering_id" ON "subscriptions" ("product_offering_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_start_date" ON "subscriptions" ("start_date");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_status" ON "subscriptions" ("status");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_subscriber_id" ON "subscriptions" ("subscriber_id");
+
+CREATE TABLE IF NOT EXISTS "subscription_events" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "subscription_id" uuid NOT NULL,
+    "action" text NOT NULL,
+    "from_status" text,
+    "to_status" text NOT NULL,
+    "product_offering_id" uuid,
+    "reason" text,
+    "effective_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_subscriptions_history" FOREIGN KEY ("subscription_id") REFERENCES "subscriptions"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_subscription_events_deleted_at" ON "subscription_events" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_subscription_events_effective_at" ON "subscription_events" ("effective_at");
+CREATE INDEX IF NOT EXISTS "idx_subscription_events_subscription_id" ON "subscription_events" ("subscription_id");
+
+CREATE TABLE IF NOT EXISTS "api_keys" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text NOT NULL,
+    "prefix" text NOT NULL,
+    "key_hash" text NOT NULL,
+    "scopes" jsonb,
+    "expires_at" timestamptz,
+    "revoked_at" timestamptz,
+    "last_used_at" timestamptz,
+    "rotated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_api_keys_deleted_at" ON "api_keys" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_api_keys_expires_at" ON "api_keys" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_api_keys_name" ON "api_keys" ("name");
+CREATE INDEX IF NOT EXISTS "idx_api_keys_revoked_at" ON "api_keys" ("revoked_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_api_keys_prefix" ON "api_keys" ("prefix");
+
+CREATE TABLE IF NOT EXISTS "audit_entries" (
+    "sequence" bigint,
+    "created_at" timestamptz NOT NULL,
+    "actor_api_key_id" text,
+    "actor_ip" text,
+    "request_id" text,
+    "action" text NOT NULL,
+    "entity_type" text NOT NULL,
+    "entity_id" text,
+    "before" jsonb,
+    "after" jsonb,
+    "previous_hash" text NOT NULL,
+    "hash" text NOT NULL,
+    PRIMARY KEY ("sequence")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_entity" ON "audit_entries" ("entity_type","entity_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_actor_api_key_id" ON "audit_entries" ("actor_api_key_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_actor_ip" ON "audit_entries" ("actor_ip");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_created_at" ON "audit_entries" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_request_id" ON "audit_entries" ("request_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_audit_entries_hash" ON "audit_entries" ("hash");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "organizations"@"idx_organizations_balance_amount_id";
+DROP INDEX IF EXISTS "organizations"@"idx_organizations_created_at_id";
@@ -0,0 +1,6 @@
+-- The indexes of the sort keys of the organization list. The id is the tie-breaker of the equal values,
+-- so each page continues from the position of the last organization of the previous page by the index.
+-- The name is already indexed by the unique index of organization_details.
+
+CREATE INDEX IF NOT EXISTS "idx_organizations_created_at_id" ON "organizations" ("created_at", "id");
+CREATE INDEX IF NOT EXISTS "idx_organizations_balance_amount_id" ON "organizations" ("balance_amount", "id");
@@ -0,0 +1,215 @@
+package cockroachdb
+
+import (
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// organizationSortColumns are the columns of the sort keys of the organization list
+var organizationSortColumns = map[string]string{
+	models.OrganizationSortName:      "organization_details.name",
+	models.OrganizationSortCreatedAt: "organizations.created_at",
+	models.OrganizationSortBalance:   "organizations.balance_amount",
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, so they are matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// OrganizationRepository is the GORM implementation of repository.OrganizationRepository
+type OrganizationRepository struct {
+	AuditRepository
+	db *gorm.DB
+}
+
+// NewOrganizationRepository returns an organization repository on the given database or transaction
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{AuditRepository: AuditRepository{db: db}, db: db}
+}
+
+func (r *OrganizationRepository) Transaction(fn func(tx repository.OrganizationRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewOrganizationRepository(tx))
+	})
+}
+
+func (r *OrganizationRepository) List(query repository.OrganizationListQuery) ([]models.Organization, error) {
+	organizationList := []models.Organization{}
+
+	column, found := organizationSortColumns[query.SortKey]
+	if !found {
+		return nil, fmt.Errorf("unknown sort key %s", query.SortKey)
+	}
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	db := r.filtered(query.Filter)
+	if query.Filter.Deleted == models.OrganizationDeletedInclude || query.Filter.Deleted == models.OrganizationDeletedOnly {
+		db = db.Preload("Details", unscoped).Preload("Owner", unscoped)
+	} else {
+		db = db.Preload("Details").Preload("Owner")
+	}
+
+	// the organizations with the same value of the sort key are ordered by their ids,
+	// so the position of each organization in the list is unique
+	if query.After != nil {
+		db = db.Where(fmt.Sprintf("(%s, organizations.id) %s (?, ?)", column, comparison), query.After.Value, query.After.ID)
+	}
+
+	err := db.Order(fmt.Sprintf("%s %s, organizations.id %s", column, direction, direction)).
+		Limit(query.Limit).
+		Find(&organizationList).Error
+	return organizationList, err
+}
+
+func (r *OrganizationRepository) Count(filter models.OrganizationFilter) (int64, error) {
+	var count int64
+	err := r.filtered(filter).Count(&count).Error
+	return count, err
+}
+
+// filtered returns the query of the organizations that match the filter
+func (r *OrganizationRepository) filtered(filter models.OrganizationFilter) *gorm.DB {
+	query := r.db.Model(&models.Organization{}).
+		Joins("left join organization_details on organization_details.organization_id = organizations.id").
+		Joins("left join organization_owners on organization_owners.organization_id = organizations.id")
+
+	switch filter.Deleted {
+	case models.OrganizationDeletedInclude:
+		query = query.Unscoped()
+	case models.OrganizationDeletedOnly:
+		query = query.Unscoped().Where("organizations.deleted_at IS NOT NULL")
+	}
+
+	if filter.NamePrefix != "" {
+		query = query.Where("organization_details.name ILIKE ?", likeEscaper.Replace(filter.NamePrefix)+"%")
+	}
+	if filter.OwnerType != "" {
+		query = query.Where("organization_owners.type = ?", filter.OwnerType)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("organizations.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("organizations.created_at <= ?", *filter.CreatedTo)
+	}
+	if filter.BalanceMin != nil {
+		query = query.Where("organizations.balance_amount >= ?", *filter.BalanceMin)
+	}
+	if filter.BalanceMax != nil {
+		query = query.Where("organizations.balance_amount <= ?", *filter.BalanceMax)
+	}
+
+	return query
+}
+
+func (r *OrganizationRepository) Find(lookup repository.OrganizationLookup) (models.Organization, error) {
+	var organization models.Organization
+
+	query := r.db
+	if lookup.Lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "organizations"}})
+	}
+	if lookup.Unscoped {
+		query = query.Unscoped().Preload("Details", unscoped).Preload("Owner", unscoped)
+	} else {
+		query = query.Preload("Details").Preload("Owner")
+	}
+	query = query.Joins("left join organization_details on organization_details.organization_id = organizations.id")
+
+	if lookup.ID != "" {
+		query = query.Where("organizations.id = ?", lookup.ID)
+	}
+	if lookup.Name != "" {
+		query = query.Where("organization_details.name = ?", lookup.Name)
+	}
+
+	err := query.First(&organization).Error
+	return organization, err
+}
+
+func (r *OrganizationRepository) Create(organization *models.Organization) error {
+	return r.db.Create(organization).Error
+}
+
+func (r *OrganizationRepository) Delete(organization *models.Organization, hard bool) error {
+	query := r.db
+	if hard {
+		query = query.Unscoped()
+	}
+
+	return query.Select("Details", "Owner").Delete(organization).Error
+}
+
+func (r *OrganizationRepository) Recover(organizationID string) error {
+	err := r.db.Unscoped().Model(&models.Organization{}).Where("id = ?", organizationID).Update("deleted_at", nil).Error
+	if err != nil {
+		return err
+	}
+
+	err = r.db.Unscoped().Model(&models.OrganizationDetails{}).Where("organization_id = ?", organizationID).Update("deleted_at", nil).Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.Unscoped().Model(&models.OrganizationOwner{}).Where("organization_id = ?", organizationID).Update("deleted_at", nil).Error
+}
+
+func (r *OrganizationRepository) UpdateProfile(organization *models.Organization) error {
+	err := r.db.Model(&organization.Details).Updates(map[string]interface{}{
+		"name":    organization.Details.Name,
+		"address": organization.Details.Address,
+		"email":   organization.Details.Email,
+		"mobile":  organization.Details.Mobile,
+		"phone":   organization.Details.Phone,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.Model(&organization.Owner).Updates(map[string]interface{}{
+		"type":              organization.Owner.Type,
+		"name":              organization.Owner.Name,
+		"address":           organization.Owner.Address,
+		"email":             organization.Owner.Email,
+		"mobile":            organization.Owner.Mobile,
+		"phone":             organization.Owner.Phone,
+		"legal_national_id": organization.Owner.LegalNationalID,
+	}).Error
+}
+
+func (r *OrganizationRepository) UpdateBalancePolicy(organization *models.Organization) error {
+	return r.db.Model(organization).Updates(map[string]interface{}{
+		"allow_nagative_balance":            organization.AllowNagativeBalance,
+		"negative_balance_threshold_amount": organization.NegativeBalanceThreshold.Amount,
+	}).Error
+}
+
+func (r *OrganizationRepository) ProfileValueUsed(owner bool, field string, value string, organizationID string) (bool, error) {
+	if field != "name" && field != "email" && field != "mobile" {
+		return false, fmt.Errorf("unknown unique profile field %s", field)
+	}
+
+	var model interface{} = &models.OrganizationDetails{}
+	if owner {
+		model = &models.OrganizationOwner{}
+	}
+
+	var count int64
+	err := r.db.Unscoped().Model(model).
+		Where(fmt.Sprintf("%s = ? AND organization_id <> ?", field), value, organizationID).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+// unscoped loads the soft deleted associations too
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
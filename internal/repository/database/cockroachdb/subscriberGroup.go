@@ -0,0 +1,90 @@
+package cockroachdb
+
+import (
+	"ospm/internal/models"
+	"ospm/internal/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SubscriberGroupRepository is the GORM implementation of repository.SubscriberGroupRepository
+type SubscriberGroupRepository struct {
+	AuditRepository
+	db *gorm.DB
+}
+
+// NewSubscriberGroupRepository returns a subscriber group repository on the given database or transaction
+func NewSubscriberGroupRepository(db *gorm.DB) *SubscriberGroupRepository {
+	return &SubscriberGroupRepository{AuditRepository: AuditRepository{db: db}, db: db}
+}
+
+func (r *SubscriberGroupRepository) Transaction(fn func(tx repository.SubscriberGroupRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewSubscriberGroupRepository(tx))
+	})
+}
+
+func (r *SubscriberGroupRepository) List(organizationID string) ([]models.SubscriberGroupMinimal, error) {
+	var groupList []models.SubscriberGroupMinimal
+
+	err := r.db.
+		Model(&models.SubscriberGroup{}).
+		Select("id,name").
+		Where("organization_id =  ?", organizationID).
+		Find(&groupList).Error
+
+	return groupList, err
+}
+
+func (r *SubscriberGroupRepository) Find(subscriberGroupID string, lock bool) (models.SubscriberGroup, error) {
+	var subscriberGroup models.SubscriberGroup
+
+	query := r.db
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	if err := query.First(&subscriberGroup, "id = ?", subscriberGroupID).Error; err != nil {
+		return models.SubscriberGroup{}, err
+	}
+
+	err := r.db.Where("subscriber_group_id = ?", subscriberGroupID).Find(&subscriberGroup.Permissions).Error
+	return subscriberGroup, err
+}
+
+func (r *SubscriberGroupRepository) Create(subscriberGroup *models.SubscriberGroup) error {
+	return r.db.Create(subscriberGroup).Error
+}
+
+func (r *SubscriberGroupRepository) UpdateDetails(subscriberGroup *models.SubscriberGroup) error {
+	return r.db.Model(subscriberGroup).Updates(map[string]interface{}{
+		"name":        subscriberGroup.Name,
+		"description": subscriberGroup.Description,
+	}).Error
+}
+
+func (r *SubscriberGroupRepository) Delete(subscriberGroupID string) error {
+	err := r.db.Unscoped().Where("id = ?", subscriberGroupID).Delete(&models.SubscriberGroup{}).Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.Unscoped().Where("subscriber_group_id = ?", subscriberGroupID).Delete(&models.Permission{}).Error
+}
+
+func (r *SubscriberGroupRepository) AddPermissions(permissions []models.Permission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	return r.db.Create(&permissions).Error
+}
+
+func (r *SubscriberGroupRepository) UpdatePermissionValue(permissionID string, value string) error {
+	return r.db.Model(&models.Permission{}).Where("id = ?", permissionID).Update("permission_value", value).Error
+}
+
+func (r *SubscriberGroupRepository) RemovePermission(permissionID string) error {
+	return r.db.Unscoped().Delete(&models.Permission{}, "id = ?", permissionID).Error
+}
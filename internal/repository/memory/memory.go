@@ -0,0 +1,69 @@
+// Package memory contains the in-memory implementations of the repositories. They keep the same
+// behavior as the database implementations, including the transactions and the unique constraints,
+// so the services and the handlers can be tested without a database
+package memory
+
+import (
+	"ospm/internal/models"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// store serializes the access to the state of a repository. A transaction holds the lock until it
+// ends and works on a copy of the state which replaces the state when the transaction is committed
+type store struct {
+	lock *sync.Mutex // nil within a transaction since the transaction already holds the lock
+}
+
+func newStore() store {
+	return store{lock: &sync.Mutex{}}
+}
+
+func (s store) locked() func() {
+	if s.lock == nil {
+		return func() {}
+	}
+
+	s.lock.Lock()
+	return s.lock.Unlock
+}
+
+// auditLog is the audit log part of the state of the repositories
+type auditLog struct {
+	entries []models.AuditEntry
+}
+
+func (l *auditLog) last() models.AuditEntry {
+	if len(l.entries) == 0 {
+		return models.AuditEntry{}
+	}
+	return l.entries[len(l.entries)-1]
+}
+
+func (l *auditLog) append(entry *models.AuditEntry) error {
+	for _, existing := range l.entries {
+		if existing.Sequence == entry.Sequence || existing.Hash == entry.Hash {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	l.entries = append(l.entries, *entry)
+	return nil
+}
+
+func (l auditLog) clone() auditLog {
+	return auditLog{entries: append([]models.AuditEntry{}, l.entries...)}
+}
+
+// newModel returns the fields of a new row like the database does
+func newModel() (string, gorm.Model) {
+	now := time.Now()
+	return uuid.NewString(), gorm.Model{CreatedAt: now, UpdatedAt: now}
+}
+
+func deletedNow() gorm.DeletedAt {
+	return gorm.DeletedAt{Time: time.Now(), Valid: true}
+}
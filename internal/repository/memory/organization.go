@@ -0,0 +1,350 @@
+package memory
+
+import (
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OrganizationRepository is the in-memory implementation of repository.OrganizationRepository
+type OrganizationRepository struct {
+	store
+	state *organizationState
+}
+
+type organizationState struct {
+	organizations []models.Organization // in the order they are created
+	audit         auditLog
+}
+
+// NewOrganizationRepository returns an empty organization repository
+func NewOrganizationRepository() *OrganizationRepository {
+	return &OrganizationRepository{store: newStore(), state: &organizationState{}}
+}
+
+// AuditEntries returns the audit log in the order of the entries
+func (r *OrganizationRepository) AuditEntries() []models.AuditEntry {
+	defer r.locked()()
+	return r.state.audit.clone().entries
+}
+
+func (r *OrganizationRepository) LastAuditEntry() (models.AuditEntry, error) {
+	defer r.locked()()
+	return r.state.audit.last(), nil
+}
+
+func (r *OrganizationRepository) AppendAuditEntry(entry *models.AuditEntry) error {
+	defer r.locked()()
+	return r.state.audit.append(entry)
+}
+
+func (r *OrganizationRepository) Transaction(fn func(tx repository.OrganizationRepository) error) error {
+	defer r.locked()()
+
+	state := &organizationState{
+		organizations: append([]models.Organization{}, r.state.organizations...),
+		audit:         r.state.audit.clone(),
+	}
+	if err := fn(&OrganizationRepository{state: state}); err != nil {
+		return err
+	}
+
+	*r.state = *state
+	return nil
+}
+
+func (r *OrganizationRepository) List(query repository.OrganizationListQuery) ([]models.Organization, error) {
+	defer r.locked()()
+
+	if _, found := organizationSortValue(models.Organization{}, query.SortKey); !found {
+		return nil, fmt.Errorf("unknown sort key %s", query.SortKey)
+	}
+
+	// compare orders the organizations by the sort key and then by their ids like the database does
+	compare := func(value interface{}, id string, organization models.Organization) int {
+		sortValue, _ := organizationSortValue(organization, query.SortKey)
+		result := compareSortValues(value, sortValue)
+		if result == 0 {
+			result = strings.Compare(id, organization.ID)
+		}
+		if query.Descending {
+			result = -result
+		}
+		return result
+	}
+
+	organizationList := []models.Organization{}
+	for _, organization := range r.state.organizations {
+		if !organizationMatches(organization, query.Filter) {
+			continue
+		}
+		if query.After != nil && compare(query.After.Value, query.After.ID, organization) >= 0 {
+			continue
+		}
+		organizationList = append(organizationList, organization)
+	}
+
+	sort.Slice(organizationList, func(i, j int) bool {
+		sortValue, _ := organizationSortValue(organizationList[i], query.SortKey)
+		return compare(sortValue, organizationList[i].ID, organizationList[j]) < 0
+	})
+
+	if query.Limit > 0 && len(organizationList) > query.Limit {
+		organizationList = organizationList[:query.Limit]
+	}
+	return organizationList, nil
+}
+
+func (r *OrganizationRepository) Count(filter models.OrganizationFilter) (int64, error) {
+	defer r.locked()()
+
+	var count int64
+	for _, organization := range r.state.organizations {
+		if organizationMatches(organization, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *OrganizationRepository) Find(lookup repository.OrganizationLookup) (models.Organization, error) {
+	defer r.locked()()
+
+	for _, organization := range r.state.organizations {
+		if organization.DeletedAt.Valid && !lookup.Unscoped {
+			continue
+		}
+		if lookup.ID != "" && organization.ID != lookup.ID {
+			continue
+		}
+		if lookup.Name != "" && organization.Details.Name != lookup.Name {
+			continue
+		}
+		return organization, nil
+	}
+
+	return models.Organization{}, gorm.ErrRecordNotFound
+}
+
+func (r *OrganizationRepository) Create(organization *models.Organization) error {
+	defer r.locked()()
+
+	if err := r.uniqueCheck(organization); err != nil {
+		return err
+	}
+
+	organization.ID, organization.Model = newModel()
+	organization.Details.ID, organization.Details.Model = newModel()
+	organization.Owner.ID, organization.Owner.Model = newModel()
+	organization.Details.OrganizationID = organization.ID
+	organization.Owner.OrganizationID = organization.ID
+
+	r.state.organizations = append(r.state.organizations, *organization)
+	return nil
+}
+
+func (r *OrganizationRepository) Delete(organization *models.Organization, hard bool) error {
+	defer r.locked()()
+
+	for index := range r.state.organizations {
+		if r.state.organizations[index].ID != organization.ID {
+			continue
+		}
+
+		if hard {
+			r.state.organizations = append(r.state.organizations[:index:index], r.state.organizations[index+1:]...)
+			return nil
+		}
+
+		stored := &r.state.organizations[index]
+		stored.DeletedAt = deletedNow()
+		stored.Details.DeletedAt = stored.DeletedAt
+		stored.Owner.DeletedAt = stored.DeletedAt
+		return nil
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) Recover(organizationID string) error {
+	defer r.locked()()
+
+	if stored := r.stored(organizationID); stored != nil {
+		stored.DeletedAt = gorm.DeletedAt{}
+		stored.Details.DeletedAt = gorm.DeletedAt{}
+		stored.Owner.DeletedAt = gorm.DeletedAt{}
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) UpdateProfile(organization *models.Organization) error {
+	defer r.locked()()
+
+	if err := r.uniqueCheck(organization); err != nil {
+		return err
+	}
+
+	if stored := r.stored(organization.ID); stored != nil {
+		stored.Details.Name = organization.Details.Name
+		stored.Details.Address = organization.Details.Address
+		stored.Details.Email = organization.Details.Email
+		stored.Details.Mobile = organization.Details.Mobile
+		stored.Details.Phone = organization.Details.Phone
+		stored.Details.UpdatedAt = time.Now()
+
+		stored.Owner.Type = organization.Owner.Type
+		stored.Owner.Name = organization.Owner.Name
+		stored.Owner.Address = organization.Owner.Address
+		stored.Owner.Email = organization.Owner.Email
+		stored.Owner.Mobile = organization.Owner.Mobile
+		stored.Owner.Phone = organization.Owner.Phone
+		stored.Owner.LegalNationalID = organization.Owner.LegalNationalID
+		stored.Owner.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) UpdateBalancePolicy(organization *models.Organization) error {
+	defer r.locked()()
+
+	if stored := r.stored(organization.ID); stored != nil {
+		stored.AllowNagativeBalance = organization.AllowNagativeBalance
+		stored.NegativeBalanceThreshold.Amount = organization.NegativeBalanceThreshold.Amount
+		stored.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) ProfileValueUsed(owner bool, field string, value string, organizationID string) (bool, error) {
+	defer r.locked()()
+
+	if field != "name" && field != "email" && field != "mobile" {
+		return false, fmt.Errorf("unknown unique profile field %s", field)
+	}
+
+	for _, organization := range r.state.organizations {
+		if organization.ID != organizationID && uniqueValues(&organization, owner)[field] == value {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *OrganizationRepository) stored(organizationID string) *models.Organization {
+	for index := range r.state.organizations {
+		if r.state.organizations[index].ID == organizationID {
+			return &r.state.organizations[index]
+		}
+	}
+	return nil
+}
+
+// uniqueCheck applies the unique constraints of the details and the owner, including the soft deleted organizations
+func (r *OrganizationRepository) uniqueCheck(organization *models.Organization) error {
+	for _, stored := range r.state.organizations {
+		if stored.ID == organization.ID {
+			continue
+		}
+
+		for _, owner := range []bool{false, true} {
+			storedValues := uniqueValues(&stored, owner)
+			for field, value := range uniqueValues(organization, owner) {
+				if storedValues[field] == value {
+					return fmt.Errorf("%w: %s %s is already used", gorm.ErrDuplicatedKey, field, value)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// uniqueValues returns the unique fields of the details, or of the owner if owner is true
+func uniqueValues(organization *models.Organization, owner bool) map[string]string {
+	if owner {
+		return map[string]string{
+			"name":   organization.Owner.Name,
+			"email":  organization.Owner.Email,
+			"mobile": organization.Owner.Mobile,
+		}
+	}
+
+	return map[string]string{
+		"name":   organization.Details.Name,
+		"email":  organization.Details.Email,
+		"mobile": organization.Details.Mobile,
+	}
+}
+
+// organizationMatches determines whether the organization matches the filter like the database query does
+func organizationMatches(organization models.Organization, filter models.OrganizationFilter) bool {
+	switch filter.Deleted {
+	case models.OrganizationDeletedInclude:
+	case models.OrganizationDeletedOnly:
+		if !organization.DeletedAt.Valid {
+			return false
+		}
+	default:
+		if organization.DeletedAt.Valid {
+			return false
+		}
+	}
+
+	if filter.NamePrefix != "" && !strings.HasPrefix(strings.ToLower(organization.Details.Name), strings.ToLower(filter.NamePrefix)) {
+		return false
+	}
+	if filter.OwnerType != "" && organization.Owner.Type != filter.OwnerType {
+		return false
+	}
+	if filter.CreatedFrom != nil && organization.CreatedAt.Before(*filter.CreatedFrom) {
+		return false
+	}
+	if filter.CreatedTo != nil && organization.CreatedAt.After(*filter.CreatedTo) {
+		return false
+	}
+	if filter.BalanceMin != nil && organization.Balance.Amount.LessThan(*filter.BalanceMin) {
+		return false
+	}
+	if filter.BalanceMax != nil && filter.BalanceMax.LessThan(organization.Balance.Amount) {
+		return false
+	}
+
+	return true
+}
+
+// organizationSortValue returns the value of the sort key of the organization
+func organizationSortValue(organization models.Organization, sortKey string) (interface{}, bool) {
+	switch sortKey {
+	case models.OrganizationSortName:
+		return organization.Details.Name, true
+	case models.OrganizationSortCreatedAt:
+		return organization.CreatedAt, true
+	case models.OrganizationSortBalance:
+		return organization.Balance.Amount, true
+	}
+	return nil, false
+}
+
+func compareSortValues(a interface{}, b interface{}) int {
+	switch typed := a.(type) {
+	case string:
+		other, _ := b.(string)
+		return strings.Compare(typed, other)
+	case time.Time:
+		other, _ := b.(time.Time)
+		return typed.Compare(other)
+	case models.Decimal:
+		other, _ := b.(models.Decimal)
+		return typed.Cmp(other)
+	}
+	return 0
+}
@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SubscriberGroupRepository is the in-memory implementation of repository.SubscriberGroupRepository
+type SubscriberGroupRepository struct {
+	store
+	state *subscriberGroupState
+}
+
+type subscriberGroupState struct {
+	subscriberGroups []models.SubscriberGroup // without their permissions, in the order they are created
+	permissions      []models.Permission
+	audit            auditLog
+}
+
+// NewSubscriberGroupRepository returns an empty subscriber group repository
+func NewSubscriberGroupRepository() *SubscriberGroupRepository {
+	return &SubscriberGroupRepository{store: newStore(), state: &subscriberGroupState{}}
+}
+
+// AuditEntries returns the audit log in the order of the entries
+func (r *SubscriberGroupRepository) AuditEntries() []models.AuditEntry {
+	defer r.locked()()
+	return r.state.audit.clone().entries
+}
+
+func (r *SubscriberGroupRepository) LastAuditEntry() (models.AuditEntry, error) {
+	defer r.locked()()
+	return r.state.audit.last(), nil
+}
+
+func (r *SubscriberGroupRepository) AppendAuditEntry(entry *models.AuditEntry) error {
+	defer r.locked()()
+	return r.state.audit.append(entry)
+}
+
+func (r *SubscriberGroupRepository) Transaction(fn func(tx repository.SubscriberGroupRepository) error) error {
+	defer r.locked()()
+
+	state := &subscriberGroupState{
+		subscriberGroups: append([]models.SubscriberGroup{}, r.state.subscriberGroups...),
+		permissions:      append([]models.Permission{}, r.state.permissions...),
+		audit:            r.state.audit.clone(),
+	}
+	if err := fn(&SubscriberGroupRepository{state: state}); err != nil {
+		return err
+	}
+
+	*r.state = *state
+	return nil
+}
+
+func (r *SubscriberGroupRepository) List(organizationID string) ([]models.SubscriberGroupMinimal, error) {
+	defer r.locked()()
+
+	var groupList []models.SubscriberGroupMinimal
+	for _, subscriberGroup := range r.state.subscriberGroups {
+		if subscriberGroup.OrganizationID == organizationID && !subscriberGroup.DeletedAt.Valid {
+			groupList = append(groupList, models.SubscriberGroupMinimal{ID: subscriberGroup.ID, Name: subscriberGroup.Name})
+		}
+	}
+
+	return groupList, nil
+}
+
+func (r *SubscriberGroupRepository) Find(subscriberGroupID string, lock bool) (models.SubscriberGroup, error) {
+	defer r.locked()()
+
+	stored := r.stored(subscriberGroupID)
+	if stored == nil || stored.DeletedAt.Valid {
+		return models.SubscriberGroup{}, gorm.ErrRecordNotFound
+	}
+
+	subscriberGroup := *stored
+	subscriberGroup.Permissions = []models.Permission{}
+	for _, permission := range r.state.permissions {
+		if permission.SubscriberGroupID == subscriberGroupID && !permission.DeletedAt.Valid {
+			subscriberGroup.Permissions = append(subscriberGroup.Permissions, permission)
+		}
+	}
+
+	return subscriberGroup, nil
+}
+
+func (r *SubscriberGroupRepository) Create(subscriberGroup *models.SubscriberGroup) error {
+	defer r.locked()()
+
+	if err := r.uniqueCheck(subscriberGroup); err != nil {
+		return err
+	}
+
+	subscriberGroup.ID, subscriberGroup.Model = newModel()
+	for index := range subscriberGroup.Permissions {
+		subscriberGroup.Permissions[index].SubscriberGroupID = subscriberGroup.ID
+	}
+
+	stored := *subscriberGroup
+	stored.Permissions = nil
+	r.state.subscriberGroups = append(r.state.subscriberGroups, stored)
+	r.addPermissions(subscriberGroup.Permissions)
+
+	return nil
+}
+
+func (r *SubscriberGroupRepository) UpdateDetails(subscriberGroup *models.SubscriberGroup) error {
+	defer r.locked()()
+
+	if err := r.uniqueCheck(subscriberGroup); err != nil {
+		return err
+	}
+
+	if stored := r.stored(subscriberGroup.ID); stored != nil {
+		stored.Name = subscriberGroup.Name
+		stored.Description = subscriberGroup.Description
+		stored.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+func (r *SubscriberGroupRepository) Delete(subscriberGroupID string) error {
+	defer r.locked()()
+
+	subscriberGroups := []models.SubscriberGroup{}
+	for _, subscriberGroup := range r.state.subscriberGroups {
+		if subscriberGroup.ID != subscriberGroupID {
+			subscriberGroups = append(subscriberGroups, subscriberGroup)
+		}
+	}
+	r.state.subscriberGroups = subscriberGroups
+
+	permissions := []models.Permission{}
+	for _, permission := range r.state.permissions {
+		if permission.SubscriberGroupID != subscriberGroupID {
+			permissions = append(permissions, permission)
+		}
+	}
+	r.state.permissions = permissions
+
+	return nil
+}
+
+func (r *SubscriberGroupRepository) AddPermissions(permissions []models.Permission) error {
+	defer r.locked()()
+
+	r.addPermissions(permissions)
+	return nil
+}
+
+func (r *SubscriberGroupRepository) UpdatePermissionValue(permissionID string, value string) error {
+	defer r.locked()()
+
+	for index := range r.state.permissions {
+		if r.state.permissions[index].ID == permissionID {
+			r.state.permissions[index].PermissionValue = value
+			r.state.permissions[index].UpdatedAt = time.Now()
+		}
+	}
+
+	return nil
+}
+
+func (r *SubscriberGroupRepository) RemovePermission(permissionID string) error {
+	defer r.locked()()
+
+	permissions := []models.Permission{}
+	for _, permission := range r.state.permissions {
+		if permission.ID != permissionID {
+			permissions = append(permissions, permission)
+		}
+	}
+	r.state.permissions = permissions
+
+	return nil
+}
+
+func (r *SubscriberGroupRepository) stored(subscriberGroupID string) *models.SubscriberGroup {
+	for index := range r.state.subscriberGroups {
+		if r.state.subscriberGroups[index].ID == subscriberGroupID {
+			return &r.state.subscriberGroups[index]
+		}
+	}
+	return nil
+}
+
+func (r *SubscriberGroupRepository) addPermissions(permissions []models.Permission) {
+	for index := range permissions {
+		permissions[index].ID, permissions[index].Model = newModel()
+		r.state.permissions = append(r.state.permissions, permissions[index])
+	}
+}
+
+// uniqueCheck applies the unique constraint of the name of the groups within their organization
+func (r *SubscriberGroupRepository) uniqueCheck(subscriberGroup *models.SubscriberGroup) error {
+	for _, stored := range r.state.subscriberGroups {
+		if stored.ID != subscriberGroup.ID && stored.OrganizationID == subscriberGroup.OrganizationID && stored.Name == subscriberGroup.Name {
+			return fmt.Errorf("%w: subscriber group %s already exists in organization %s", gorm.ErrDuplicatedKey, subscriberGroup.Name, subscriberGroup.OrganizationID)
+		}
+	}
+
+	return nil
+}
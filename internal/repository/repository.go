@@ -0,0 +1,121 @@
+// Package repository defines how the services store and load their entities, so the services do not
+// depend on a specific database. The GORM implementations are in the cockroachdb package and the
+// in-memory implementations, which are used by the tests, are in the memory package.
+// The implementations return gorm.ErrRecordNotFound when the requested entity does not exist
+package repository
+
+import (
+	"ospm/internal/models"
+)
+
+// AuditRepository keeps the entries of the audit log
+type AuditRepository interface {
+	// LastAuditEntry returns the last entry of the audit log and locks it until the end of the transaction.
+	// An empty entry is returned if the audit log is empty
+	LastAuditEntry() (models.AuditEntry, error)
+
+	AppendAuditEntry(entry *models.AuditEntry) error
+}
+
+// OrganizationLookup identifies an organization by its id or its name. If both are given,
+// the organization must match both of them
+type OrganizationLookup struct {
+	ID   string
+	Name string
+
+	// Unscoped includes the soft deleted organizations
+	Unscoped bool
+
+	// Lock locks the organization until the end of the transaction
+	Lock bool
+}
+
+// OrganizationPosition is the position of an organization in the list sorted by a key. Value is the value of
+// the sort key of the organization: the name as string, the creation time as time.Time or the balance amount
+// as models.Decimal
+type OrganizationPosition struct {
+	Value interface{}
+	ID    string
+}
+
+// OrganizationListQuery selects the organizations of a page of the list
+type OrganizationListQuery struct {
+	Filter     models.OrganizationFilter
+	SortKey    string // one of the models.OrganizationSort keys
+	Descending bool
+
+	// After starts the list after the given position, nil starts it from the beginning.
+	// The organizations with the same value of the sort key are ordered by their ids
+	After *OrganizationPosition
+
+	Limit int
+}
+
+// OrganizationRepository keeps the organizations with their details and owners
+type OrganizationRepository interface {
+	AuditRepository
+
+	// Transaction runs fn with a repository whose changes are committed together.
+	// The changes are rolled back if fn returns an error
+	Transaction(fn func(tx OrganizationRepository) error) error
+
+	// List returns the organizations that match the query with their details and owners
+	List(query OrganizationListQuery) ([]models.Organization, error)
+
+	// Count returns the number of the organizations that match the filter
+	Count(filter models.OrganizationFilter) (int64, error)
+
+	// Find returns the organization with its details and owner
+	Find(lookup OrganizationLookup) (models.Organization, error)
+
+	// Create adds the organization with its details and owner and sets their ids
+	Create(organization *models.Organization) error
+
+	// Delete deletes the organization with its details and owner. It is a soft delete unless hard is true
+	Delete(organization *models.Organization, hard bool) error
+
+	// Recover recovers the soft deleted organization with its details and owner
+	Recover(organizationID string) error
+
+	// UpdateProfile saves the details and the owner of the organization
+	UpdateProfile(organization *models.Organization) error
+
+	// UpdateBalancePolicy saves whether the organization is allowed to have negative balance and how much
+	UpdateBalancePolicy(organization *models.Organization) error
+
+	// ProfileValueUsed determines whether the name, email or mobile field of the details, or of the owner if
+	// owner is true, has the given value in any organization but the given one, including the soft deleted ones
+	ProfileValueUsed(owner bool, field string, value string, organizationID string) (bool, error)
+}
+
+// SubscriberGroupRepository keeps the subscriber groups with their permissions
+type SubscriberGroupRepository interface {
+	AuditRepository
+
+	// Transaction runs fn with a repository whose changes are committed together.
+	// The changes are rolled back if fn returns an error
+	Transaction(fn func(tx SubscriberGroupRepository) error) error
+
+	// List returns the id and the name of the groups of the organization
+	List(organizationID string) ([]models.SubscriberGroupMinimal, error)
+
+	// Find returns the group with its permissions. lock locks the group until the end of the transaction
+	Find(subscriberGroupID string, lock bool) (models.SubscriberGroup, error)
+
+	// Create adds the group with its permissions and sets their ids
+	Create(subscriberGroup *models.SubscriberGroup) error
+
+	// UpdateDetails saves the name and the description of the group
+	UpdateDetails(subscriberGroup *models.SubscriberGroup) error
+
+	// Delete deletes the group with its permissions permanently
+	Delete(subscriberGroupID string) error
+
+	// AddPermissions adds the permissions to their groups and sets their ids
+	AddPermissions(permissions []models.Permission) error
+
+	UpdatePermissionValue(permissionID string, value string) error
+
+	// RemovePermission deletes the permission permanently
+	RemovePermission(permissionID string) error
+}
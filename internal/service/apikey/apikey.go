@@ -0,0 +1,374 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"ospm/internal/models"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/logger"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EntityType is the type of the API keys in the audit log
+const EntityType = "api_key"
+
+var (
+	// ErrInvalidAPIKey is returned when the API key is missing, unknown, expired or revoked
+	ErrInvalidAPIKey = errors.New("invalid API key")
+
+	// ErrInsufficientScope is returned when the API key does not have the scope of the request
+	ErrInsufficientScope = errors.New("the API key does not have the required scope")
+
+	// ErrInvalidRequest is returned when the API key request is not valid
+	ErrInvalidRequest = errors.New("invalid API key request")
+
+	// ErrRevoked is returned when a revoked key is rotated or revoked again
+	ErrRevoked = errors.New("the API key is revoked")
+)
+
+// Resources are the API resources that the scopes are defined on. Each one is the first segment of its routes
+var Resources = []string{
+	"organization",
+	"subscriber_group",
+	"subscriber",
+	"usage",
+	"balance",
+	"catalog",
+	"subscription",
+	"permission_catalog",
+	"audit",
+	"api_key",
+}
+
+// Actions are the actions that the scopes allow on the resources.
+// hard_delete is required for the delete requests in hard mode
+var Actions = []string{"read", "write", "delete", "hard_delete"}
+
+// keyScheme is the first part of the keys so they can be recognized in the configs and logs
+const keyScheme = "ospm"
+
+// lastUsedInterval limits how often the last usage time of a key is written to the database
+const lastUsedInterval = time.Minute
+
+// Authenticate looks up the key by its prefix and compares its hash in constant time.
+// Expired and revoked keys are rejected
+func Authenticate(key string) (models.APIKey, error) {
+	var apiKey models.APIKey
+
+	prefix, valid := parse(key)
+	if !valid {
+		return models.APIKey{}, ErrInvalidAPIKey
+	}
+
+	err := cockroachdb.DB.First(&apiKey, "prefix = ?", prefix).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.APIKey{}, ErrInvalidAPIKey
+	} else if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the API key with prefix %s, error: %+v", prefix, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.APIKey{}, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash(key)), []byte(apiKey.KeyHash)) != 1 {
+		return models.APIKey{}, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return models.APIKey{}, fmt.Errorf("%w: the key is revoked or expired", ErrInvalidAPIKey)
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedInterval {
+		if err := cockroachdb.DB.Model(&apiKey).Update("last_used_at", now).Error; err != nil {
+			logger.OSPMLogger.Warnf("failed to update the last usage time of API key id %s, error: %+v", apiKey.ID, err)
+		}
+	}
+
+	return apiKey, nil
+}
+
+// Create generates a new key with the given scopes and returns it with its secret.
+// The secret is not stored and can not be retrieved again
+func Create(request models.APIKeyRequest, actor audit.Actor) (models.APIKeySecretResponse, error) {
+	if strings.TrimSpace(request.Name) == "" {
+		return models.APIKeySecretResponse{}, fmt.Errorf("%w: the name of the key must be given", ErrInvalidRequest)
+	}
+	if len(request.Scopes) == 0 {
+		return models.APIKeySecretResponse{}, fmt.Errorf("%w: at least one scope must be given", ErrInvalidRequest)
+	}
+	for _, scope := range request.Scopes {
+		if !IsValidScope(scope) {
+			return models.APIKeySecretResponse{}, fmt.Errorf("%w: unknown scope %q", ErrInvalidRequest, scope)
+		}
+	}
+	if request.ExpiresAt != nil && request.ExpiresAt.Before(time.Now()) {
+		return models.APIKeySecretResponse{}, fmt.Errorf("%w: the expiry time is in the past", ErrInvalidRequest)
+	}
+
+	prefix, key, err := generate()
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to generate a new API key, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.APIKeySecretResponse{}, err
+	}
+
+	apiKey := models.APIKey{
+		Name:      request.Name,
+		Prefix:    prefix,
+		KeyHash:   hash(key),
+		Scopes:    request.Scopes,
+		ExpiresAt: request.ExpiresAt,
+	}
+	err = cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&apiKey).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, actor, audit.ActionCreate, EntityType, apiKey.ID, nil, Clean(&apiKey))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to add the new API key %s, error: %+v", request.Name, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.APIKeySecretResponse{}, err
+	}
+
+	logger.OSPMLogger.Infof("API key %s successfully added. id: %s, prefix: %s, scopes: %v", apiKey.Name, apiKey.ID, apiKey.Prefix, apiKey.Scopes)
+
+	return models.APIKeySecretResponse{APIKeyResponse: Clean(&apiKey), Key: key}, nil
+}
+
+// List returns all of the keys including the revoked and expired ones, the latest first
+func List() ([]models.APIKeyResponse, error) {
+	var apiKeys []models.APIKey
+
+	if err := cockroachdb.DB.Order("created_at desc").Find(&apiKeys).Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to load the list of API keys, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, err
+	}
+
+	response := []models.APIKeyResponse{}
+	for index := range apiKeys {
+		response = append(response, Clean(&apiKeys[index]))
+	}
+
+	return response, nil
+}
+
+// Rotate replaces the secret of the key and keeps its scopes and expiry time.
+// The old secret stops working immediately
+func Rotate(apiKeyID string, actor audit.Actor) (models.APIKeySecretResponse, error) {
+	var apiKey models.APIKey
+	var key string
+
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&apiKey, "id = ?", apiKeyID).Error
+		if err != nil {
+			return err
+		}
+		if apiKey.RevokedAt != nil {
+			return ErrRevoked
+		}
+		before := Clean(&apiKey)
+
+		var prefix string
+		if prefix, key, err = generate(); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		apiKey.Prefix = prefix
+		apiKey.KeyHash = hash(key)
+		apiKey.RotatedAt = &now
+
+		err = tx.Model(&apiKey).Updates(map[string]interface{}{
+			"prefix":     apiKey.Prefix,
+			"key_hash":   apiKey.KeyHash,
+			"rotated_at": apiKey.RotatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(tx, actor, audit.ActionUpdate, EntityType, apiKey.ID, before, Clean(&apiKey))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to rotate the API key id %s, error: %+v", apiKeyID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.APIKeySecretResponse{}, err
+	}
+
+	logger.OSPMLogger.Infof("API key id %s successfully rotated. new prefix: %s", apiKey.ID, apiKey.Prefix)
+
+	return models.APIKeySecretResponse{APIKeyResponse: Clean(&apiKey), Key: key}, nil
+}
+
+// Revoke disables the key permanently. The key is kept so it is still listed
+func Revoke(apiKeyID string, actor audit.Actor) error {
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		var apiKey models.APIKey
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&apiKey, "id = ?", apiKeyID).Error
+		if err != nil {
+			return err
+		}
+		if apiKey.RevokedAt != nil {
+			return ErrRevoked
+		}
+
+		before := Clean(&apiKey)
+
+		now := time.Now()
+		apiKey.RevokedAt = &now
+		if err := tx.Model(&apiKey).Update("revoked_at", apiKey.RevokedAt).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, actor, audit.ActionDelete, EntityType, apiKey.ID, before, Clean(&apiKey))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to revoke the API key id %s, error: %+v", apiKeyID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	logger.OSPMLogger.Infof("API key id %s successfully revoked", apiKeyID)
+
+	return nil
+}
+
+// Bootstrap creates a key with all of the scopes if there is no active key, so the API
+// is not locked out on the first startup. The key is printed once and is not logged
+func Bootstrap() error {
+	var activeKeys int64
+
+	err := cockroachdb.DB.Model(&models.APIKey{}).
+		Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", time.Now()).
+		Count(&activeKeys).Error
+	if err != nil {
+		return err
+	}
+	if activeKeys > 0 {
+		return nil
+	}
+
+	// the bootstrap key is created by OSPM itself, so there is no actor
+	created, err := Create(models.APIKeyRequest{Name: "bootstrap", Scopes: []string{"*"}}, audit.Actor{})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("there is no active API key, a bootstrap key with all of the scopes is created. "+
+		"store it safely, it will not be shown again. id: %s, key: %s", created.ID, created.Key)
+	return nil
+}
+
+// RequiredScope returns the scope that the request needs. The resource is the first segment of
+// the path and the action is read for GET and HEAD, delete (or hard_delete in hard mode) for DELETE
+// and write for the other methods
+func RequiredScope(method string, path string, deleteMode string) string {
+	resource := strings.SplitN(strings.Trim(path, "/"), "/", 2)[0]
+
+	action := "write"
+	switch method {
+	case "GET", "HEAD":
+		action = "read"
+	case "DELETE":
+		action = "delete"
+		if deleteMode == "hard" {
+			action = "hard_delete"
+		}
+	}
+
+	return resource + ":" + action
+}
+
+// HasScope checks the required scope against the scopes of a key
+func HasScope(scopes []string, required string) bool {
+	resource, _, _ := strings.Cut(required, ":")
+	for _, scope := range scopes {
+		if scope == "*" || scope == required || scope == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidScope checks that the scope is *, resource:* or resource:action of the known ones
+func IsValidScope(scope string) bool {
+	if scope == "*" {
+		return true
+	}
+
+	resource, action, found := strings.Cut(scope, ":")
+	if !found || !contains(Resources, resource) {
+		return false
+	}
+
+	return action == "*" || contains(Actions, action)
+}
+
+// Clean removes the hash of the key
+func Clean(apiKey *models.APIKey) models.APIKeyResponse {
+	return models.APIKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		CreatedAt:  apiKey.CreatedAt,
+		ExpiresAt:  apiKey.ExpiresAt,
+		RevokedAt:  apiKey.RevokedAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RotatedAt:  apiKey.RotatedAt,
+	}
+}
+
+// generate returns a new key in ospm_<prefix>_<secret> format. The secret has 256 bits of
+// randomness, so a fast hash is enough to store it and no salt or key stretching is needed
+func generate() (string, string, error) {
+	prefixBytes := make([]byte, 4)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+
+	prefix := hex.EncodeToString(prefixBytes)
+	key := fmt.Sprintf("%s_%s_%s", keyScheme, prefix, base64.RawURLEncoding.EncodeToString(secretBytes))
+
+	return prefix, key, nil
+}
+
+// parse returns the prefix of the key if the key is in the expected format
+func parse(key string) (string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != keyScheme || len(parts[1]) != 8 || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
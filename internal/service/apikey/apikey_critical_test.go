@@ -0,0 +1,152 @@
+package apikey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiredScope(t *testing.T) {
+	type testCase struct {
+		name          string
+		method        string
+		path          string
+		deleteMode    string
+		expectedScope string
+	}
+
+	testCases := []testCase{
+		{
+			name:          "the organization list is requested. In this case, organization:read should be required",
+			method:        "GET",
+			path:          "/organization",
+			expectedScope: "organization:read",
+		},
+		{
+			name:          "a subscriber group is updated. In this case, subscriber_group:write should be required",
+			method:        "PATCH",
+			path:          "/subscriber_group/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a",
+			expectedScope: "subscriber_group:write",
+		},
+		{
+			name:          "an organization is deleted in soft mode. In this case, organization:delete should be required",
+			method:        "DELETE",
+			path:          "/organization",
+			deleteMode:    "soft",
+			expectedScope: "organization:delete",
+		},
+		{
+			name:          "an organization is deleted in hard mode. In this case, organization:hard_delete should be required",
+			method:        "DELETE",
+			path:          "/organization",
+			deleteMode:    "hard",
+			expectedScope: "organization:hard_delete",
+		},
+		{
+			name:          "an API key is rotated. In this case, api_key:write should be required",
+			method:        "POST",
+			path:          "/api_key/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a/rotate",
+			expectedScope: "api_key:write",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedScope, RequiredScope(tc.method, tc.path, tc.deleteMode))
+		})
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	type testCase struct {
+		name     string
+		scopes   []string
+		required string
+		expected bool
+	}
+
+	testCases := []testCase{
+		{
+			name:     "the key has the exact scope. In this case, it should be accepted",
+			scopes:   []string{"organization:read", "subscriber_group:write"},
+			required: "subscriber_group:write",
+			expected: true,
+		},
+		{
+			name:     "the key has all of the actions of the resource. In this case, it should be accepted",
+			scopes:   []string{"organization:*"},
+			required: "organization:hard_delete",
+			expected: true,
+		},
+		{
+			name:     "the key has all of the scopes. In this case, it should be accepted",
+			scopes:   []string{"*"},
+			required: "api_key:write",
+			expected: true,
+		},
+		{
+			name:     "the key only has the delete scope and hard delete is required. In this case, it should be rejected",
+			scopes:   []string{"organization:delete"},
+			required: "organization:hard_delete",
+			expected: false,
+		},
+		{
+			name:     "the key has the scope of another resource. In this case, it should be rejected",
+			scopes:   []string{"subscriber:*"},
+			required: "subscriber_group:read",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, HasScope(tc.scopes, tc.required))
+		})
+	}
+}
+
+func TestIsValidScope(t *testing.T) {
+	type testCase struct {
+		name     string
+		scope    string
+		expected bool
+	}
+
+	testCases := []testCase{
+		{name: "a known resource and action is given. In this case, it should be valid", scope: "organization:hard_delete", expected: true},
+		{name: "all of the actions of a resource are given. In this case, it should be valid", scope: "catalog:*", expected: true},
+		{name: "all of the scopes are given. In this case, it should be valid", scope: "*", expected: true},
+		{name: "an unknown resource is given. In this case, it should be invalid", scope: "organizations:read", expected: false},
+		{name: "an unknown action is given. In this case, it should be invalid", scope: "organization:admin", expected: false},
+		{name: "a resource without an action is given. In this case, it should be invalid", scope: "organization", expected: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, IsValidScope(tc.scope))
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	prefix, key, err := generate()
+	assert.NoError(t, err)
+
+	parsedPrefix, valid := parse(key)
+	assert.True(t, valid)
+	assert.Equal(t, prefix, parsedPrefix)
+
+	_, otherKey, err := generate()
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+	assert.NotEqual(t, hash(key), hash(otherKey))
+
+	_, valid = parse("Bearer " + key)
+	assert.False(t, valid)
+}
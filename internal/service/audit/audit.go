@@ -0,0 +1,252 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/logger"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ActionCreate     = "create"
+	ActionUpdate     = "update"
+	ActionDelete     = "delete"
+	ActionHardDelete = "hard_delete"
+	ActionRecover    = "recover"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+
+	// verifyBatchSize is the number of entries that are loaded at once while verifying the chain
+	verifyBatchSize = 1000
+)
+
+// ErrInvalidFilter is returned when the filters of the audit log query are not valid
+var ErrInvalidFilter = errors.New("invalid audit log filter")
+
+// Actor is who made the change. APIKeyID is empty when the API keys are not required
+type Actor struct {
+	APIKeyID  string
+	ClientIP  string
+	RequestID string
+}
+
+// Record adds an entry to the end of the audit log. It should be called with the transaction of the change
+// so the change and its entry are committed together. See Append for the details
+func Record(tx *gorm.DB, actor Actor, action string, entityType string, entityID string, before interface{}, after interface{}) error {
+	return Append(cockroachdb.NewAuditRepository(tx), actor, action, entityType, entityID, before, after)
+}
+
+// Append adds an entry to the end of the audit log of the repository. It should be called with the repository
+// of the transaction of the change so the change and its entry are committed together. The last entry is locked
+// until the end of the transaction so the concurrent changes are chained one after another. before and after are
+// the snapshots of the entity and nil means there is no entity, e.g. before a create or after a delete
+func Append(auditRepository repository.AuditRepository, actor Actor, action string, entityType string, entityID string, before interface{}, after interface{}) error {
+	var err error
+	entry := models.AuditEntry{
+		CreatedAt:     time.Now().UTC().Truncate(time.Microsecond),
+		ActorAPIKeyID: actor.APIKeyID,
+		ActorIP:       actor.ClientIP,
+		RequestID:     actor.RequestID,
+		Action:        action,
+		EntityType:    entityType,
+		EntityID:      entityID,
+	}
+
+	if entry.Before, err = snapshot(before); err != nil {
+		return fmt.Errorf("failed to take the snapshot of %s %s before %s, error: %w", entityType, entityID, action, err)
+	}
+	if entry.After, err = snapshot(after); err != nil {
+		return fmt.Errorf("failed to take the snapshot of %s %s after %s, error: %w", entityType, entityID, action, err)
+	}
+
+	last, err := auditRepository.LastAuditEntry()
+	if err != nil {
+		return fmt.Errorf("failed to load the last audit entry, error: %w", err)
+	}
+
+	entry.Sequence = last.Sequence + 1
+	entry.PreviousHash = last.Hash
+	if entry.Hash, err = Hash(entry); err != nil {
+		return err
+	}
+
+	if err := auditRepository.AppendAuditEntry(&entry); err != nil {
+		return fmt.Errorf("failed to record the audit entry of %s %s, error: %w", entityType, entityID, err)
+	}
+
+	return nil
+}
+
+// Query returns the entries that match the filter, the newest first
+func Query(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	entries := []models.AuditEntry{}
+
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, fmt.Errorf("%w: the end of the time range is before its start", ErrInvalidFilter)
+	}
+	if filter.Limit < 0 || filter.Limit > maxLimit {
+		return nil, fmt.Errorf("%w: limit should be between 1 and %d", ErrInvalidFilter, maxLimit)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultLimit
+	}
+
+	query := cockroachdb.DB.Model(&models.AuditEntry{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor_api_key_id = ? OR actor_ip = ?", filter.Actor, filter.Actor)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	if err := query.Order("sequence desc").Limit(filter.Limit).Find(&entries).Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to load the audit log, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, errors.New(errorMessage)
+	}
+
+	return entries, nil
+}
+
+// Verify walks the whole audit log and checks the hash of each entry and its link to the previous one
+func Verify() (models.AuditVerification, error) {
+	verification := models.AuditVerification{Valid: true}
+	previous := models.AuditEntry{}
+
+	for {
+		entries := []models.AuditEntry{}
+		err := cockroachdb.DB.Where("sequence > ?", previous.Sequence).Order("sequence").Limit(verifyBatchSize).Find(&entries).Error
+		if err != nil {
+			errorMessage := fmt.Sprintf("failed to load the audit log, error: %+v", err)
+			logger.OSPMLogger.Errorln(errorMessage)
+			return models.AuditVerification{}, errors.New(errorMessage)
+		}
+
+		for _, entry := range entries {
+			if reason := VerifyLink(previous, entry); reason != "" {
+				sequence := entry.Sequence
+				verification.Valid = false
+				verification.BrokenSequence = &sequence
+				verification.Reason = reason
+				logger.OSPMLogger.Warnf("the audit log chain is broken at sequence %d, %s", sequence, reason)
+				return verification, nil
+			}
+			verification.CheckedEntries++
+			previous = entry
+		}
+
+		if len(entries) < verifyBatchSize {
+			return verification, nil
+		}
+	}
+}
+
+// VerifyLink checks the entry against the entry before it and returns why they do not match.
+// An empty previous entry is the start of the chain
+func VerifyLink(previous models.AuditEntry, entry models.AuditEntry) string {
+	if entry.Sequence != previous.Sequence+1 {
+		return fmt.Sprintf("the entry with sequence %d is missing", previous.Sequence+1)
+	}
+	if entry.PreviousHash != previous.Hash {
+		return "the previous hash of the entry does not match the hash of the previous entry"
+	}
+
+	hash, err := Hash(entry)
+	if err != nil || hash != entry.Hash {
+		return "the hash of the entry does not match its content"
+	}
+
+	return ""
+}
+
+// Hash returns the SHA-256 of the fields of the entry and the hash of the previous entry.
+// The snapshots are hashed in their canonical form since the database may reformat them
+func Hash(entry models.AuditEntry) (string, error) {
+	before, err := canonical(entry.Before)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the audit entry, error: %w", err)
+	}
+	after, err := canonical(entry.After)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the audit entry, error: %w", err)
+	}
+
+	fields := []string{
+		strconv.FormatInt(entry.Sequence, 10),
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		entry.ActorAPIKeyID,
+		entry.ActorIP,
+		entry.RequestID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		before,
+		after,
+		entry.PreviousHash,
+	}
+
+	content, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the audit entry, error: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func snapshot(value interface{}) (models.JSONDocument, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	document, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if string(document) == "null" {
+		return nil, nil
+	}
+
+	return models.JSONDocument(document), nil
+}
+
+// canonical decodes and encodes the document again so the objects have sorted keys
+// and the same spacing no matter how the document is stored
+func canonical(document models.JSONDocument) (string, error) {
+	if len(document) == 0 {
+		return "", nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+
+	canonicalDocument, err := json.Marshal(value)
+	return string(canonicalDocument), err
+}
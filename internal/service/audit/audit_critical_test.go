@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"ospm/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// chain links the entries like Record does
+func chain(t *testing.T, entries []models.AuditEntry) []models.AuditEntry {
+	previous := models.AuditEntry{}
+	for index := range entries {
+		entries[index].Sequence = previous.Sequence + 1
+		entries[index].PreviousHash = previous.Hash
+
+		hash, err := Hash(entries[index])
+		assert.NoError(t, err)
+		entries[index].Hash = hash
+		previous = entries[index]
+	}
+	return entries
+}
+
+func TestVerifyLink(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 30, 0, 123456000, time.UTC)
+
+	newEntries := func() []models.AuditEntry {
+		return chain(t, []models.AuditEntry{
+			{CreatedAt: createdAt, ActorAPIKeyID: "key-1", ActorIP: "10.0.0.1", RequestID: "request-1", Action: ActionCreate,
+				EntityType: "organization", EntityID: "org-1", After: models.JSONDocument(`{"name":"owl","balance":{"amount":100}}`)},
+			{CreatedAt: createdAt.Add(time.Minute), ActorAPIKeyID: "key-1", ActorIP: "10.0.0.1", RequestID: "request-2", Action: ActionUpdate,
+				EntityType: "organization", EntityID: "org-1",
+				Before: models.JSONDocument(`{"name":"owl","balance":{"amount":100}}`), After: models.JSONDocument(`{"name":"owl-mns","balance":{"amount":100}}`)},
+			{CreatedAt: createdAt.Add(2 * time.Minute), ActorIP: "10.0.0.2", RequestID: "request-3", Action: ActionHardDelete,
+				EntityType: "organization", EntityID: "org-1", Before: models.JSONDocument(`{"name":"owl-mns","balance":{"amount":100}}`)},
+		})
+	}
+
+	type testCase struct {
+		name          string
+		tamper        func(entries []models.AuditEntry) []models.AuditEntry
+		expectedValid bool
+	}
+
+	testCases := []testCase{
+		{
+			name:          "the entries are not changed. In this case, the chain should be valid",
+			tamper:        func(entries []models.AuditEntry) []models.AuditEntry { return entries },
+			expectedValid: true,
+		},
+		{
+			name: "the snapshots are reformatted by the database. In this case, the chain should still be valid",
+			tamper: func(entries []models.AuditEntry) []models.AuditEntry {
+				entries[1].Before = models.JSONDocument(`{"balance": {"amount": 100}, "name": "owl"}`)
+				entries[1].CreatedAt = entries[1].CreatedAt.In(time.FixedZone("IRST", 12600))
+				return entries
+			},
+			expectedValid: true,
+		},
+		{
+			name: "a snapshot is changed. In this case, the chain should be broken",
+			tamper: func(entries []models.AuditEntry) []models.AuditEntry {
+				entries[1].After = models.JSONDocument(`{"name":"owl-mns","balance":{"amount":1000000}}`)
+				return entries
+			},
+		},
+		{
+			name: "the actor is changed and the entry is hashed again. In this case, the link of the next entry should be broken",
+			tamper: func(entries []models.AuditEntry) []models.AuditEntry {
+				entries[1].ActorAPIKeyID = "key-2"
+				entries[1].Hash, _ = Hash(entries[1])
+				return entries
+			},
+		},
+		{
+			name: "an entry is removed. In this case, the chain should be broken",
+			tamper: func(entries []models.AuditEntry) []models.AuditEntry {
+				return append(entries[:1], entries[2:]...)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			entries := tc.tamper(newEntries())
+
+			valid := true
+			previous := models.AuditEntry{}
+			for _, entry := range entries {
+				if reason := VerifyLink(previous, entry); reason != "" {
+					valid = false
+					break
+				}
+				previous = entry
+			}
+			assert.Equal(t, tc.expectedValid, valid)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package authentication
+
+import (
+	"errors"
+	"fmt"
+	"ospm/config"
+	"ospm/internal/models"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/subscriber"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSessionRevoked is returned when the session of the given token is revoked or expired
+var ErrSessionRevoked = errors.New("the session is revoked or expired")
+
+// Login verifies the given credentials and starts a new session for the subscriber.
+// The returned access token is short lived and the refresh token can be used to get a new
+// pair of tokens as long as the session is not revoked or expired
+func Login(username string, plainPassword string, clientIP string, userAgent string) (models.TokenPair, error) {
+	subscriberDetails, err := subscriber.VerifyCredentials(username, plainPassword)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	// subscribers of a soft deleted organization are not allowed to login
+	var organization models.Organization
+	if err := cockroachdb.DB.Select("id").First(&organization, "id = ?", subscriberDetails.OrganizationID).Error; err != nil {
+		logger.OSPMLogger.Warnf("login of username %s is rejected since its organization is not available, error: %+v", username, err)
+		return models.TokenPair{}, subscriber.ErrInvalidCredentials
+	}
+
+	session := models.SubscriberSession{
+		SubscriberID:      subscriberDetails.ID,
+		OrganizationID:    subscriberDetails.OrganizationID,
+		SubscriberGroupID: subscriberDetails.SubscriberGroupID,
+		RefreshTokenID:    uuid.NewString(),
+		ExpiresAt:         time.Now().Add(config.Current().Auth.RefreshTokenTTL),
+		ClientIP:          clientIP,
+		UserAgent:         userAgent,
+	}
+
+	if err := cockroachdb.DB.Create(&session).Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to create a new session for username %s, error: %+v", username, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.TokenPair{}, errors.New(errorMessage)
+	}
+
+	logger.OSPMLogger.Infof("subscriber id %s successfully logged in. session id: %s", session.SubscriberID, session.ID)
+
+	return issueTokenPair(session)
+}
+
+// Refresh rotates the refresh token of the session and returns a new pair of tokens.
+// Each refresh token can be used only once. Presenting an already used refresh token
+// revokes the whole session since the token has probably been stolen
+func Refresh(refreshToken string) (models.TokenPair, error) {
+	authSettings := config.Current().Auth
+	claims, err := ParseToken(refreshToken, []byte(authSettings.TokenSigningKey), authSettings.TokenIssuer, RefreshTokenType)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	var session models.SubscriberSession
+	var reuseDetected bool
+
+	err = cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, "id = ?", claims.SessionID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		} else if err != nil {
+			return err
+		}
+
+		if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+			return ErrSessionRevoked
+		}
+
+		if session.RefreshTokenID != claims.ID {
+			logger.OSPMLogger.Warnf("reuse of a rotated refresh token is detected, session id %s is revoked", session.ID)
+			now := time.Now()
+			reuseDetected = true
+			// returning nil commits the revocation
+			return tx.Model(&session).Update("revoked_at", &now).Error
+		}
+
+		// the group of the subscriber might be changed since the last refresh
+		var subscriberDetails models.Subscriber
+		err = tx.Select("id", "organization_id", "subscriber_group_id").First(&subscriberDetails, "id = ?", session.SubscriberID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		} else if err != nil {
+			return err
+		}
+
+		session.SubscriberGroupID = subscriberDetails.SubscriberGroupID
+		session.RefreshTokenID = uuid.NewString()
+
+		return tx.Model(&session).Updates(map[string]interface{}{
+			"refresh_token_id":    session.RefreshTokenID,
+			"subscriber_group_id": session.SubscriberGroupID,
+		}).Error
+	})
+	if errors.Is(err, ErrSessionRevoked) {
+		return models.TokenPair{}, err
+	} else if err != nil {
+		errorMessage := fmt.Sprintf("failed to refresh the tokens of session id %s, error: %+v", claims.SessionID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.TokenPair{}, errors.New(errorMessage)
+	}
+
+	if reuseDetected {
+		return models.TokenPair{}, ErrSessionRevoked
+	}
+
+	return issueTokenPair(session)
+}
+
+// Revoke revokes the session of the given token. Both access and refresh tokens are accepted
+func Revoke(token string) error {
+	authSettings := config.Current().Auth
+	claims, err := ParseToken(token, []byte(authSettings.TokenSigningKey), authSettings.TokenIssuer, "")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	result := cockroachdb.DB.Model(&models.SubscriberSession{}).
+		Where("id = ? AND revoked_at IS NULL", claims.SessionID).
+		Update("revoked_at", &now)
+	if result.Error != nil {
+		errorMessage := fmt.Sprintf("failed to revoke session id %s, error: %+v", claims.SessionID, result.Error)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return errors.New(errorMessage)
+	}
+
+	logger.OSPMLogger.Infof("session id %s of subscriber id %s is revoked", claims.SessionID, claims.SubscriberID)
+
+	return nil
+}
+
+// ValidateAccessToken verifies the given access token and makes sure its session is still active
+func ValidateAccessToken(accessToken string) (*Claims, error) {
+	authSettings := config.Current().Auth
+	claims, err := ParseToken(accessToken, []byte(authSettings.TokenSigningKey), authSettings.TokenIssuer, AccessTokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	var session models.SubscriberSession
+	err = cockroachdb.DB.Select("id", "revoked_at", "expires_at").First(&session, "id = ?", claims.SessionID).Error
+	if err != nil || session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, ErrSessionRevoked
+	}
+
+	return claims, nil
+}
+
+// issueTokenPair issues a new access token and a refresh token for the given session.
+// The refresh token expires at the same time as the session
+func issueTokenPair(session models.SubscriberSession) (models.TokenPair, error) {
+	authSettings := config.Current().Auth
+	signingKey := []byte(authSettings.TokenSigningKey)
+	issuer := authSettings.TokenIssuer
+
+	claims := Claims{
+		SubscriberID:      session.SubscriberID,
+		OrganizationID:    session.OrganizationID,
+		SubscriberGroupID: session.SubscriberGroupID,
+		SessionID:         session.ID,
+	}
+
+	accessTokenClaims := claims
+	accessTokenClaims.TokenType = AccessTokenType
+	accessTokenExpiresAt := time.Now().Add(authSettings.AccessTokenTTL)
+	if accessTokenExpiresAt.After(session.ExpiresAt) {
+		accessTokenExpiresAt = session.ExpiresAt
+	}
+
+	accessToken, err := IssueToken(accessTokenClaims, signingKey, issuer, accessTokenExpiresAt)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	refreshTokenClaims := claims
+	refreshTokenClaims.TokenType = RefreshTokenType
+	refreshTokenClaims.ID = session.RefreshTokenID
+
+	refreshToken, err := IssueToken(refreshTokenClaims, signingKey, issuer, session.ExpiresAt)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	return models.TokenPair{
+		TokenType:             "Bearer",
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
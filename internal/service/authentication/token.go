@@ -0,0 +1,80 @@
+package authentication
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AccessTokenType  = "access"
+	RefreshTokenType = "refresh"
+)
+
+// ErrInvalidToken is returned when the given token is malformed, expired,
+// signed by another key or is not of the expected type
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims are embedded in both access and refresh tokens. The token type
+// prevents a refresh token from being used as an access token and vice versa
+type Claims struct {
+	SubscriberID      string `json:"subscriber_id"`
+	OrganizationID    string `json:"organization_id"`
+	SubscriberGroupID string `json:"subscriber_group_id"`
+	SessionID         string `json:"session_id"`
+	TokenType         string `json:"token_type"`
+	jwt.RegisteredClaims
+}
+
+// IssueToken signs the given claims by HMAC-SHA256 and returns the signed token.
+// If the claims do not have an ID, a new one is generated
+func IssueToken(claims Claims, signingKey []byte, issuer string, expiresAt time.Time) (string, error) {
+	now := time.Now()
+
+	if claims.ID == "" {
+		claims.ID = uuid.NewString()
+	}
+	claims.Issuer = issuer
+	claims.Subject = claims.SubscriberID
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.NotBefore = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+
+	signedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(signingKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign the token, error: %w", err)
+	}
+
+	return signedToken, nil
+}
+
+// ParseToken verifies the signature, the issuer and the expiry of the given token and makes sure
+// the token is of the expected type. An empty expected type accepts both access and refresh tokens
+func ParseToken(signedToken string, signingKey []byte, issuer string, expectedType string) (*Claims, error) {
+	claims := &Claims{}
+
+	_, err := jwt.ParseWithClaims(signedToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			return signingKey, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	if expectedType != "" && claims.TokenType != expectedType {
+		return nil, fmt.Errorf("%w: expected %s token but %s token is given", ErrInvalidToken, expectedType, claims.TokenType)
+	}
+
+	if claims.SessionID == "" || claims.SubscriberID == "" {
+		return nil, fmt.Errorf("%w: required claims are missing", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,94 @@
+package authentication
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseToken(t *testing.T) {
+	signingKey := []byte("test-signing-key")
+	claims := Claims{
+		SubscriberID:      "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a",
+		OrganizationID:    "ed83a2ba-c55c-4297-b2ac-df7b02abdd7b",
+		SubscriberGroupID: "ed83a2ba-c55c-4297-b2ac-df7b02abdd7c",
+		SessionID:         "ed83a2ba-c55c-4297-b2ac-df7b02abdd7d",
+		TokenType:         AccessTokenType,
+	}
+
+	validToken, _ := IssueToken(claims, signingKey, "ospm", time.Now().Add(time.Minute))
+	expiredToken, _ := IssueToken(claims, signingKey, "ospm", time.Now().Add(-time.Minute))
+	otherKeyToken, _ := IssueToken(claims, []byte("another-key"), "ospm", time.Now().Add(time.Minute))
+	otherIssuerToken, _ := IssueToken(claims, signingKey, "another-issuer", time.Now().Add(time.Minute))
+
+	type testCase struct {
+		name          string
+		token         string
+		expectedType  string
+		expectedError error
+	}
+
+	testCases := []testCase{
+		{
+			name:          "a valid access token is given. In this case, the claims should be returned",
+			token:         validToken,
+			expectedType:  AccessTokenType,
+			expectedError: nil,
+		},
+		{
+			name:          "a valid access token is given while any type is accepted. In this case, the claims should be returned",
+			token:         validToken,
+			expectedType:  "",
+			expectedError: nil,
+		},
+		{
+			name:          "an access token is given as a refresh token. In this case, the token should be rejected",
+			token:         validToken,
+			expectedType:  RefreshTokenType,
+			expectedError: ErrInvalidToken,
+		},
+		{
+			name:          "an expired token is given. In this case, the token should be rejected",
+			token:         expiredToken,
+			expectedType:  AccessTokenType,
+			expectedError: ErrInvalidToken,
+		},
+		{
+			name:          "a token signed by another key is given. In this case, the token should be rejected",
+			token:         otherKeyToken,
+			expectedType:  AccessTokenType,
+			expectedError: ErrInvalidToken,
+		},
+		{
+			name:          "a token issued by another issuer is given. In this case, the token should be rejected",
+			token:         otherIssuerToken,
+			expectedType:  AccessTokenType,
+			expectedError: ErrInvalidToken,
+		},
+		{
+			name:          "a malformed token is given. In this case, the token should be rejected",
+			token:         "not-a-token",
+			expectedType:  AccessTokenType,
+			expectedError: ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			parsedClaims, err := ParseToken(tc.token, signingKey, "ospm", tc.expectedType)
+			if tc.expectedError == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, claims.SubscriberID, parsedClaims.SubscriberID)
+				assert.Equal(t, claims.OrganizationID, parsedClaims.OrganizationID)
+				assert.Equal(t, claims.SubscriberGroupID, parsedClaims.SubscriberGroupID)
+				assert.Equal(t, claims.SessionID, parsedClaims.SessionID)
+			} else {
+				assert.True(t, errors.Is(err, tc.expectedError))
+			}
+		})
+	}
+}
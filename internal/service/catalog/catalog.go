@@ -0,0 +1,130 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidRequest is returned when the given catalog entity is not valid
+	ErrInvalidRequest = errors.New("invalid catalog request")
+
+	// ErrInvalidReference is returned when the referenced specification does not exist
+	ErrInvalidReference = errors.New("invalid product specification reference")
+
+	// ErrInvalidLifecycleTransition is returned when the lifecycle status can not be changed to the given one
+	ErrInvalidLifecycleTransition = errors.New("invalid lifecycle status transition")
+
+	// ErrSpecificationInUse is returned when a specification can not be retired or deleted
+	// because of the offerings that use it
+	ErrSpecificationInUse = errors.New("product specification is in use")
+)
+
+// The default and the maximum number of items returned by the listing endpoints
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// lifecycleTransitions contains the statuses that each status can be changed to
+var lifecycleTransitions = map[string][]string{
+	models.LifecycleStatusDraft:   {models.LifecycleStatusActive, models.LifecycleStatusRetired},
+	models.LifecycleStatusActive:  {models.LifecycleStatusRetired},
+	models.LifecycleStatusRetired: {},
+}
+
+// CanTransition determines whether the lifecycle status can be changed from the current status to the next one.
+// Keeping the same status is always allowed
+func CanTransition(current string, next string) bool {
+	if current == next {
+		_, valid := lifecycleTransitions[next]
+		return valid
+	}
+
+	for _, allowed := range lifecycleTransitions[current] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateValidity checks that the end of the validity period is after its start
+func ValidateValidity(validFrom time.Time, validTo *time.Time) error {
+	if validTo != nil && !validTo.After(validFrom) {
+		return fmt.Errorf("%w: the end of the validity period (%s) must be after its start (%s)",
+			ErrInvalidRequest, validTo.Format(time.RFC3339), validFrom.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// IsValidAt determines whether the validity period contains the given time
+func IsValidAt(validFrom time.Time, validTo *time.Time, at time.Time) bool {
+	return !at.Before(validFrom) && (validTo == nil || at.Before(*validTo))
+}
+
+// applyFilter applies the common filters of the catalog entities to the query
+func applyFilter(query *gorm.DB, filter models.CatalogFilter) *gorm.DB {
+	if filter.Name != "" {
+		query = query.Where("name = ?", filter.Name)
+	}
+	if filter.LifecycleStatus != "" {
+		query = query.Where("lifecycle_status = ?", filter.LifecycleStatus)
+	}
+	if filter.ValidAt != nil {
+		query = query.Where("valid_from <= ? AND (valid_to IS NULL OR valid_to > ?)", *filter.ValidAt, *filter.ValidAt)
+	}
+
+	return query
+}
+
+// paginate applies the offset and the limit of the filter to the query
+func paginate(query *gorm.DB, filter models.CatalogFilter) *gorm.DB {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	} else if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	return query.Order("created_at").Offset(offset).Limit(limit)
+}
+
+// nullIfEmpty returns NULL for the empty values of the optional columns. The optional unique
+// columns must be NULL so the entities without a value do not conflict with each other
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return gorm.Expr("NULL")
+	}
+	return value
+}
+
+// emptyFields returns the names of the given optional fields which are empty,
+// so they can be omitted while creating the entity and be left NULL
+func emptyFields(fields map[string]string) []string {
+	empties := []string{}
+	for field, value := range fields {
+		if value == "" {
+			empties = append(empties, field)
+		}
+	}
+	return empties
+}
+
+func timePeriod(validFrom time.Time, validTo *time.Time) models.TimePeriod {
+	return models.TimePeriod{
+		StartDateTime: &validFrom,
+		EndDateTime:   validTo,
+	}
+}
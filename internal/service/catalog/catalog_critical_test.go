@@ -0,0 +1,138 @@
+package catalog
+
+import (
+	"ospm/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	type testCase struct {
+		name           string
+		current        string
+		next           string
+		expectedResult bool
+	}
+
+	testCases := []testCase{
+		{
+			name:           "a draft is activated. In this case, it should be allowed",
+			current:        models.LifecycleStatusDraft,
+			next:           models.LifecycleStatusActive,
+			expectedResult: true,
+		},
+		{
+			name:           "a draft is retired. In this case, it should be allowed",
+			current:        models.LifecycleStatusDraft,
+			next:           models.LifecycleStatusRetired,
+			expectedResult: true,
+		},
+		{
+			name:           "an active entity is retired. In this case, it should be allowed",
+			current:        models.LifecycleStatusActive,
+			next:           models.LifecycleStatusRetired,
+			expectedResult: true,
+		},
+		{
+			name:           "an active entity is moved back to draft. In this case, it should be rejected",
+			current:        models.LifecycleStatusActive,
+			next:           models.LifecycleStatusDraft,
+			expectedResult: false,
+		},
+		{
+			name:           "a retired entity is activated again. In this case, it should be rejected",
+			current:        models.LifecycleStatusRetired,
+			next:           models.LifecycleStatusActive,
+			expectedResult: false,
+		},
+		{
+			name:           "the status is not changed. In this case, it should be allowed",
+			current:        models.LifecycleStatusRetired,
+			next:           models.LifecycleStatusRetired,
+			expectedResult: true,
+		},
+		{
+			name:           "an unknown status is given. In this case, it should be rejected",
+			current:        "unknown",
+			next:           "unknown",
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedResult, CanTransition(tc.current, tc.next))
+		})
+	}
+}
+
+func TestSpecificationCheck(t *testing.T) {
+	validFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	validTo := validFrom.Add(24 * time.Hour)
+	invalidTo := validFrom.Add(-time.Hour)
+
+	type testCase struct {
+		name          string
+		specification models.ProductOfferingSpecification
+		expectedError error
+	}
+
+	testCases := []testCase{
+		{
+			name: "a valid specification is given. In this case, it should be accepted",
+			specification: models.ProductOfferingSpecification{
+				Name: "Fiber Internet", Type: "service", LifecycleStatus: models.LifecycleStatusDraft, ValidFrom: validFrom, ValidTo: &validTo,
+			},
+		},
+		{
+			name: "the name is empty. In this case, it should be rejected",
+			specification: models.ProductOfferingSpecification{
+				Type: "service", LifecycleStatus: models.LifecycleStatusDraft, ValidFrom: validFrom,
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			name: "the type is neither product nor service. In this case, it should be rejected",
+			specification: models.ProductOfferingSpecification{
+				Name: "Fiber Internet", Type: "bundle", LifecycleStatus: models.LifecycleStatusDraft, ValidFrom: validFrom,
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			name: "the lifecycle status is unknown. In this case, it should be rejected",
+			specification: models.ProductOfferingSpecification{
+				Name: "Fiber Internet", Type: "service", LifecycleStatus: "launched", ValidFrom: validFrom,
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			name: "the validity period ends before it starts. In this case, it should be rejected",
+			specification: models.ProductOfferingSpecification{
+				Name: "Fiber Internet", Type: "service", LifecycleStatus: models.LifecycleStatusDraft, ValidFrom: validFrom, ValidTo: &invalidTo,
+			},
+			expectedError: ErrInvalidRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.ErrorIs(t, SpecificationCheck(&tc.specification), tc.expectedError)
+		})
+	}
+}
+
+func TestIsValidAt(t *testing.T) {
+	validFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	validTo := validFrom.Add(24 * time.Hour)
+
+	assert.True(t, IsValidAt(validFrom, &validTo, validFrom))
+	assert.True(t, IsValidAt(validFrom, nil, validFrom.Add(365*24*time.Hour)))
+	assert.False(t, IsValidAt(validFrom, &validTo, validTo))
+	assert.False(t, IsValidAt(validFrom, &validTo, validFrom.Add(-time.Second)))
+}
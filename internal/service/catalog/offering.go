@@ -0,0 +1,327 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/characteristic"
+	"ospm/internal/service/logger"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProductOfferingPath is the path of the offering resources used in their href
+const ProductOfferingPath = "/catalog/productOffering"
+
+// ProductOfferingEntityType is the type of the product offerings in the audit log
+const ProductOfferingEntityType = "product_offering"
+
+// ListOfferings returns the product offerings that match the given filter
+func ListOfferings(filter models.CatalogFilter) ([]models.ProductOfferingResource, int64, error) {
+	offerings := []models.ProductOffering{}
+	var total int64
+
+	query := applyFilter(cockroachdb.DB.Model(&models.ProductOffering{}), filter)
+	if filter.SpecificationID != "" {
+		query = query.Where("specification_id = ?", filter.SpecificationID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to count the product offerings, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, 0, errors.New(errorMessage)
+	}
+
+	if err := paginate(query, filter).Find(&offerings).Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to load the product offerings, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, 0, errors.New(errorMessage)
+	}
+
+	specificationNames, err := specificationNames(offerings)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the product specifications of the offerings, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, 0, errors.New(errorMessage)
+	}
+
+	resources := []models.ProductOfferingResource{}
+	for index := range offerings {
+		resources = append(resources, OfferingResource(&offerings[index], specificationNames[offerings[index].SpecificationID]))
+	}
+
+	return resources, total, nil
+}
+
+// OfferingDetail returns the product offering of the given id
+func OfferingDetail(offeringID string) (models.ProductOfferingResource, error) {
+	var offering models.ProductOffering
+
+	if err := cockroachdb.DB.First(&offering, "id = ?", offeringID).Error; err != nil {
+		return models.ProductOfferingResource{}, err
+	}
+
+	specificationNames, err := specificationNames([]models.ProductOffering{offering})
+	if err != nil {
+		return models.ProductOfferingResource{}, err
+	}
+
+	return OfferingResource(&offering, specificationNames[offering.SpecificationID]), nil
+}
+
+// NewOffering creates a product offering. The lifecycle status is draft and the validity
+// starts now, unless they are given. The referenced specification must exist
+func NewOffering(request models.ProductOfferingRequest, actor audit.Actor) (models.ProductOfferingResource, error) {
+	offering := models.ProductOffering{
+		LifecycleStatus: models.LifecycleStatusDraft,
+		ValidFrom:       time.Now(),
+	}
+	applyOfferingRequest(&offering, request)
+
+	var specificationName string
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if specificationName, err = OfferingCheck(tx, &offering); err != nil {
+			return err
+		}
+
+		err = tx.Omit(emptyFields(map[string]string{
+			"PersianName":     offering.PersianName,
+			"SpecificationID": offering.SpecificationID,
+		})...).Create(&offering).Error
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(tx, actor, audit.ActionCreate, ProductOfferingEntityType, offering.ID,
+			nil, OfferingResource(&offering, specificationName))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("the new product offering can not be created, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.ProductOfferingResource{}, err
+	}
+
+	logger.OSPMLogger.Infof("product offering %s is created with id %s", offering.Name, offering.ID)
+	return OfferingResource(&offering, specificationName), nil
+}
+
+// UpdateOffering partially updates the product offering
+func UpdateOffering(offeringID string, request models.ProductOfferingRequest, actor audit.Actor) (models.ProductOfferingResource, error) {
+	var offering models.ProductOffering
+	var specificationName string
+
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&offering, "id = ?", offeringID).Error; err != nil {
+			return err
+		}
+
+		currentSpecificationNames, err := specificationNames([]models.ProductOffering{offering})
+		if err != nil {
+			return err
+		}
+		before := OfferingResource(&offering, currentSpecificationNames[offering.SpecificationID])
+
+		currentStatus := offering.LifecycleStatus
+		applyOfferingRequest(&offering, request)
+
+		if !CanTransition(currentStatus, offering.LifecycleStatus) {
+			return fmt.Errorf("%w: from %s to %s", ErrInvalidLifecycleTransition, currentStatus, offering.LifecycleStatus)
+		}
+
+		if specificationName, err = OfferingCheck(tx, &offering); err != nil {
+			return err
+		}
+
+		err = tx.Model(&offering).Updates(map[string]interface{}{
+			"name":                      offering.Name,
+			"persian_name":              nullIfEmpty(offering.PersianName),
+			"description":               offering.Description,
+			"specification_id":          nullIfEmpty(offering.SpecificationID),
+			"characteristic_value":      offering.CharacteristicValue,
+			"characteristic_value_type": offering.CharacteristicValueType,
+			"lifecycle_status":          offering.LifecycleStatus,
+			"valid_from":                offering.ValidFrom,
+			"valid_to":                  offering.ValidTo,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(tx, actor, audit.ActionUpdate, ProductOfferingEntityType, offering.ID,
+			before, OfferingResource(&offering, specificationName))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to update the product offering id %s, error: %+v", offeringID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.ProductOfferingResource{}, err
+	}
+
+	return OfferingResource(&offering, specificationName), nil
+}
+
+// DeleteOffering soft deletes the product offering
+func DeleteOffering(offeringID string, actor audit.Actor) error {
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		var offering models.ProductOffering
+		if err := tx.First(&offering, "id = ?", offeringID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&offering).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, actor, audit.ActionDelete, ProductOfferingEntityType, offeringID, OfferingResource(&offering, ""), nil)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to delete the product offering id %s, error: %+v", offeringID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return errors.New(errorMessage)
+	}
+
+	return nil
+}
+
+// OfferingCheck validates the given product offering and its reference to the specification.
+// An offering can only be activated if its specification is active. The characteristic value
+// is checked against the characteristic specification and it is replaced by its normalized form.
+// It returns the name of the referenced specification
+func OfferingCheck(tx *gorm.DB, offering *models.ProductOffering) (string, error) {
+	if offering.Name == "" {
+		return "", fmt.Errorf("%w: the name of the product offering can not be empty", ErrInvalidRequest)
+	}
+
+	if _, valid := lifecycleTransitions[offering.LifecycleStatus]; !valid {
+		return "", fmt.Errorf("%w: the lifecycle status should be one of draft, active or retired. given value is: %s",
+			ErrInvalidRequest, offering.LifecycleStatus)
+	}
+
+	if err := ValidateValidity(offering.ValidFrom, offering.ValidTo); err != nil {
+		return "", err
+	}
+
+	if offering.SpecificationID == "" {
+		if offering.LifecycleStatus == models.LifecycleStatusActive {
+			return "", fmt.Errorf("%w: an active product offering must refer to a product specification", ErrInvalidReference)
+		}
+		return "", CharacteristicCheck(offering, models.CharacteristicSpecification{})
+	}
+
+	var specification models.ProductOfferingSpecification
+	err := tx.First(&specification, "id = ?", offering.SpecificationID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("%w: product specification %s does not exist", ErrInvalidReference, offering.SpecificationID)
+	} else if err != nil {
+		return "", err
+	}
+
+	if offering.LifecycleStatus == models.LifecycleStatusActive && specification.LifecycleStatus != models.LifecycleStatusActive {
+		return "", fmt.Errorf("%w: product specification %s is %s", ErrInvalidReference, specification.ID, specification.LifecycleStatus)
+	}
+
+	return specification.Name, CharacteristicCheck(offering, specification.Characteristic)
+}
+
+// CharacteristicCheck validates the characteristic value of the offering against the given
+// characteristic specification and normalizes the value and its type in place
+func CharacteristicCheck(offering *models.ProductOffering, specification models.CharacteristicSpecification) error {
+	valueType, value, err := characteristic.Validate(specification, offering.CharacteristicValueType, offering.CharacteristicValue)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	offering.CharacteristicValueType = valueType
+	offering.CharacteristicValue = value
+	return nil
+}
+
+// OfferingResource converts the offering into its TMF620 representation
+func OfferingResource(offering *models.ProductOffering, specificationName string) models.ProductOfferingResource {
+	resource := models.ProductOfferingResource{
+		ID:                      offering.ID,
+		Href:                    fmt.Sprintf("%s/%s", ProductOfferingPath, offering.ID),
+		Name:                    offering.Name,
+		PersianName:             offering.PersianName,
+		Description:             offering.Description,
+		LifecycleStatus:         offering.LifecycleStatus,
+		ValidFor:                timePeriod(offering.ValidFrom, offering.ValidTo),
+		LastUpdate:              offering.UpdatedAt,
+		CharacteristicValue:     offering.CharacteristicValue,
+		CharacteristicValueType: offering.CharacteristicValueType,
+		Type:                    "ProductOffering",
+	}
+
+	if offering.SpecificationID != "" {
+		resource.ProductSpecification = &models.ProductSpecificationRef{
+			ID:   offering.SpecificationID,
+			Href: fmt.Sprintf("%s/%s", ProductSpecificationPath, offering.SpecificationID),
+			Name: specificationName,
+		}
+	}
+
+	return resource
+}
+
+func applyOfferingRequest(offering *models.ProductOffering, request models.ProductOfferingRequest) {
+	if request.Name != nil {
+		offering.Name = *request.Name
+	}
+	if request.PersianName != nil {
+		offering.PersianName = *request.PersianName
+	}
+	if request.Description != nil {
+		offering.Description = *request.Description
+	}
+	if request.LifecycleStatus != nil {
+		offering.LifecycleStatus = *request.LifecycleStatus
+	}
+	if request.ValidFor != nil {
+		if request.ValidFor.StartDateTime != nil {
+			offering.ValidFrom = *request.ValidFor.StartDateTime
+		}
+		offering.ValidTo = request.ValidFor.EndDateTime
+	}
+	if request.ProductSpecification != nil {
+		offering.SpecificationID = request.ProductSpecification.ID
+	}
+	if request.CharacteristicValue != nil {
+		offering.CharacteristicValue = *request.CharacteristicValue
+	}
+	if request.CharacteristicValueType != nil {
+		offering.CharacteristicValueType = *request.CharacteristicValueType
+	}
+}
+
+// specificationNames returns the names of the specifications referenced by the given offerings mapped by their id
+func specificationNames(offerings []models.ProductOffering) (map[string]string, error) {
+	names := map[string]string{}
+
+	specificationIDs := []string{}
+	for _, offering := range offerings {
+		if offering.SpecificationID != "" {
+			specificationIDs = append(specificationIDs, offering.SpecificationID)
+		}
+	}
+	if len(specificationIDs) == 0 {
+		return names, nil
+	}
+
+	specifications := []models.ProductOfferingSpecification{}
+	if err := cockroachdb.DB.Select("id", "name").Where("id IN ?", specificationIDs).Find(&specifications).Error; err != nil {
+		return nil, err
+	}
+
+	for _, specification := range specifications {
+		names[specification.ID] = specification.Name
+	}
+
+	return names, nil
+}
@@ -0,0 +1,294 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/characteristic"
+	"ospm/internal/service/logger"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProductSpecificationPath is the path of the specification resources used in their href
+const ProductSpecificationPath = "/catalog/productSpecification"
+
+// ProductSpecificationEntityType is the type of the product specifications in the audit log
+const ProductSpecificationEntityType = "product_specification"
+
+// ListSpecifications returns the product offering specifications that match the given filter
+func ListSpecifications(filter models.CatalogFilter) ([]models.ProductSpecificationResource, int64, error) {
+	specifications := []models.ProductOfferingSpecification{}
+	var total int64
+
+	query := applyFilter(cockroachdb.DB.Model(&models.ProductOfferingSpecification{}), filter)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to count the product specifications, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, 0, errors.New(errorMessage)
+	}
+
+	if err := paginate(query, filter).Find(&specifications).Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to load the product specifications, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, 0, errors.New(errorMessage)
+	}
+
+	resources := []models.ProductSpecificationResource{}
+	for index := range specifications {
+		resources = append(resources, SpecificationResource(&specifications[index]))
+	}
+
+	return resources, total, nil
+}
+
+// SpecificationDetail returns the product offering specification of the given id
+func SpecificationDetail(specificationID string) (models.ProductSpecificationResource, error) {
+	var specification models.ProductOfferingSpecification
+
+	if err := cockroachdb.DB.First(&specification, "id = ?", specificationID).Error; err != nil {
+		return models.ProductSpecificationResource{}, err
+	}
+
+	return SpecificationResource(&specification), nil
+}
+
+// NewSpecification creates a product offering specification. The lifecycle status
+// is draft and the validity starts now, unless they are given
+func NewSpecification(request models.ProductSpecificationRequest, actor audit.Actor) (models.ProductSpecificationResource, error) {
+	specification := models.ProductOfferingSpecification{
+		LifecycleStatus: models.LifecycleStatusDraft,
+		ValidFrom:       time.Now(),
+	}
+	applySpecificationRequest(&specification, request)
+
+	if err := SpecificationCheck(&specification); err != nil {
+		return models.ProductSpecificationResource{}, err
+	}
+
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(emptyFields(map[string]string{"PersianName": specification.PersianName})...).Create(&specification).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, actor, audit.ActionCreate, ProductSpecificationEntityType, specification.ID, nil, SpecificationResource(&specification))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("the new product specification can not be created, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.ProductSpecificationResource{}, err
+	}
+
+	logger.OSPMLogger.Infof("product specification %s is created with id %s", specification.Name, specification.ID)
+	return SpecificationResource(&specification), nil
+}
+
+// UpdateSpecification partially updates the product offering specification. A specification
+// can not be retired while it is used by offerings that are not retired and its characteristic
+// can not be changed in a way that the values of those offerings become invalid
+func UpdateSpecification(specificationID string, request models.ProductSpecificationRequest, actor audit.Actor) (models.ProductSpecificationResource, error) {
+	var specification models.ProductOfferingSpecification
+
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&specification, "id = ?", specificationID).Error; err != nil {
+			return err
+		}
+
+		before := SpecificationResource(&specification)
+
+		currentStatus := specification.LifecycleStatus
+		applySpecificationRequest(&specification, request)
+
+		if !CanTransition(currentStatus, specification.LifecycleStatus) {
+			return fmt.Errorf("%w: from %s to %s", ErrInvalidLifecycleTransition, currentStatus, specification.LifecycleStatus)
+		}
+
+		if err := SpecificationCheck(&specification); err != nil {
+			return err
+		}
+
+		if specification.LifecycleStatus == models.LifecycleStatusRetired && currentStatus != models.LifecycleStatusRetired {
+			inUse, err := specificationInUse(tx, specificationID)
+			if err != nil {
+				return err
+			}
+			if inUse {
+				return fmt.Errorf("%w: retire its offerings first", ErrSpecificationInUse)
+			}
+		}
+
+		if request.Characteristic != nil {
+			if err := offeringsCharacteristicCheck(tx, specification); err != nil {
+				return err
+			}
+		}
+
+		err := tx.Model(&specification).Updates(map[string]interface{}{
+			"name":                          specification.Name,
+			"persian_name":                  nullIfEmpty(specification.PersianName),
+			"description":                   specification.Description,
+			"type":                          specification.Type,
+			"lifecycle_status":              specification.LifecycleStatus,
+			"valid_from":                    specification.ValidFrom,
+			"valid_to":                      specification.ValidTo,
+			"characteristic_value_type":     specification.Characteristic.ValueType,
+			"characteristic_min_value":      specification.Characteristic.MinValue,
+			"characteristic_max_value":      specification.Characteristic.MaxValue,
+			"characteristic_allowed_values": specification.Characteristic.AllowedValues,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(tx, actor, audit.ActionUpdate, ProductSpecificationEntityType, specificationID, before, SpecificationResource(&specification))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to update the product specification id %s, error: %+v", specificationID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.ProductSpecificationResource{}, err
+	}
+
+	return SpecificationResource(&specification), nil
+}
+
+// DeleteSpecification soft deletes the product offering specification.
+// Specifications that are referenced by offerings can not be deleted
+func DeleteSpecification(specificationID string, actor audit.Actor) error {
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		var specification models.ProductOfferingSpecification
+		if err := tx.First(&specification, "id = ?", specificationID).Error; err != nil {
+			return err
+		}
+
+		var offeringCount int64
+		if err := tx.Model(&models.ProductOffering{}).Where("specification_id = ?", specificationID).Count(&offeringCount).Error; err != nil {
+			return err
+		}
+		if offeringCount > 0 {
+			return fmt.Errorf("%w: %d offerings refer to it", ErrSpecificationInUse, offeringCount)
+		}
+
+		if err := tx.Delete(&specification).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, actor, audit.ActionDelete, ProductSpecificationEntityType, specificationID, SpecificationResource(&specification), nil)
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to delete the product specification id %s, error: %+v", specificationID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	return nil
+}
+
+// SpecificationCheck validates the given product offering specification
+func SpecificationCheck(specification *models.ProductOfferingSpecification) error {
+	if specification.Name == "" {
+		return fmt.Errorf("%w: the name of the product specification can not be empty", ErrInvalidRequest)
+	}
+
+	if specification.Type != "product" && specification.Type != "service" {
+		return fmt.Errorf("%w: the type of the product specification should be either product or service. given value is: %s",
+			ErrInvalidRequest, specification.Type)
+	}
+
+	if _, valid := lifecycleTransitions[specification.LifecycleStatus]; !valid {
+		return fmt.Errorf("%w: the lifecycle status should be one of draft, active or retired. given value is: %s",
+			ErrInvalidRequest, specification.LifecycleStatus)
+	}
+
+	if err := characteristic.ValidateSpecification(&specification.Characteristic); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	return ValidateValidity(specification.ValidFrom, specification.ValidTo)
+}
+
+// SpecificationResource converts the specification into its TMF620 representation
+func SpecificationResource(specification *models.ProductOfferingSpecification) models.ProductSpecificationResource {
+	resource := models.ProductSpecificationResource{
+		ID:              specification.ID,
+		Href:            fmt.Sprintf("%s/%s", ProductSpecificationPath, specification.ID),
+		Name:            specification.Name,
+		PersianName:     specification.PersianName,
+		Description:     specification.Description,
+		SpecType:        specification.Type,
+		LifecycleStatus: specification.LifecycleStatus,
+		ValidFor:        timePeriod(specification.ValidFrom, specification.ValidTo),
+		LastUpdate:      specification.UpdatedAt,
+		Type:            "ProductSpecification",
+	}
+
+	if specification.Characteristic.ValueType != "" {
+		characteristic := specification.Characteristic
+		resource.Characteristic = &characteristic
+	}
+
+	return resource
+}
+
+func applySpecificationRequest(specification *models.ProductOfferingSpecification, request models.ProductSpecificationRequest) {
+	if request.Name != nil {
+		specification.Name = *request.Name
+	}
+	if request.PersianName != nil {
+		specification.PersianName = *request.PersianName
+	}
+	if request.Description != nil {
+		specification.Description = *request.Description
+	}
+	if request.SpecType != nil {
+		specification.Type = *request.SpecType
+	}
+	if request.LifecycleStatus != nil {
+		specification.LifecycleStatus = *request.LifecycleStatus
+	}
+	if request.ValidFor != nil {
+		if request.ValidFor.StartDateTime != nil {
+			specification.ValidFrom = *request.ValidFor.StartDateTime
+		}
+		specification.ValidTo = request.ValidFor.EndDateTime
+	}
+	if request.Characteristic != nil {
+		specification.Characteristic = *request.Characteristic
+	}
+}
+
+// offeringsCharacteristicCheck checks the characteristic values of the offerings that refer to
+// the specification and are not retired against its characteristic specification
+func offeringsCharacteristicCheck(tx *gorm.DB, specification models.ProductOfferingSpecification) error {
+	offerings := []models.ProductOffering{}
+	err := tx.Where("specification_id = ? AND lifecycle_status <> ?", specification.ID, models.LifecycleStatusRetired).
+		Find(&offerings).Error
+	if err != nil {
+		return err
+	}
+
+	for index := range offerings {
+		_, _, err := characteristic.Validate(specification.Characteristic, offerings[index].CharacteristicValueType, offerings[index].CharacteristicValue)
+		if err != nil {
+			return fmt.Errorf("%w: product offering %s does not satisfy the characteristic, %w", ErrSpecificationInUse, offerings[index].ID, err)
+		}
+	}
+
+	return nil
+}
+
+// specificationInUse determines whether the specification is used by offerings that are not retired
+func specificationInUse(tx *gorm.DB, specificationID string) (bool, error) {
+	var offeringCount int64
+	err := tx.Model(&models.ProductOffering{}).
+		Where("specification_id = ? AND lifecycle_status <> ?", specificationID, models.LifecycleStatusRetired).
+		Count(&offeringCount).Error
+
+	return offeringCount > 0, err
+}
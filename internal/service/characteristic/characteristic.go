@@ -0,0 +1,306 @@
+package characteristic
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"ospm/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The supported types of the characteristic values
+const (
+	TypeInteger    = "integer"
+	TypeDecimal    = "decimal"
+	TypeBoolean    = "boolean"
+	TypeEnum       = "enum"
+	TypeDuration   = "duration"    // e.g. 30d, 1h30m or 90s. Normalized to seconds: 5400s
+	TypeBandwidth  = "bandwidth"   // e.g. 10Mbps. Normalized to bits per second: 10000000bps
+	TypeDataVolume = "data_volume" // e.g. 50GB or 1.5GiB. Normalized to bytes: 50000000000B
+)
+
+var (
+	// ErrInvalidValue is returned when the value does not match its type or its constraints
+	ErrInvalidValue = errors.New("invalid characteristic value")
+
+	// ErrInvalidConstraint is returned when the constraints of a specification are not consistent
+	ErrInvalidConstraint = errors.New("invalid characteristic constraint")
+)
+
+// bandwidthUnits are the multipliers of the bandwidth units to bits per second.
+// The prefixes are case insensitive but the unit must be bps, so bytes per second are not mixed up
+var bandwidthUnits = map[string]int64{
+	"bps":  1,
+	"kbps": 1_000,
+	"mbps": 1_000_000,
+	"gbps": 1_000_000_000,
+	"tbps": 1_000_000_000_000,
+}
+
+// dataVolumeUnits are the multipliers of the data volume units to bytes.
+// Both decimal (GB) and binary (GiB) prefixes are supported
+var dataVolumeUnits = map[string]int64{
+	"B":   1,
+	"KB":  1_000,
+	"MB":  1_000_000,
+	"GB":  1_000_000_000,
+	"TB":  1_000_000_000_000,
+	"PB":  1_000_000_000_000_000,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+	"PiB": 1 << 50,
+}
+
+// durationUnits are the multipliers of the duration units to seconds
+var durationUnits = map[string]int64{
+	"s": 1,
+	"m": 60,
+	"h": 3600,
+	"d": 86400,
+	"w": 604800,
+}
+
+// Value is a parsed characteristic value
+type Value struct {
+	Type       string
+	Normalized string
+	number     *big.Rat // the magnitude of the numeric types in their base unit, nil for the others
+}
+
+// IsValidType determines whether the given type is supported
+func IsValidType(valueType string) bool {
+	switch valueType {
+	case TypeInteger, TypeDecimal, TypeBoolean, TypeEnum, TypeDuration, TypeBandwidth, TypeDataVolume:
+		return true
+	}
+	return false
+}
+
+// Parse parses the value of the given type into its normalized form.
+// Enum values are kept as they are, since their valid values are defined by the constraints
+func Parse(valueType string, value string) (Value, error) {
+	text := strings.TrimSpace(value)
+	if text == "" {
+		return Value{}, fmt.Errorf("%w: empty %s value", ErrInvalidValue, valueType)
+	}
+
+	switch valueType {
+	case TypeInteger:
+		integer, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return Value{}, fmt.Errorf("%w: %q is not an integer", ErrInvalidValue, value)
+		}
+		return Value{Type: valueType, Normalized: strconv.FormatInt(integer, 10), number: new(big.Rat).SetInt64(integer)}, nil
+
+	case TypeDecimal:
+		decimal, err := models.ParseDecimal(text)
+		if err != nil {
+			return Value{}, fmt.Errorf("%w: %q is not a decimal", ErrInvalidValue, value)
+		}
+		number, _ := new(big.Rat).SetString(decimal.String())
+		return Value{Type: valueType, Normalized: decimal.String(), number: number}, nil
+
+	case TypeBoolean:
+		boolean, err := strconv.ParseBool(strings.ToLower(text))
+		if err != nil {
+			return Value{}, fmt.Errorf("%w: %q is not a boolean", ErrInvalidValue, value)
+		}
+		return Value{Type: valueType, Normalized: strconv.FormatBool(boolean)}, nil
+
+	case TypeEnum:
+		return Value{Type: valueType, Normalized: text}, nil
+
+	case TypeDuration:
+		return parseQuantity(valueType, text, durationUnits, "s", strings.ToLower)
+
+	case TypeBandwidth:
+		return parseQuantity(valueType, text, bandwidthUnits, "bps", strings.ToLower)
+
+	case TypeDataVolume:
+		return parseQuantity(valueType, text, dataVolumeUnits, "B", func(unit string) string { return unit })
+	}
+
+	return Value{}, fmt.Errorf("%w: unknown type %q", ErrInvalidValue, valueType)
+}
+
+// Validate checks the value of an offering against the characteristic specification and returns
+// its type and its normalized form. If the specification defines a type, the offering must use
+// the same type or leave it empty to inherit it. Offerings without a value are valid only if
+// the specification does not define a characteristic
+func Validate(specification models.CharacteristicSpecification, valueType string, value string) (string, string, error) {
+	if specification.ValueType != "" {
+		if valueType == "" {
+			valueType = specification.ValueType
+		} else if valueType != specification.ValueType {
+			return "", "", fmt.Errorf("%w: the specification requires %s values, given type is: %s",
+				ErrInvalidValue, specification.ValueType, valueType)
+		}
+	}
+
+	if valueType == "" {
+		if strings.TrimSpace(value) != "" {
+			return "", "", fmt.Errorf("%w: the type of the value %q must be given", ErrInvalidValue, value)
+		}
+		return "", "", nil
+	}
+
+	if !IsValidType(valueType) {
+		return "", "", fmt.Errorf("%w: unknown type %q", ErrInvalidValue, valueType)
+	}
+
+	parsed, err := Parse(valueType, value)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := checkConstraints(specification, parsed); err != nil {
+		return "", "", err
+	}
+
+	return valueType, parsed.Normalized, nil
+}
+
+// ValidateSpecification checks that the constraints are consistent with their type and
+// normalizes them in place, so the stored constraints are in the same form as the values
+func ValidateSpecification(specification *models.CharacteristicSpecification) error {
+	if specification.ValueType == "" {
+		if specification.MinValue != "" || specification.MaxValue != "" || len(specification.AllowedValues) > 0 {
+			return fmt.Errorf("%w: the value type must be given for the constraints", ErrInvalidConstraint)
+		}
+		return nil
+	}
+
+	if !IsValidType(specification.ValueType) {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidConstraint, specification.ValueType)
+	}
+
+	switch specification.ValueType {
+	case TypeBoolean, TypeEnum:
+		if specification.MinValue != "" || specification.MaxValue != "" {
+			return fmt.Errorf("%w: %s values can not have min or max", ErrInvalidConstraint, specification.ValueType)
+		}
+	}
+
+	if specification.ValueType == TypeEnum && len(specification.AllowedValues) == 0 {
+		return fmt.Errorf("%w: the allowed values of enum must be given", ErrInvalidConstraint)
+	}
+
+	var minimum, maximum Value
+	var err error
+	if specification.MinValue != "" {
+		if minimum, err = Parse(specification.ValueType, specification.MinValue); err != nil {
+			return fmt.Errorf("%w: min value, %w", ErrInvalidConstraint, err)
+		}
+		specification.MinValue = minimum.Normalized
+	}
+	if specification.MaxValue != "" {
+		if maximum, err = Parse(specification.ValueType, specification.MaxValue); err != nil {
+			return fmt.Errorf("%w: max value, %w", ErrInvalidConstraint, err)
+		}
+		specification.MaxValue = maximum.Normalized
+	}
+	if minimum.number != nil && maximum.number != nil && minimum.number.Cmp(maximum.number) > 0 {
+		return fmt.Errorf("%w: min value %s is greater than max value %s", ErrInvalidConstraint, minimum.Normalized, maximum.Normalized)
+	}
+
+	allowedValues := models.StringList{}
+	for _, allowedValue := range specification.AllowedValues {
+		parsed, err := Parse(specification.ValueType, allowedValue)
+		if err != nil {
+			return fmt.Errorf("%w: allowed value, %w", ErrInvalidConstraint, err)
+		}
+		if err := checkRange(*specification, parsed); err != nil {
+			return fmt.Errorf("%w: allowed value, %w", ErrInvalidConstraint, err)
+		}
+		allowedValues = append(allowedValues, parsed.Normalized)
+	}
+	if len(allowedValues) > 0 {
+		specification.AllowedValues = allowedValues
+	}
+
+	return nil
+}
+
+func checkConstraints(specification models.CharacteristicSpecification, value Value) error {
+	if err := checkRange(specification, value); err != nil {
+		return err
+	}
+
+	if len(specification.AllowedValues) == 0 {
+		return nil
+	}
+
+	for _, allowedValue := range specification.AllowedValues {
+		allowed, err := Parse(specification.ValueType, allowedValue)
+		if err == nil && allowed.Normalized == value.Normalized {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s is not one of the allowed values: %s",
+		ErrInvalidValue, value.Normalized, strings.Join(specification.AllowedValues, ", "))
+}
+
+func checkRange(specification models.CharacteristicSpecification, value Value) error {
+	if value.number == nil {
+		return nil
+	}
+
+	if specification.MinValue != "" {
+		minimum, err := Parse(specification.ValueType, specification.MinValue)
+		if err == nil && value.number.Cmp(minimum.number) < 0 {
+			return fmt.Errorf("%w: %s is less than the min value %s", ErrInvalidValue, value.Normalized, minimum.Normalized)
+		}
+	}
+
+	if specification.MaxValue != "" {
+		maximum, err := Parse(specification.ValueType, specification.MaxValue)
+		if err == nil && value.number.Cmp(maximum.number) > 0 {
+			return fmt.Errorf("%w: %s is greater than the max value %s", ErrInvalidValue, value.Normalized, maximum.Normalized)
+		}
+	}
+
+	return nil
+}
+
+// parseQuantity parses a number followed by a unit, like 1.5GiB, and normalizes it to the base unit.
+// Durations can also be given in Go format with multiple units, like 1h30m
+func parseQuantity(valueType string, text string, units map[string]int64, baseUnit string, unitKey func(string) string) (Value, error) {
+	if valueType == TypeDuration {
+		if duration, err := time.ParseDuration(text); err == nil && strings.ContainsAny(text, "hms") {
+			if duration < 0 || duration%time.Second != 0 {
+				return Value{}, fmt.Errorf("%w: %q is not a whole number of seconds", ErrInvalidValue, text)
+			}
+			seconds := int64(duration / time.Second)
+			return Value{Type: valueType, Normalized: fmt.Sprintf("%d%s", seconds, baseUnit), number: new(big.Rat).SetInt64(seconds)}, nil
+		}
+	}
+
+	unitStart := strings.IndexFunc(text, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if unitStart <= 0 {
+		return Value{}, fmt.Errorf("%w: %q should be a number followed by a unit", ErrInvalidValue, text)
+	}
+
+	multiplier, found := units[unitKey(strings.TrimSpace(text[unitStart:]))]
+	if !found {
+		return Value{}, fmt.Errorf("%w: unknown %s unit in %q", ErrInvalidValue, valueType, text)
+	}
+
+	number, valid := new(big.Rat).SetString(text[:unitStart])
+	if !valid {
+		return Value{}, fmt.Errorf("%w: %q should be a number followed by a unit", ErrInvalidValue, text)
+	}
+
+	number.Mul(number, new(big.Rat).SetInt64(multiplier))
+	if !number.IsInt() || !number.Num().IsInt64() {
+		return Value{}, fmt.Errorf("%w: %q is not a whole number of %s", ErrInvalidValue, text, baseUnit)
+	}
+
+	return Value{Type: valueType, Normalized: fmt.Sprintf("%s%s", number.Num().String(), baseUnit), number: number}, nil
+}
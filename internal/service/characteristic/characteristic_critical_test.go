@@ -0,0 +1,292 @@
+package characteristic
+
+import (
+	"ospm/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		name               string
+		valueType          string
+		value              string
+		expectedNormalized string
+		expectedError      error
+	}
+
+	testCases := []testCase{
+		{
+			name:               "an integer is given. In this case, it should be kept as it is",
+			valueType:          TypeInteger,
+			value:              " 42 ",
+			expectedNormalized: "42",
+		},
+		{
+			name:          "a fraction is given as an integer. In this case, it should be rejected",
+			valueType:     TypeInteger,
+			value:         "4.2",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:               "a decimal with trailing zeros is given. In this case, the zeros should be removed",
+			valueType:          TypeDecimal,
+			value:              "12.500",
+			expectedNormalized: "12.5",
+		},
+		{
+			name:               "a boolean is given in upper case. In this case, it should be normalized to lower case",
+			valueType:          TypeBoolean,
+			value:              "TRUE",
+			expectedNormalized: "true",
+		},
+		{
+			name:               "a duration in days is given. In this case, it should be normalized to seconds",
+			valueType:          TypeDuration,
+			value:              "30d",
+			expectedNormalized: "2592000s",
+		},
+		{
+			name:               "a duration with multiple units is given. In this case, it should be normalized to seconds",
+			valueType:          TypeDuration,
+			value:              "1h30m",
+			expectedNormalized: "5400s",
+		},
+		{
+			name:          "a duration shorter than a second is given. In this case, it should be rejected",
+			valueType:     TypeDuration,
+			value:         "1500ms",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:               "a bandwidth in Mbps is given. In this case, it should be normalized to bits per second",
+			valueType:          TypeBandwidth,
+			value:              "10Mbps",
+			expectedNormalized: "10000000bps",
+		},
+		{
+			name:               "a fractional bandwidth with a lower case prefix is given. In this case, it should be normalized to bits per second",
+			valueType:          TypeBandwidth,
+			value:              "2.5 gbps",
+			expectedNormalized: "2500000000bps",
+		},
+		{
+			name:          "a bandwidth without a unit is given. In this case, it should be rejected",
+			valueType:     TypeBandwidth,
+			value:         "1000",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:          "a bandwidth in bytes per second is given. In this case, it should be rejected",
+			valueType:     TypeBandwidth,
+			value:         "10MB",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:               "a data volume with a decimal prefix is given. In this case, it should be normalized to bytes",
+			valueType:          TypeDataVolume,
+			value:              "50GB",
+			expectedNormalized: "50000000000B",
+		},
+		{
+			name:               "a data volume with a binary prefix is given. In this case, it should be normalized to bytes",
+			valueType:          TypeDataVolume,
+			value:              "1.5GiB",
+			expectedNormalized: "1610612736B",
+		},
+		{
+			name:          "a data volume which is not a whole number of bytes is given. In this case, it should be rejected",
+			valueType:     TypeDataVolume,
+			value:         "0.5B",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:          "a negative data volume is given. In this case, it should be rejected",
+			valueType:     TypeDataVolume,
+			value:         "-5GB",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:          "an unknown type is given. In this case, it should be rejected",
+			valueType:     "speed",
+			value:         "10",
+			expectedError: ErrInvalidValue,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			value, err := Parse(tc.valueType, tc.value)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedNormalized, value.Normalized)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	bandwidth := models.CharacteristicSpecification{ValueType: TypeBandwidth, MinValue: "1Mbps", MaxValue: "1Gbps"}
+	plans := models.CharacteristicSpecification{ValueType: TypeEnum, AllowedValues: models.StringList{"gold", "silver"}}
+
+	type testCase struct {
+		name               string
+		specification      models.CharacteristicSpecification
+		valueType          string
+		value              string
+		expectedType       string
+		expectedNormalized string
+		expectedError      error
+	}
+
+	testCases := []testCase{
+		{
+			name:               "a value within the range is given. In this case, it should be normalized",
+			specification:      bandwidth,
+			valueType:          TypeBandwidth,
+			value:              "100Mbps",
+			expectedType:       TypeBandwidth,
+			expectedNormalized: "100000000bps",
+		},
+		{
+			name:               "the type of the value is not given. In this case, the type of the specification should be used",
+			specification:      bandwidth,
+			value:              "1Gbps",
+			expectedType:       TypeBandwidth,
+			expectedNormalized: "1000000000bps",
+		},
+		{
+			name:          "a value over the max value is given. In this case, it should be rejected",
+			specification: bandwidth,
+			value:         "10Gbps",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:          "a value under the min value is given. In this case, it should be rejected",
+			specification: bandwidth,
+			value:         "512kbps",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:          "a type other than the type of the specification is given. In this case, it should be rejected",
+			specification: bandwidth,
+			valueType:     TypeDataVolume,
+			value:         "100MB",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:          "the value is empty while the specification defines a characteristic. In this case, it should be rejected",
+			specification: bandwidth,
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:               "an allowed enum value is given. In this case, it should be accepted",
+			specification:      plans,
+			value:              "gold",
+			expectedType:       TypeEnum,
+			expectedNormalized: "gold",
+		},
+		{
+			name:          "an enum value which is not allowed is given. In this case, it should be rejected",
+			specification: plans,
+			value:         "bronze",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:               "a typed value is given without a characteristic specification. In this case, it should be normalized",
+			valueType:          TypeDataVolume,
+			value:              "50GB",
+			expectedType:       TypeDataVolume,
+			expectedNormalized: "50000000000B",
+		},
+		{
+			name:          "a value without a type is given without a characteristic specification. In this case, it should be rejected",
+			value:         "50GB",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name: "neither a value nor a characteristic specification is given. In this case, it should be accepted",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			valueType, normalized, err := Validate(tc.specification, tc.valueType, tc.value)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedType, valueType)
+			assert.Equal(t, tc.expectedNormalized, normalized)
+		})
+	}
+}
+
+func TestValidateSpecification(t *testing.T) {
+	type testCase struct {
+		name          string
+		specification models.CharacteristicSpecification
+		expected      models.CharacteristicSpecification
+		expectedError error
+	}
+
+	testCases := []testCase{
+		{
+			name:          "a range of data volumes is given. In this case, the constraints should be normalized",
+			specification: models.CharacteristicSpecification{ValueType: TypeDataVolume, MinValue: "1GB", MaxValue: "1TB", AllowedValues: models.StringList{"50GB", "100GB"}},
+			expected:      models.CharacteristicSpecification{ValueType: TypeDataVolume, MinValue: "1000000000B", MaxValue: "1000000000000B", AllowedValues: models.StringList{"50000000000B", "100000000000B"}},
+		},
+		{
+			name:          "the min value is greater than the max value. In this case, it should be rejected",
+			specification: models.CharacteristicSpecification{ValueType: TypeInteger, MinValue: "10", MaxValue: "1"},
+			expectedError: ErrInvalidConstraint,
+		},
+		{
+			name:          "an allowed value is out of the range. In this case, it should be rejected",
+			specification: models.CharacteristicSpecification{ValueType: TypeDuration, MaxValue: "30d", AllowedValues: models.StringList{"7d", "90d"}},
+			expectedError: ErrInvalidConstraint,
+		},
+		{
+			name:          "an enum without allowed values is given. In this case, it should be rejected",
+			specification: models.CharacteristicSpecification{ValueType: TypeEnum},
+			expectedError: ErrInvalidConstraint,
+		},
+		{
+			name:          "a boolean with a min value is given. In this case, it should be rejected",
+			specification: models.CharacteristicSpecification{ValueType: TypeBoolean, MinValue: "false"},
+			expectedError: ErrInvalidConstraint,
+		},
+		{
+			name:          "constraints without a type are given. In this case, it should be rejected",
+			specification: models.CharacteristicSpecification{MaxValue: "10"},
+			expectedError: ErrInvalidConstraint,
+		},
+		{
+			name:          "an unknown type is given. In this case, it should be rejected",
+			specification: models.CharacteristicSpecification{ValueType: "speed"},
+			expectedError: ErrInvalidConstraint,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateSpecification(&tc.specification)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, tc.specification)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package clientip
+
+import (
+	"net"
+	"net/netip"
+	"ospm/internal/service/complementary"
+	"strings"
+)
+
+const (
+	HeaderForwarded     = "Forwarded"
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderXRealIP       = "X-Real-IP"
+)
+
+// Resolve returns the IP address of the client that sent the request. The forwarding header is
+// only used when the request comes from a trusted proxy, otherwise anyone could spoof their address.
+// Only the given header, which is the one that the trusted proxies set, is read since the proxies pass
+// the other ones through as the client sends them. The hops of the Forwarded (RFC 7239) or the
+// X-Forwarded-For header are checked from the nearest one and the first hop that is not a trusted proxy
+// is the client. The remote address is returned as it is if it can not be parsed
+func Resolve(remoteIP string, header func(string) string, trustedProxies *complementary.IPSet, clientIPHeader string) string {
+	remoteAddress, err := netip.ParseAddr(remoteIP)
+	if err != nil {
+		return remoteIP
+	}
+	remoteAddress = remoteAddress.Unmap()
+
+	if !trustedProxies.Contains(remoteAddress) {
+		return remoteAddress.String()
+	}
+
+	var hops []string
+	switch clientIPHeader {
+	case HeaderForwarded:
+		hops = forwardedHops(header(HeaderForwarded))
+	case HeaderXForwardedFor:
+		hops = forwardedForHops(header(HeaderXForwardedFor))
+	case HeaderXRealIP:
+		if realIP, valid := parseNode(header(HeaderXRealIP)); valid {
+			return realIP.String()
+		}
+	}
+
+	if len(hops) == 0 {
+		return remoteAddress.String()
+	}
+
+	client := remoteAddress
+	for index := len(hops) - 1; index >= 0; index-- {
+		hop, valid := parseNode(hops[index])
+		if !valid {
+			// the hops before an unknown or obfuscated one can not be checked
+			break
+		}
+
+		client = hop
+		if !trustedProxies.Contains(hop) {
+			break
+		}
+	}
+
+	return client.String()
+}
+
+// forwardedHops returns the "for" parameter of each element of the Forwarded header
+func forwardedHops(value string) []string {
+	hops := []string{}
+
+	for _, element := range splitOutsideQuotes(value, ',') {
+		found := false
+		for _, pair := range splitOutsideQuotes(element, ';') {
+			key, pairValue, hasValue := strings.Cut(strings.TrimSpace(pair), "=")
+			if hasValue && strings.EqualFold(strings.TrimSpace(key), "for") {
+				hops = append(hops, strings.TrimSpace(pairValue))
+				found = true
+				break
+			}
+		}
+
+		// an element without a "for" parameter breaks the chain of the hops
+		if !found && strings.TrimSpace(element) != "" {
+			hops = append(hops, "unknown")
+		}
+	}
+
+	return hops
+}
+
+func forwardedForHops(value string) []string {
+	hops := []string{}
+
+	for _, hop := range strings.Split(value, ",") {
+		if hop = strings.TrimSpace(hop); hop != "" {
+			hops = append(hops, hop)
+		}
+	}
+
+	return hops
+}
+
+// parseNode parses a node of the forwarding headers. The quotes, the brackets
+// of the IPv6 addresses and the ports are removed
+func parseNode(node string) (netip.Addr, bool) {
+	node = strings.Trim(strings.TrimSpace(node), "\"")
+	if node == "" {
+		return netip.Addr{}, false
+	}
+
+	if address, err := netip.ParseAddr(strings.Trim(node, "[]")); err == nil {
+		return address.Unmap(), true
+	}
+
+	host, _, err := net.SplitHostPort(node)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	address, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return address.Unmap(), true
+}
+
+func splitOutsideQuotes(value string, separator rune) []string {
+	parts := []string{}
+	quoted := false
+	start := 0
+
+	for index, character := range value {
+		switch {
+		case character == '"':
+			quoted = !quoted
+		case character == separator && !quoted:
+			parts = append(parts, value[start:index])
+			start = index + 1
+		}
+	}
+
+	return append(parts, value[start:])
+}
@@ -0,0 +1,109 @@
+package clientip
+
+import (
+	"ospm/internal/service/complementary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	trustedProxies, err := complementary.ParseIPSet([]string{"10.0.0.0/8", "2001:db8::/32", "192.168.1.10"})
+	assert.NoError(t, err)
+
+	type testCase struct {
+		name           string
+		remoteIP       string
+		clientIPHeader string
+		headers        map[string]string
+		expectedIP     string
+	}
+
+	testCases := []testCase{
+		{
+			name:       "the request does not come from a trusted proxy. In this case, the forwarding headers should be ignored",
+			remoteIP:   "203.0.113.7",
+			headers:    map[string]string{HeaderXForwardedFor: "127.0.0.1"},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "the request comes from a trusted proxy. In this case, the client should be taken from X-Forwarded-For",
+			remoteIP:   "10.1.2.3",
+			headers:    map[string]string{HeaderXForwardedFor: "198.51.100.4"},
+			expectedIP: "198.51.100.4",
+		},
+		{
+			name:       "the client spoofs X-Forwarded-For behind a chain of trusted proxies. In this case, the nearest untrusted hop should be the client",
+			remoteIP:   "10.1.2.3",
+			headers:    map[string]string{HeaderXForwardedFor: "127.0.0.1, 198.51.100.4, 192.168.1.10"},
+			expectedIP: "198.51.100.4",
+		},
+		{
+			name:       "all of the hops are trusted proxies. In this case, the farthest hop should be the client",
+			remoteIP:   "10.1.2.3",
+			headers:    map[string]string{HeaderXForwardedFor: "10.9.9.9, 192.168.1.10"},
+			expectedIP: "10.9.9.9",
+		},
+		{
+			name:           "the proxy sets the Forwarded header with quoted IPv6 and ports. In this case, it should be used instead of X-Forwarded-For",
+			remoteIP:       "2001:db8::1",
+			clientIPHeader: HeaderForwarded,
+			headers: map[string]string{
+				HeaderForwarded:     `for="[2001:db9::17]:4711";proto=https, for="10.0.0.5:8080";by=10.0.0.1`,
+				HeaderXForwardedFor: "198.51.100.4",
+			},
+			expectedIP: "2001:db9::17",
+		},
+		{
+			name:           "the nearest hop of the Forwarded header is obfuscated. In this case, the last known hop should be the client",
+			remoteIP:       "10.1.2.3",
+			clientIPHeader: HeaderForwarded,
+			headers:        map[string]string{HeaderForwarded: "for=198.51.100.4, for=_hidden"},
+			expectedIP:     "10.1.2.3",
+		},
+		{
+			name:           "X-Real-IP is set by a trusted proxy. In this case, it should be the client",
+			remoteIP:       "192.168.1.10",
+			clientIPHeader: HeaderXRealIP,
+			headers:        map[string]string{HeaderXRealIP: "198.51.100.4"},
+			expectedIP:     "198.51.100.4",
+		},
+		{
+			name:       "the trusted proxy connects by an IPv4-mapped IPv6 address. In this case, it should match the IPv4 CIDR",
+			remoteIP:   "::ffff:10.1.2.3",
+			headers:    map[string]string{HeaderXForwardedFor: "::ffff:198.51.100.4"},
+			expectedIP: "198.51.100.4",
+		},
+		{
+			name:       "the proxy only appends X-Forwarded-For and the client sends a Forwarded header. In this case, the spoofed Forwarded header should be ignored",
+			remoteIP:   "10.1.2.3",
+			headers:    map[string]string{HeaderForwarded: "for=127.0.0.1", HeaderXForwardedFor: "198.51.100.4"},
+			expectedIP: "198.51.100.4",
+		},
+		{
+			name:       "the proxy only appends X-Forwarded-For and the client sends X-Real-IP without it. In this case, the address of the proxy should be the client",
+			remoteIP:   "10.1.2.3",
+			headers:    map[string]string{HeaderXRealIP: "127.0.0.1"},
+			expectedIP: "10.1.2.3",
+		},
+		{
+			name:           "the proxy sets X-Real-IP and the client sends X-Forwarded-For. In this case, the spoofed X-Forwarded-For should be ignored",
+			remoteIP:       "192.168.1.10",
+			clientIPHeader: HeaderXRealIP,
+			headers:        map[string]string{HeaderXForwardedFor: "127.0.0.1", HeaderXRealIP: "198.51.100.4"},
+			expectedIP:     "198.51.100.4",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if tc.clientIPHeader == "" {
+				tc.clientIPHeader = HeaderXForwardedFor
+			}
+			header := func(key string) string { return tc.headers[key] }
+			assert.Equal(t, tc.expectedIP, Resolve(tc.remoteIP, header, trustedProxies, tc.clientIPHeader))
+		})
+	}
+}
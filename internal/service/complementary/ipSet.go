@@ -0,0 +1,150 @@
+package complementary
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// IPSet is a parsed list of IP addresses and CIDRs. The entries starting with ! are deny entries
+// which exclude addresses from the allow entries, e.g. 10.0.0.0/8 and !10.0.0.5.
+// IPv4-mapped IPv6 addresses and ranges are treated as IPv4 ones
+type IPSet struct {
+	allow prefixTable
+	deny  prefixTable
+}
+
+// prefixTable keeps the prefixes by their length, so an address is matched by
+// one map lookup per distinct length no matter how many prefixes there are
+type prefixTable struct {
+	lengths4 []int
+	lengths6 []int
+	prefixes map[netip.Prefix]struct{}
+}
+
+// ParseIPSet parses the entries of an allowlist. Each entry is an IP address or a CIDR,
+// optionally starting with ! to deny it. Any invalid entry is rejected
+func ParseIPSet(entries []string) (*IPSet, error) {
+	set := &IPSet{}
+
+	for _, entry := range entries {
+		trimmed := strings.TrimSpace(entry)
+		if trimmed == "" {
+			continue
+		}
+
+		table := &set.allow
+		if strings.HasPrefix(trimmed, "!") {
+			table = &set.deny
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "!"))
+		}
+
+		prefix, err := parsePrefix(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address or CIDR %q, %s", entry, err)
+		}
+		table.add(prefix)
+	}
+
+	if len(set.deny.prefixes) > 0 && len(set.allow.prefixes) == 0 {
+		return nil, errors.New("deny entries exclude addresses from the allow entries, at least one allow entry must be given")
+	}
+
+	return set, nil
+}
+
+// Contains returns true if the address is in an allow entry and not in any deny entry
+func (s *IPSet) Contains(address netip.Addr) bool {
+	if s == nil || !address.IsValid() {
+		return false
+	}
+
+	address = address.WithZone("").Unmap()
+	return s.allow.contains(address) && !s.deny.contains(address)
+}
+
+// ContainsString is the same as Contains for an address in the text form.
+// Invalid addresses are not contained in any set
+func (s *IPSet) ContainsString(address string) bool {
+	parsed, err := netip.ParseAddr(strings.TrimSpace(address))
+	if err != nil {
+		return false
+	}
+	return s.Contains(parsed)
+}
+
+// IsEmpty returns true if the set has no entries
+func (s *IPSet) IsEmpty() bool {
+	return s == nil || len(s.allow.prefixes) == 0
+}
+
+func parsePrefix(entry string) (netip.Prefix, error) {
+	var prefix netip.Prefix
+
+	if strings.Contains(entry, "/") {
+		parsed, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		prefix = parsed
+	} else {
+		address, err := netip.ParseAddr(entry)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		prefix = netip.PrefixFrom(address, address.BitLen())
+	}
+
+	// ::ffff:10.0.0.0/104 is the same range as 10.0.0.0/8
+	if prefix.Addr().Is4In6() {
+		if prefix.Bits() < 96 {
+			return netip.Prefix{}, errors.New("IPv4-mapped ranges must not be shorter than /96")
+		}
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+
+	return prefix.Masked(), nil
+}
+
+func (t *prefixTable) add(prefix netip.Prefix) {
+	if t.prefixes == nil {
+		t.prefixes = map[netip.Prefix]struct{}{}
+	}
+	if _, found := t.prefixes[prefix]; found {
+		return
+	}
+	t.prefixes[prefix] = struct{}{}
+
+	lengths := &t.lengths6
+	if prefix.Addr().Is4() {
+		lengths = &t.lengths4
+	}
+	for _, length := range *lengths {
+		if length == prefix.Bits() {
+			return
+		}
+	}
+	*lengths = append(*lengths, prefix.Bits())
+	sort.Sort(sort.Reverse(sort.IntSlice(*lengths)))
+}
+
+func (t *prefixTable) contains(address netip.Addr) bool {
+	lengths := t.lengths6
+	if address.Is4() {
+		lengths = t.lengths4
+	}
+
+	for _, length := range lengths {
+		prefix, err := address.Prefix(length)
+		if err != nil {
+			continue
+		}
+		if _, found := t.prefixes[prefix]; found {
+			return true
+		}
+	}
+
+	return false
+}
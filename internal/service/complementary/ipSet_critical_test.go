@@ -0,0 +1,120 @@
+package complementary
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPSet(t *testing.T) {
+	set, err := ParseIPSet([]string{"10.0.0.0/8", "!10.0.0.5", "!10.1.0.0/16", "192.168.1.10", "2001:db8::/32", "::ffff:172.16.0.0/108"})
+	assert.NoError(t, err)
+
+	type testCase struct {
+		name     string
+		address  string
+		expected bool
+	}
+
+	testCases := []testCase{
+		{
+			name:     "the address is in an allowed range. In this case, it should be contained",
+			address:  "10.20.30.40",
+			expected: true,
+		},
+		{
+			name:     "the address is denied explicitly. In this case, it should not be contained",
+			address:  "10.0.0.5",
+			expected: false,
+		},
+		{
+			name:     "the address is in a denied range inside an allowed range. In this case, it should not be contained",
+			address:  "10.1.2.3",
+			expected: false,
+		},
+		{
+			name:     "the address is an allowed absolute IP. In this case, it should be contained",
+			address:  "192.168.1.10",
+			expected: true,
+		},
+		{
+			name:     "the address is an IPv4-mapped IPv6 address of an allowed range. In this case, it should be contained",
+			address:  "::ffff:10.20.30.40",
+			expected: true,
+		},
+		{
+			name:     "the address is in an allowed IPv4-mapped range. In this case, it should match it as an IPv4 range",
+			address:  "172.16.5.5",
+			expected: true,
+		},
+		{
+			name:     "the address is in an allowed IPv6 range. In this case, it should be contained",
+			address:  "2001:db8:1::1",
+			expected: true,
+		},
+		{
+			name:     "the address is not in any range. In this case, it should not be contained",
+			address:  "192.168.1.11",
+			expected: false,
+		},
+		{
+			name:     "the address is not valid. In this case, it should not be contained",
+			address:  "10.0.0",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, set.ContainsString(tc.address))
+		})
+	}
+}
+
+func TestParseIPSet(t *testing.T) {
+	type testCase struct {
+		name    string
+		entries []string
+	}
+
+	testCases := []testCase{
+		{
+			name:    "an entry has a typo. In this case, it should be rejected",
+			entries: []string{"10.0.0.0/8", "192.168.1.1O"},
+		},
+		{
+			name:    "a CIDR is longer than the address. In this case, it should be rejected",
+			entries: []string{"10.0.0.0/33"},
+		},
+		{
+			name:    "there are only deny entries. In this case, it should be rejected since nothing could be contained",
+			entries: []string{"!10.0.0.5"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseIPSet(tc.entries)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func BenchmarkIPSetContains(b *testing.B) {
+	entries := make([]string, 0, 65536)
+	for index := 0; index < 65536; index++ {
+		entries = append(entries, fmt.Sprintf("10.%d.%d.0/24", index/256, index%256))
+	}
+	set, err := ParseIPSet(entries)
+	assert.NoError(b, err)
+
+	b.ResetTimer()
+	for index := 0; index < b.N; index++ {
+		set.ContainsString("10.200.100.7")
+	}
+}
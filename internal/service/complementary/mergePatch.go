@@ -0,0 +1,44 @@
+package complementary
+
+import "encoding/json"
+
+// MergePatch applies the JSON Merge Patch (RFC 7396) on the given JSON document and returns
+// the patched document. Objects of the patch are merged recursively, null values remove
+// the members and any other value replaces the target value
+func MergePatch(document []byte, patch []byte) ([]byte, error) {
+	var target interface{}
+	if len(document) > 0 {
+		if err := json.Unmarshal(document, &target); err != nil {
+			return nil, err
+		}
+	}
+
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergeValue(target, patchValue))
+}
+
+func mergeValue(target interface{}, patch interface{}) interface{} {
+	patchObject, isObject := patch.(map[string]interface{})
+	if !isObject {
+		return patch
+	}
+
+	targetObject, isObject := target.(map[string]interface{})
+	if !isObject {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+
+	return targetObject
+}
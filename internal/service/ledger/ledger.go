@@ -0,0 +1,374 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/organization"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EntityType is the type of the organization balances in the audit log. The entity id is the organization id
+const EntityType = "organization_balance"
+
+var (
+	// ErrInvalidAmount is returned when the amount of the operation is not acceptable
+	ErrInvalidAmount = errors.New("invalid amount")
+
+	// ErrMissingRequestKey is returned when the request key of the operation is not given
+	ErrMissingRequestKey = errors.New("request key must be provided")
+
+	// ErrRequestKeyConflict is returned when the request key is already used for another operation
+	ErrRequestKeyConflict = errors.New("request key is already used for another operation")
+
+	// ErrNegativeBalanceThreshold is returned when a debit or a reversal would take the balance
+	// below what the organization is allowed to
+	ErrNegativeBalanceThreshold = errors.New("insufficient balance")
+
+	// ErrNotReversible is returned when the transaction is a reversal or it is already reversed
+	ErrNotReversible = errors.New("transaction can not be reversed")
+)
+
+// posting describes how a transaction is posted to the ledger
+type posting struct {
+	Type                  string
+	Amount                models.Decimal // signed change of the organization balance
+	CounterAccount        string
+	ReversedTransactionID *string
+	EnforceThreshold      bool
+}
+
+// balanceSnapshot is the snapshot of the organization balance in the audit log. The transaction id
+// is the ledger transaction that changed the balance, it is only kept after the change
+type balanceSnapshot struct {
+	Balance       models.Money `json:"balance"`
+	TransactionID string       `json:"transaction_id,omitempty"`
+}
+
+// Credit tops up the balance of the organization by the given positive amount.
+// The returned bool is false if the request key was already used for the same operation
+// and the existing transaction is returned without changing the balance again
+func Credit(organizationID string, request models.BalanceOperationRequest, actor audit.Actor) (models.LedgerTransaction, bool, error) {
+	if !request.Amount.IsPositive() {
+		return models.LedgerTransaction{}, false, fmt.Errorf("%w: credit amount must be positive, given value is: %s", ErrInvalidAmount, request.Amount)
+	}
+
+	return post(organizationID, request, actor, posting{
+		Type:           models.LedgerCredit,
+		Amount:         request.Amount,
+		CounterAccount: models.LedgerAccountTopUp,
+	})
+}
+
+// Debit charges the balance of the organization by the given positive amount.
+// Debits that would take the organization over its negative balance threshold are rejected
+func Debit(organizationID string, request models.BalanceOperationRequest, actor audit.Actor) (models.LedgerTransaction, bool, error) {
+	if !request.Amount.IsPositive() {
+		return models.LedgerTransaction{}, false, fmt.Errorf("%w: debit amount must be positive, given value is: %s", ErrInvalidAmount, request.Amount)
+	}
+
+	return post(organizationID, request, actor, posting{
+		Type:             models.LedgerDebit,
+		Amount:           request.Amount.Neg(),
+		CounterAccount:   models.LedgerAccountCharges,
+		EnforceThreshold: true,
+	})
+}
+
+// Adjust corrects the balance of the organization by the given signed amount.
+// Adjustments are made by the operators, so the negative balance threshold is not enforced
+func Adjust(organizationID string, request models.BalanceOperationRequest, actor audit.Actor) (models.LedgerTransaction, bool, error) {
+	if request.Amount.IsZero() {
+		return models.LedgerTransaction{}, false, fmt.Errorf("%w: adjustment amount can not be zero", ErrInvalidAmount)
+	}
+
+	return post(organizationID, request, actor, posting{
+		Type:           models.LedgerAdjustment,
+		Amount:         request.Amount,
+		CounterAccount: models.LedgerAccountAdjustments,
+	})
+}
+
+// Reverse cancels the given transaction by posting its opposite. A transaction can only be
+// reversed once and reversals can not be reversed. The amount of the request is ignored.
+// Reversals that lower the balance, like the reversal of a credit, are rejected if they would take
+// the organization over its negative balance threshold
+func Reverse(organizationID string, transactionID string, request models.BalanceOperationRequest, actor audit.Actor) (models.LedgerTransaction, bool, error) {
+	return post(organizationID, request, actor, posting{
+		Type:                  models.LedgerReversal,
+		ReversedTransactionID: &transactionID,
+	})
+}
+
+// Transactions returns the ledger transactions of the organization, the latest first
+func Transactions(organizationID string) ([]models.LedgerTransactionResponse, error) {
+	transactions := []models.LedgerTransaction{}
+
+	err := cockroachdb.DB.Preload("Entries").
+		Where("organization_id = ?", organizationID).
+		Order("created_at DESC").
+		Find(&transactions).Error
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the ledger of organization id %s, error: %+v", organizationID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, errors.New(errorMessage)
+	}
+
+	response := []models.LedgerTransactionResponse{}
+	for index := range transactions {
+		response = append(response, Clean(&transactions[index]))
+	}
+
+	return response, nil
+}
+
+// Reconcile compares the stored balance of the organization with the sum of the entries
+// posted to its balance account
+func Reconcile(organizationID string) (models.BalanceReconciliation, error) {
+	var organizationDetails models.Organization
+	if err := cockroachdb.DB.First(&organizationDetails, "id = ?", organizationID).Error; err != nil {
+		return models.BalanceReconciliation{}, err
+	}
+
+	ledgerBalance, err := ledgerBalance(cockroachdb.DB, organizationID)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to calculate the ledger balance of organization id %s, error: %+v", organizationID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.BalanceReconciliation{}, errors.New(errorMessage)
+	}
+
+	difference, err := organizationDetails.Balance.Amount.Sub(ledgerBalance)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to compare the balance of organization id %s with its ledger, error: %+v", organizationID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.BalanceReconciliation{}, errors.New(errorMessage)
+	}
+
+	reconciliation := models.BalanceReconciliation{
+		OrganizationID: organizationID,
+		Currency:       organizationDetails.Balance.Currency,
+		Balance:        organizationDetails.Balance.Amount,
+		LedgerBalance:  ledgerBalance,
+		Difference:     difference,
+	}
+	reconciliation.Consistent = reconciliation.Difference.IsZero()
+
+	if !reconciliation.Consistent {
+		logger.OSPMLogger.Warnf("balance of organization id %s (%s) does not match its ledger (%s)",
+			organizationID, reconciliation.Balance, reconciliation.LedgerBalance)
+	}
+
+	return reconciliation, nil
+}
+
+// Clean removes the database related fields of the transaction
+func Clean(transaction *models.LedgerTransaction) models.LedgerTransactionResponse {
+	response := models.LedgerTransactionResponse{
+		ID:                    transaction.ID,
+		OrganizationID:        transaction.OrganizationID,
+		RequestKey:            transaction.RequestKey,
+		Type:                  transaction.Type,
+		Currency:              transaction.Currency,
+		Amount:                transaction.Amount,
+		BalanceAfter:          transaction.BalanceAfter,
+		Description:           transaction.Description,
+		ReversedTransactionID: transaction.ReversedTransactionID,
+		CreatedAt:             transaction.CreatedAt,
+		Entries:               []models.LedgerEntryResponse{},
+	}
+
+	for _, entry := range transaction.Entries {
+		response.Entries = append(response.Entries, models.LedgerEntryResponse{
+			Account: entry.Account,
+			Amount:  entry.Amount,
+		})
+	}
+
+	return response
+}
+
+// post applies the posting to the balance of the organization and records it in the ledger
+// and the audit log within a single transaction. The organization is locked so the concurrent operations
+// and the retries of the same request are serialized
+func post(organizationID string, request models.BalanceOperationRequest, actor audit.Actor, operation posting) (models.LedgerTransaction, bool, error) {
+	var transaction models.LedgerTransaction
+	created := false
+
+	if request.RequestKey == "" {
+		return transaction, false, ErrMissingRequestKey
+	}
+
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		var organizationDetails models.Organization
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&organizationDetails, "id = ?", organizationID).Error
+		if err != nil {
+			return err
+		}
+
+		// the request is a retry, so the result of the first attempt is returned
+		err = tx.Preload("Entries").
+			Where("organization_id = ? AND request_key = ?", organizationID, request.RequestKey).
+			First(&transaction).Error
+		if err == nil {
+			if !isSameOperation(&transaction, operation) {
+				return fmt.Errorf("%w: %s", ErrRequestKeyConflict, request.RequestKey)
+			}
+			return nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if operation.ReversedTransactionID != nil {
+			if err := prepareReversal(tx, organizationID, &operation); err != nil {
+				return err
+			}
+		}
+
+		if request.Currency != "" && request.Currency != organizationDetails.Balance.Currency {
+			return fmt.Errorf("%w: the balance of the organization is in %s, given currency is: %s",
+				models.ErrCurrencyMismatch, organizationDetails.Balance.Currency, request.Currency)
+		}
+
+		before := balanceSnapshot{Balance: organizationDetails.Balance}
+
+		if err := applyPosting(&organizationDetails, operation); err != nil {
+			return err
+		}
+
+		transaction = models.LedgerTransaction{
+			OrganizationID:        organizationID,
+			RequestKey:            request.RequestKey,
+			Type:                  operation.Type,
+			Currency:              organizationDetails.Balance.Currency,
+			Amount:                operation.Amount,
+			BalanceAfter:          organizationDetails.Balance.Amount,
+			Description:           request.Description,
+			ReversedTransactionID: operation.ReversedTransactionID,
+			Entries: []models.LedgerEntry{
+				{
+					OrganizationID: organizationID,
+					Account:        models.LedgerAccountOrganization,
+					Amount:         operation.Amount,
+				},
+				{
+					OrganizationID: organizationID,
+					Account:        operation.CounterAccount,
+					Amount:         operation.Amount.Neg(),
+				},
+			},
+		}
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&organizationDetails).Update("balance_amount", organizationDetails.Balance.Amount).Error; err != nil {
+			return err
+		}
+
+		after := balanceSnapshot{Balance: organizationDetails.Balance, TransactionID: transaction.ID}
+		if err := audit.Record(tx, actor, audit.ActionUpdate, EntityType, organizationID, before, after); err != nil {
+			return err
+		}
+
+		created = true
+		return nil
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to post %s of organization id %s with request key %s, error: %+v",
+			operation.Type, organizationID, request.RequestKey, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.LedgerTransaction{}, false, err
+	}
+
+	if created {
+		logger.OSPMLogger.Infof("%s of %s %s is posted to organization id %s, balance: %s",
+			transaction.Type, transaction.Amount, transaction.Currency, organizationID, transaction.BalanceAfter)
+	}
+
+	return transaction, created, nil
+}
+
+// prepareReversal fills the posting by the opposite of the transaction to reverse
+func prepareReversal(tx *gorm.DB, organizationID string, operation *posting) error {
+	var reversed models.LedgerTransaction
+	err := tx.Preload("Entries").
+		Where("organization_id = ? AND id = ?", organizationID, *operation.ReversedTransactionID).
+		First(&reversed).Error
+	if err != nil {
+		return err
+	}
+
+	if reversed.Type == models.LedgerReversal {
+		return fmt.Errorf("%w: %s is a reversal itself", ErrNotReversible, reversed.ID)
+	}
+
+	var reversalCount int64
+	err = tx.Model(&models.LedgerTransaction{}).Where("reversed_transaction_id = ?", reversed.ID).Count(&reversalCount).Error
+	if err != nil {
+		return err
+	}
+	if reversalCount > 0 {
+		return fmt.Errorf("%w: %s is already reversed", ErrNotReversible, reversed.ID)
+	}
+
+	reversalOf(&reversed, operation)
+	return nil
+}
+
+// reversalOf fills the posting by the opposite of the given transaction. The reversals that lower
+// the balance, like the reversal of a credit, are subject to the negative balance threshold as debits are
+func reversalOf(reversed *models.LedgerTransaction, operation *posting) {
+	operation.Amount = reversed.Amount.Neg()
+	operation.EnforceThreshold = operation.Amount.IsNegative()
+	for _, entry := range reversed.Entries {
+		if entry.Account != models.LedgerAccountOrganization {
+			operation.CounterAccount = entry.Account
+		}
+	}
+}
+
+// applyPosting changes the balance of the organization by the amount of the posting. It is rejected if the
+// balance would overflow or, when the posting enforces it, go over the negative balance threshold
+func applyPosting(organizationDetails *models.Organization, operation posting) error {
+	// a wrapped balance must never be stored, so the operations that overflow it are rejected
+	balance, err := organizationDetails.Balance.Amount.Add(operation.Amount)
+	if err != nil {
+		return fmt.Errorf("%w: the balance of the organization (%s) can not be changed by %s, %s",
+			ErrInvalidAmount, organizationDetails.Balance, operation.Amount, err)
+	}
+
+	organizationDetails.Balance.Amount = balance
+	if operation.EnforceThreshold && organization.IsOverNegativeBalanceThreshold(organizationDetails) {
+		return fmt.Errorf("%w: the balance of the organization would be %s", ErrNegativeBalanceThreshold, organizationDetails.Balance)
+	}
+
+	return nil
+}
+
+// isSameOperation determines whether the existing transaction was created by the same operation
+func isSameOperation(transaction *models.LedgerTransaction, operation posting) bool {
+	if transaction.Type != operation.Type {
+		return false
+	}
+
+	if operation.ReversedTransactionID != nil {
+		return transaction.ReversedTransactionID != nil && *transaction.ReversedTransactionID == *operation.ReversedTransactionID
+	}
+
+	return transaction.Amount == operation.Amount
+}
+
+func ledgerBalance(db *gorm.DB, organizationID string) (models.Decimal, error) {
+	var balance models.Decimal
+	err := db.Model(&models.LedgerEntry{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("organization_id = ? AND account = ?", organizationID, models.LedgerAccountOrganization).
+		Scan(&balance).Error
+
+	return balance, err
+}
@@ -0,0 +1,170 @@
+package ledger
+
+import (
+	"ospm/internal/models"
+	"ospm/internal/service/audit"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationValidation(t *testing.T) {
+	type testCase struct {
+		name          string
+		operation     func(string, models.BalanceOperationRequest, audit.Actor) (models.LedgerTransaction, bool, error)
+		request       models.BalanceOperationRequest
+		expectedError error
+	}
+
+	testCases := []testCase{
+		{
+			name:          "credit with negative amount is requested. In this case, it should be rejected",
+			operation:     Credit,
+			request:       models.BalanceOperationRequest{RequestKey: "key", Amount: models.NewDecimal(-10)},
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			name:          "debit with zero amount is requested. In this case, it should be rejected",
+			operation:     Debit,
+			request:       models.BalanceOperationRequest{RequestKey: "key"},
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			name:          "debit with negative amount is requested. In this case, it should be rejected",
+			operation:     Debit,
+			request:       models.BalanceOperationRequest{RequestKey: "key", Amount: models.NewDecimal(-10)},
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			name:          "adjustment with zero amount is requested. In this case, it should be rejected",
+			operation:     Adjust,
+			request:       models.BalanceOperationRequest{RequestKey: "key"},
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			name:          "credit without request key is requested. In this case, it should be rejected",
+			operation:     Credit,
+			request:       models.BalanceOperationRequest{Amount: models.NewDecimal(10)},
+			expectedError: ErrMissingRequestKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, created, err := tc.operation("ed83a2ba-c55c-4297-b2ac-df7b02abdd7a", tc.request, audit.Actor{})
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.False(t, created)
+		})
+	}
+}
+
+func TestIsSameOperation(t *testing.T) {
+	reversedID := "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+	otherID := "ed83a2ba-c55c-4297-b2ac-df7b02abdd7b"
+
+	type testCase struct {
+		name        string
+		transaction models.LedgerTransaction
+		operation   posting
+		expected    bool
+	}
+
+	testCases := []testCase{
+		{
+			name:        "the same credit is retried. In this case, it should be the same operation",
+			transaction: models.LedgerTransaction{Type: models.LedgerCredit, Amount: models.NewDecimal(10)},
+			operation:   posting{Type: models.LedgerCredit, Amount: models.NewDecimal(10)},
+			expected:    true,
+		},
+		{
+			name:        "a credit with another amount uses the same key. In this case, it should not be the same operation",
+			transaction: models.LedgerTransaction{Type: models.LedgerCredit, Amount: models.NewDecimal(10)},
+			operation:   posting{Type: models.LedgerCredit, Amount: models.NewDecimal(20)},
+			expected:    false,
+		},
+		{
+			name:        "a debit uses the key of a credit. In this case, it should not be the same operation",
+			transaction: models.LedgerTransaction{Type: models.LedgerCredit, Amount: models.NewDecimal(10)},
+			operation:   posting{Type: models.LedgerDebit, Amount: models.NewDecimal(-10)},
+			expected:    false,
+		},
+		{
+			name:        "the same reversal is retried. In this case, it should be the same operation",
+			transaction: models.LedgerTransaction{Type: models.LedgerReversal, Amount: models.NewDecimal(-10), ReversedTransactionID: &reversedID},
+			operation:   posting{Type: models.LedgerReversal, ReversedTransactionID: &reversedID},
+			expected:    true,
+		},
+		{
+			name:        "a reversal of another transaction uses the same key. In this case, it should not be the same operation",
+			transaction: models.LedgerTransaction{Type: models.LedgerReversal, Amount: models.NewDecimal(-10), ReversedTransactionID: &reversedID},
+			operation:   posting{Type: models.LedgerReversal, ReversedTransactionID: &otherID},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, isSameOperation(&tc.transaction, tc.operation))
+		})
+	}
+}
+
+func TestReversalThreshold(t *testing.T) {
+	reversedID := "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+
+	type testCase struct {
+		name            string
+		organization    models.Organization
+		reversed        models.LedgerTransaction
+		expectedBalance models.Decimal
+		expectedError   error
+	}
+
+	testCases := []testCase{
+		{
+			name: "a credit is reversed after the balance is spent and negative balance is not allowed. " +
+				"In this case, it should be rejected since the balance would be negative",
+			organization: models.Organization{Balance: models.NewMoney(models.NewDecimal(30), "IRR")},
+			reversed: models.LedgerTransaction{ID: reversedID, Type: models.LedgerCredit, Amount: models.NewDecimal(100), Entries: []models.LedgerEntry{
+				{Account: models.LedgerAccountOrganization, Amount: models.NewDecimal(100)},
+				{Account: models.LedgerAccountTopUp, Amount: models.NewDecimal(-100)},
+			}},
+			expectedError: ErrNegativeBalanceThreshold,
+		},
+		{
+			name: "a credit is reversed and the balance would go past the negative balance threshold. In this case, it should be rejected",
+			organization: models.Organization{
+				Balance:                  models.NewMoney(models.NewDecimal(30), "IRR"),
+				AllowNagativeBalance:     true,
+				NegativeBalanceThreshold: models.NewMoney(models.NewDecimal(50), "IRR"),
+			},
+			reversed:      models.LedgerTransaction{ID: reversedID, Type: models.LedgerCredit, Amount: models.NewDecimal(100)},
+			expectedError: ErrNegativeBalanceThreshold,
+		},
+		{
+			name:            "a debit is reversed while the balance is over the threshold. In this case, it should be allowed since it raises the balance",
+			organization:    models.Organization{Balance: models.NewMoney(models.NewDecimal(-80), "IRR")},
+			reversed:        models.LedgerTransaction{ID: reversedID, Type: models.LedgerDebit, Amount: models.NewDecimal(-20)},
+			expectedBalance: models.NewDecimal(-60),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			operation := posting{Type: models.LedgerReversal, ReversedTransactionID: &reversedID}
+			reversalOf(&tc.reversed, &operation)
+
+			err := applyPosting(&tc.organization, operation)
+			assert.ErrorIs(t, err, tc.expectedError)
+			if tc.expectedError == nil {
+				assert.Equal(t, tc.expectedBalance, tc.organization.Balance.Amount)
+			}
+		})
+	}
+}
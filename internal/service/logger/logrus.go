@@ -13,11 +13,7 @@ func InitLogger() {
 	OSPMLogger = logrus.New()
 
 	// Set the log level based on an environment variable
-	level, err := logrus.ParseLevel(config.OSPM.Logrus.LogLevel)
-	if err != nil {
-		level = logrus.InfoLevel
-	}
-	OSPMLogger.SetLevel(level)
+	SetLevel(config.Current().Logrus.LogLevel)
 
 	// Set output to stdout
 	OSPMLogger.SetOutput(os.Stdout)
@@ -27,3 +23,12 @@ func InitLogger() {
 		FullTimestamp: true,
 	})
 }
+
+// SetLevel changes the log level of the logger. Invalid levels are replaced by info
+func SetLevel(logLevel string) {
+	level, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		level = logrus.InfoLevel
+	}
+	OSPMLogger.SetLevel(level)
+}
@@ -0,0 +1,176 @@
+package organization
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository"
+	"ospm/internal/service/logger"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// ErrInvalidListRequest is returned when the filters, the sort, the limit or the cursor of the list are not valid
+var ErrInvalidListRequest = errors.New("invalid organization list request")
+
+// listCursor is the position of the last organization of a page. It is encoded as base64 JSON in the next cursor
+// and keeps the sort of the page, so the cursor can not be used with another sort
+type listCursor struct {
+	Sort  string `json:"sort"`
+	Value string `json:"value"`
+	ID    string `json:"id"`
+}
+
+// List returns a page of the organizations that match the filter in the order of the sort key.
+// The next page is requested by the next cursor of the page, which is empty on the last page
+func (s *Service) List(request models.OrganizationListRequest) (models.OrganizationPage, error) {
+	query, err := listQuery(request)
+	if err != nil {
+		return models.OrganizationPage{}, err
+	}
+	limit := query.Limit
+
+	total, err := s.repository.Count(request.Filter)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to count the organizations, error: %s", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.OrganizationPage{}, errors.New(errorMessage)
+	}
+
+	// one more organization is loaded to know whether there is a next page
+	query.Limit++
+	organizationList, err := s.repository.List(query)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to get list of organization, error: %s", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.OrganizationPage{}, errors.New(errorMessage)
+	}
+
+	page := models.OrganizationPage{Total: total}
+	if len(organizationList) > limit {
+		organizationList = organizationList[:limit]
+		page.NextCursor = encodeCursor(sortName(query), organizationList[limit-1], query.SortKey)
+	}
+	page.Items = Shorten(organizationList)
+
+	return page, nil
+}
+
+// listQuery validates the request and converts it to the query of the repository
+func listQuery(request models.OrganizationListRequest) (repository.OrganizationListQuery, error) {
+	query := repository.OrganizationListQuery{Filter: request.Filter, Limit: request.Limit}
+	filter := request.Filter
+
+	if query.Limit < 0 || query.Limit > MaxListLimit {
+		return query, fmt.Errorf("%w: limit should be between 1 and %d", ErrInvalidListRequest, MaxListLimit)
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultListLimit
+	}
+
+	query.SortKey = strings.TrimPrefix(request.Sort, "-")
+	query.Descending = strings.HasPrefix(request.Sort, "-")
+	switch query.SortKey {
+	case "":
+		query.SortKey = models.OrganizationSortCreatedAt
+	case models.OrganizationSortName, models.OrganizationSortCreatedAt, models.OrganizationSortBalance:
+	default:
+		return query, fmt.Errorf("%w: sort should be one of %s, %s and %s with an optional - prefix, given value is: %s",
+			ErrInvalidListRequest, models.OrganizationSortName, models.OrganizationSortCreatedAt, models.OrganizationSortBalance, request.Sort)
+	}
+
+	switch filter.Deleted {
+	case "", models.OrganizationDeletedExclude, models.OrganizationDeletedInclude, models.OrganizationDeletedOnly:
+	default:
+		return query, fmt.Errorf("%w: deleted should be one of %s, %s and %s, given value is: %s",
+			ErrInvalidListRequest, models.OrganizationDeletedExclude, models.OrganizationDeletedInclude, models.OrganizationDeletedOnly, filter.Deleted)
+	}
+
+	if filter.OwnerType != "" && filter.OwnerType != "legal" && filter.OwnerType != "individual" {
+		return query, fmt.Errorf("%w: owner type should be either individual or legal, given value is: %s", ErrInvalidListRequest, filter.OwnerType)
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && filter.CreatedTo.Before(*filter.CreatedFrom) {
+		return query, fmt.Errorf("%w: the end of the created range is before its start", ErrInvalidListRequest)
+	}
+	if filter.BalanceMin != nil && filter.BalanceMax != nil && filter.BalanceMax.LessThan(*filter.BalanceMin) {
+		return query, fmt.Errorf("%w: the maximum of the balance range is less than its minimum", ErrInvalidListRequest)
+	}
+
+	if request.Cursor != "" {
+		position, err := decodeCursor(request.Cursor, sortName(query), query.SortKey)
+		if err != nil {
+			return query, err
+		}
+		query.After = &position
+	}
+
+	return query, nil
+}
+
+// sortName returns the sort of the query as it is given in the request
+func sortName(query repository.OrganizationListQuery) string {
+	if query.Descending {
+		return "-" + query.SortKey
+	}
+	return query.SortKey
+}
+
+func encodeCursor(sort string, last models.Organization, sortKey string) string {
+	cursor := listCursor{Sort: sort, ID: last.ID}
+	switch sortKey {
+	case models.OrganizationSortName:
+		cursor.Value = last.Details.Name
+	case models.OrganizationSortCreatedAt:
+		cursor.Value = last.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case models.OrganizationSortBalance:
+		cursor.Value = last.Balance.Amount.String()
+	}
+
+	encoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeCursor(encoded string, sort string, sortKey string) (repository.OrganizationPosition, error) {
+	invalidCursor := fmt.Errorf("%w: the cursor is not valid, it should be the next cursor of the previous page", ErrInvalidListRequest)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return repository.OrganizationPosition{}, invalidCursor
+	}
+
+	var cursor listCursor
+	if err := json.Unmarshal(decoded, &cursor); err != nil || cursor.ID == "" {
+		return repository.OrganizationPosition{}, invalidCursor
+	}
+	if cursor.Sort != sort {
+		return repository.OrganizationPosition{}, fmt.Errorf("%w: the cursor belongs to the list sorted by %s, it can not be used with %s",
+			ErrInvalidListRequest, cursor.Sort, sort)
+	}
+
+	position := repository.OrganizationPosition{ID: cursor.ID}
+	switch sortKey {
+	case models.OrganizationSortName:
+		position.Value = cursor.Value
+	case models.OrganizationSortCreatedAt:
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return repository.OrganizationPosition{}, invalidCursor
+		}
+		position.Value = createdAt
+	case models.OrganizationSortBalance:
+		balance, err := models.ParseDecimal(cursor.Value)
+		if err != nil {
+			return repository.OrganizationPosition{}, invalidCursor
+		}
+		position.Value = balance
+	}
+
+	return position, nil
+}
@@ -5,76 +5,60 @@ import (
 	"fmt"
 	"ospm/config"
 	"ospm/internal/models"
-	"ospm/internal/repository/database/cockroachdb"
-	"ospm/internal/service/complementary"
+	"ospm/internal/repository"
+	"ospm/internal/service/audit"
 	"ospm/internal/service/logger"
-	"strings"
+	"time"
+
+	"gorm.io/gorm"
 )
 
-// List returns a list of organizations in shortened format.
-// Organizations that are hard deleted will not be listed
-func List() ([]models.OrganizationShortInfo, error) {
-	organizationList := []models.Organization{}
-	result := cockroachdb.DB.Preload("Details").Find(&organizationList)
-	if result.Error != nil {
-		errorMessage := fmt.Sprintf("failed to get list of organization, error: %s", result.Error)
-		logger.OSPMLogger.Errorln(errorMessage)
-		return nil, errors.New(errorMessage)
-	}
+// EntityType is the type of the organizations in the audit log
+const EntityType = "organization"
 
-	return Shorten(organizationList), nil
+// Service manages the organizations kept in its repository
+type Service struct {
+	repository repository.OrganizationRepository
 }
 
-// ListAll returns a list of organizations in shortened format.
-// Organizations that are hard deleted will be listed
-func ListAll() ([]models.OrganizationShortInfo, error) {
-	organizationList := []models.Organization{}
-	result := cockroachdb.DB.Unscoped().Preload("Details").Find(&organizationList)
-	if result.Error != nil {
-		errorMessage := fmt.Sprintf("failed to get list of organization, error: %s", result.Error)
-		logger.OSPMLogger.Errorln(errorMessage)
-		return nil, errors.New(errorMessage)
-	}
-
-	return Shorten(organizationList), nil
+// NewService returns a service that keeps the organizations in the given repository
+func NewService(organizationRepository repository.OrganizationRepository) *Service {
+	return &Service{repository: organizationRepository}
 }
 
 // Details gets the name of the desired organization name and returns the
 // details for the given name. Note that the accress credentials are hidden and to check the credentials
 // another endpoint should be called
-func Details(organizationName string, organizationID string) (models.Organization, error) {
-	var organization models.Organization
-
-	query := cockroachdb.DB.Preload("Details").Preload("Owner").
-		Joins("left join organization_details on organization_details.organization_id = organizations.id")
-
-	if organizationID != "" {
-		query = query.Where("organizations.id = ?", organizationID)
-	}
-	if organizationName != "" {
-		query = query.Where("organization_details.name = ?", organizationName)
-	}
-
-	err := query.First(&organization).Error
-	if err != nil {
-		return models.Organization{}, err
-	}
-
-	return organization, nil
-
+func (s *Service) Details(organizationName string, organizationID string) (models.Organization, error) {
+	return s.repository.Find(repository.OrganizationLookup{ID: organizationID, Name: organizationName})
 }
 
 // New gets the new organization details and adds it into the database then returns
 // the new added organization's ID. in case of any issue while adding the new organization
 // it returns an error
-func New(newOrganization models.Organization) (newOrganzationID string, err error) {
+func (s *Service) New(newOrganization models.Organization, actor audit.Actor) (newOrganzationID string, err error) {
 	if err := DetailsCheck(&newOrganization); err != nil {
 		errorMessage := fmt.Sprintf("the new organization can not be created, error: %+v", err)
 		logger.OSPMLogger.Error(errorMessage)
 		return "", errors.New(errorMessage)
 	}
 
-	if err := cockroachdb.DB.Create(&newOrganization).Error; err != nil {
+	// the balance and the threshold are always in the same currency
+	if newOrganization.Balance.Currency == "" {
+		newOrganization.Balance.Currency = newOrganization.NegativeBalanceThreshold.Currency
+	}
+	if newOrganization.Balance.Currency == "" {
+		newOrganization.Balance.Currency = config.Current().Billing.DefaultCurrency
+	}
+	newOrganization.NegativeBalanceThreshold.Currency = newOrganization.Balance.Currency
+
+	err = s.repository.Transaction(func(tx repository.OrganizationRepository) error {
+		if err := tx.Create(&newOrganization); err != nil {
+			return err
+		}
+		return audit.Append(tx, actor, audit.ActionCreate, EntityType, newOrganization.ID, nil, snapshot(&newOrganization))
+	})
+	if err != nil {
 		errorMessage := fmt.Sprintf("the new organization can not be created, error: %+v", err)
 		logger.OSPMLogger.Error(errorMessage)
 		return "", errors.New(errorMessage)
@@ -86,90 +70,39 @@ func New(newOrganization models.Organization) (newOrganzationID string, err erro
 // SoftDelete deletes the desired organization and does not impact
 // the other related entities like subscriber, permissions and etc.
 // The delete action happens in soft mode
-func SoftDelete(organizationID string, organizationName string) error {
-	var organization models.Organization
-
-	query := cockroachdb.DB.Joins("left join organization_details on organization_details.organization_id = organizations.id")
-
-	if organizationID != "" {
-		query = query.Where("organizations.id = ?", organizationID)
-	} else if organizationName != "" {
-		query = query.Where("organization_details.name = ?", organizationName)
-	}
-
-	err := query.First(&organization).Error
-	if err != nil {
-		errorMessage := fmt.Sprintf("failed to find organization to delete, error: %+v", err)
-		logger.OSPMLogger.Error(errorMessage)
-		return errors.New(errorMessage)
-	}
-
-	// Start a transaction
-	tx := cockroachdb.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	// Perform the delete operation with cascading deletes with HARD DELETE Enabled!
-	if err := tx.Select("Details", "Owner").Delete(&organization).Error; err != nil {
-		tx.Rollback()
-		errorMessage := fmt.Sprintf("failed to delete organization and related records, error: %+v", err)
-		logger.OSPMLogger.Error(errorMessage)
-		return errors.New(errorMessage)
-	}
-
-	// Commit the transaction
-	if err := tx.Commit().Error; err != nil {
-		errorMessage := fmt.Sprintf("failed to commit transaction, error: %+v", err)
-		logger.OSPMLogger.Error(errorMessage)
-		return errors.New(errorMessage)
-	}
-
-	return nil
+func (s *Service) SoftDelete(organizationID string, organizationName string, actor audit.Actor) error {
+	return s.delete(organizationID, organizationName, false, actor)
 }
 
 // HardDelete deletes the desired organization and does not impact
 // the other related entities like subscriber, permissions and etc.
 // The delete action happens in hard mode
-func HardDelete(organizationID string, organizationName string) error {
-	var organization models.Organization
-
-	query := cockroachdb.DB.Unscoped().Joins("left join organization_details on organization_details.organization_id = organizations.id")
-
-	if organizationID != "" {
-		query = query.Where("organizations.id = ?", organizationID)
-	} else if organizationName != "" {
-		query = query.Where("organization_details.name = ?", organizationName)
-	}
+func (s *Service) HardDelete(organizationID string, organizationName string, actor audit.Actor) error {
+	return s.delete(organizationID, organizationName, true, actor)
+}
 
-	err := query.First(&organization).Error
+func (s *Service) delete(organizationID string, organizationName string, hard bool, actor audit.Actor) error {
+	organization, err := s.repository.Find(lookup(organizationID, organizationName, hard))
 	if err != nil {
 		errorMessage := fmt.Sprintf("failed to find organization to delete, error: %+v", err)
 		logger.OSPMLogger.Error(errorMessage)
 		return errors.New(errorMessage)
 	}
 
-	// Start a transaction
-	tx := cockroachdb.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	// Perform the delete operation with cascading deletes with HARD DELETE Enabled!
-	if err := tx.Unscoped().Select("Details", "Owner").Delete(&organization).Error; err != nil {
-		tx.Rollback()
-		errorMessage := fmt.Sprintf("failed to delete organization and related records, error: %+v", err)
-		logger.OSPMLogger.Error(errorMessage)
-		return errors.New(errorMessage)
+	action := audit.ActionDelete
+	if hard {
+		action = audit.ActionHardDelete
 	}
 
-	// Commit the transaction
-	if err := tx.Commit().Error; err != nil {
-		errorMessage := fmt.Sprintf("failed to commit transaction, error: %+v", err)
+	err = s.repository.Transaction(func(tx repository.OrganizationRepository) error {
+		// the details and the owner are deleted with the organization
+		if err := tx.Delete(&organization, hard); err != nil {
+			return err
+		}
+		return audit.Append(tx, actor, action, EntityType, organization.ID, snapshot(&organization), nil)
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to delete organization and related records, error: %+v", err)
 		logger.OSPMLogger.Error(errorMessage)
 		return errors.New(errorMessage)
 	}
@@ -179,60 +112,54 @@ func HardDelete(organizationID string, organizationName string) error {
 
 // Recover truncates the deleted_at field from the database which
 // recovers the organization from soft delete
-func Recover(organizationID string, organizationName string) error {
-	var organization models.Organization
-
-	query := cockroachdb.DB.Unscoped().Joins("left join organization_details on organization_details.organization_id = organizations.id")
-
-	if organizationID != "" {
-		query = query.Where("organizations.id = ?", organizationID)
-	} else if organizationName != "" {
-		query = query.Where("organization_details.name = ?", organizationName)
-	}
-
-	err := query.First(&organization).Error
+func (s *Service) Recover(organizationID string, organizationName string, actor audit.Actor) error {
+	organization, err := s.repository.Find(lookup(organizationID, organizationName, true))
 	if err != nil {
 		errorMessage := fmt.Sprintf("failed to find organization to delete, error: %+v", err)
 		logger.OSPMLogger.Error(errorMessage)
 		return errors.New(errorMessage)
 	}
 
-	// Start the transaction
-	tx := cockroachdb.DB.Begin()
+	err = s.repository.Transaction(func(tx repository.OrganizationRepository) error {
+		// the details and the owner are recovered with the organization
+		if err := tx.Recover(organization.ID); err != nil {
+			return err
+		}
 
-	// Restore the organization
-	if err := tx.Unscoped().Model(&models.Organization{}).Where("id = ?", organization.ID).Update("deleted_at", nil).Error; err != nil {
-		tx.Rollback()
+		before := snapshot(&organization)
+		organization.DeletedAt = gorm.DeletedAt{}
+		organization.Details.DeletedAt = gorm.DeletedAt{}
+		organization.Owner.DeletedAt = gorm.DeletedAt{}
+		return audit.Append(tx, actor, audit.ActionRecover, EntityType, organization.ID, before, snapshot(&organization))
+	})
+	if err != nil {
 		errorMessage := fmt.Sprintf("failed to recover organization from soft delete, error: %+v", err)
 		logger.OSPMLogger.Error(errorMessage)
 		return errors.New(errorMessage)
 	}
 
-	// Restore organization details
-	if err := tx.Unscoped().Model(&models.OrganizationDetails{}).Where("organization_id = ?", organization.ID).Update("deleted_at", nil).Error; err != nil {
-		tx.Rollback()
-		errorMessage := fmt.Sprintf("failed to recover organization from soft delete, error: %+v", err)
-		logger.OSPMLogger.Error(errorMessage)
-		return errors.New(errorMessage)
-	}
+	return nil
+}
 
-	// Restore related owner
-	if err := tx.Unscoped().Model(&models.OrganizationOwner{}).Where("organization_id = ?", organization.ID).Update("deleted_at", nil).Error; err != nil {
-		tx.Rollback()
-		errorMessage := fmt.Sprintf("failed to recover organization from soft delete, error: %+v", err)
-		logger.OSPMLogger.Error(errorMessage)
-		return errors.New(errorMessage)
+// lookup identifies the organization to delete or recover by its id, or by its name if the id is not given
+func lookup(organizationID string, organizationName string, unscoped bool) repository.OrganizationLookup {
+	if organizationID != "" {
+		return repository.OrganizationLookup{ID: organizationID, Unscoped: unscoped}
 	}
+	return repository.OrganizationLookup{Name: organizationName, Unscoped: unscoped}
+}
 
-	// Commit the transaction
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback()
-		errorMessage := fmt.Sprintf("failed to recover organization from soft delete, error: %+v", err)
-		logger.OSPMLogger.Error(errorMessage)
-		return errors.New(errorMessage)
+// snapshot is the state of the organization that is kept in the audit log
+func snapshot(organization *models.Organization) interface{} {
+	var deletedAt *time.Time
+	if organization.DeletedAt.Valid {
+		deletedAt = &organization.DeletedAt.Time
 	}
 
-	return nil
+	return struct {
+		models.OrganizationResponse
+		DeletedAt *time.Time `json:"deleted_at"`
+	}{Clean(organization), deletedAt}
 }
 
 // Shorten gets a list of organizations and returns a list of organizations just including
@@ -243,6 +170,13 @@ func Shorten(organizations []models.Organization) []models.OrganizationShortInfo
 		shortInfo := models.OrganizationShortInfo{}
 		shortInfo.ID = organization.ID
 		shortInfo.Name = organization.Details.Name
+		shortInfo.OwnerType = organization.Owner.Type
+		shortInfo.Balance = organization.Balance
+		shortInfo.CreatedAt = organization.CreatedAt
+		if organization.DeletedAt.Valid {
+			deletedAt := organization.DeletedAt.Time
+			shortInfo.DeletedAt = &deletedAt
+		}
 		shortList = append(shortList, shortInfo)
 	}
 
@@ -255,10 +189,10 @@ func Shorten(organizations []models.Organization) []models.OrganizationShortInfo
 func DetailsCheck(organizationDetails *models.Organization) error {
 	var err error
 
-	if organizationDetails.Balance != 0 {
+	if !organizationDetails.Balance.Amount.IsZero() {
 		errorMessage := fmt.Sprintf(
-			"organization balance can not accept any values but 0 while creating the organization. given value is: %f",
-			organizationDetails.Balance)
+			"organization balance can not accept any values but 0 while creating the organization. given value is: %s",
+			organizationDetails.Balance.Amount)
 		err = errors.New(errorMessage)
 	}
 
@@ -269,53 +203,97 @@ func DetailsCheck(organizationDetails *models.Organization) error {
 		err = errors.New(errorMessage)
 	}
 
-	if organizationDetails.NegativeBalanceThreshold != 0 {
+	if !organizationDetails.NegativeBalanceThreshold.Amount.IsZero() {
+		errorMessage := fmt.Sprintf(
+			"organization NegativeBalanceThreshold can not accept any values but 0 while creating the organization. given value is: %s",
+			organizationDetails.NegativeBalanceThreshold.Amount)
+		err = errors.New(errorMessage)
+	}
+
+	// the currency is optional, the default currency is used if it is not given
+	for _, currency := range []string{organizationDetails.Balance.Currency, organizationDetails.NegativeBalanceThreshold.Currency} {
+		if currency != "" && !models.IsValidCurrency(currency) {
+			errorMessage := fmt.Sprintf(
+				"organization currency should be an ISO-4217 currency code like IRR or USD. given value is: %s",
+				currency)
+			err = errors.New(errorMessage)
+		}
+	}
+
+	if organizationDetails.Balance.Currency != "" && organizationDetails.NegativeBalanceThreshold.Currency != "" &&
+		organizationDetails.Balance.Currency != organizationDetails.NegativeBalanceThreshold.Currency {
 		errorMessage := fmt.Sprintf(
-			"organization NegativeBalanceThreshold can not accept any values but 0 while creating the organization. given value is: %f",
-			organizationDetails.NegativeBalanceThreshold)
+			"organization balance and NegativeBalanceThreshold should be in the same currency. given values are: %s and %s",
+			organizationDetails.Balance.Currency, organizationDetails.NegativeBalanceThreshold.Currency)
 		err = errors.New(errorMessage)
 	}
 
+	if profileErr := profileCheck(organizationDetails, "creating"); profileErr != nil {
+		err = profileErr
+	}
+
+	if err != nil {
+		errorMessage := fmt.Sprintf("new organization details are wrong. error: %+v", err)
+		return errors.New(errorMessage)
+	}
+	return nil
+}
+
+// profileCheck validates the profile of the organization, its details and its owner.
+// The rules are shared by the creation and the profile update of the organization,
+// the operation is only used in the error messages
+func profileCheck(organizationDetails *models.Organization, operation string) error {
+	var err error
+
 	if organizationDetails.Details.Name == "" {
 		errorMessage := fmt.Sprintf(
-			"organization Name can not be empty while creating the organization. given value is: %s",
-			organizationDetails.Details.Name)
+			"organization Name can not be empty while %s the organization. given value is: %s",
+			operation, organizationDetails.Details.Name)
 		err = errors.New(errorMessage)
 	}
 
 	if organizationDetails.Owner.Email == "" {
 		errorMessage := fmt.Sprintf(
-			"organization's Owner email address can not be empty while creating the organization. given value is: %s",
-			organizationDetails.Owner.Email)
+			"organization's Owner email address can not be empty while %s the organization. given value is: %s",
+			operation, organizationDetails.Owner.Email)
 		err = errors.New(errorMessage)
 	}
 
 	if organizationDetails.Owner.Mobile == "" {
 		errorMessage := fmt.Sprintf(
-			"organization's Owner Mobile can not be empty while creating the organization. given value is: %s",
-			organizationDetails.Owner.Mobile)
+			"organization's Owner Mobile can not be empty while %s the organization. given value is: %s",
+			operation, organizationDetails.Owner.Mobile)
 		err = errors.New(errorMessage)
 	}
 
 	if !(organizationDetails.Owner.Type == "legal" || organizationDetails.Owner.Type == "individual") {
 		errorMessage := fmt.Sprintf(
-			"organization's Owner typ should be either individual or legal while creating the organization. given value is: %s",
-			organizationDetails.Owner.Type)
+			"organization's Owner typ should be either individual or legal while %s the organization. given value is: %s",
+			operation, organizationDetails.Owner.Type)
 		err = errors.New(errorMessage)
 	}
 
 	if organizationDetails.Owner.LegalNationalID == "" {
 		errorMessage := fmt.Sprintf(
-			"organization's Owner Legal National ID can not be empty while creating the organization. given value is: %s",
-			organizationDetails.Owner.LegalNationalID)
+			"organization's Owner Legal National ID can not be empty while %s the organization. given value is: %s",
+			operation, organizationDetails.Owner.LegalNationalID)
 		err = errors.New(errorMessage)
 	}
 
-	if err != nil {
-		errorMessage := fmt.Sprintf("new organization details are wrong. error: %+v", err)
-		return errors.New(errorMessage)
+	return err
+}
+
+// IsOverNegativeBalanceThreshold determines whether the balance of the given organization has gone
+// below what it is allowed to. Organizations that are not allowed to have negative balance are over
+// the threshold as soon as their balance is negative. Otherwise, the NegativeBalanceThreshold determines
+// how much debt is allowed. UpdateBalancePolicy rejects negative thresholds, the sign is only ignored
+// for the thresholds that were stored before it did
+func IsOverNegativeBalanceThreshold(organization *models.Organization) bool {
+	if !organization.AllowNagativeBalance {
+		return organization.Balance.Amount.IsNegative()
 	}
-	return nil
+
+	return organization.Balance.Amount.LessThan(organization.NegativeBalanceThreshold.Amount.Abs().Neg())
 }
 
 // Clean can be used to remove database related items from the results returned from the
@@ -345,60 +323,3 @@ func Clean(organization *models.Organization) models.OrganizationResponse {
 	}
 
 }
-
-// ClientIPCanSoftDeleteOrganization gets the client's IP and checks it among
-// the permited IPs. If the client's ip is whitelisted, returns true
-func ClientIPCanSoftDeleteOrganization(clientIP string) bool {
-
-	// Check if the client's IP is in the allowed list or ranges
-	for _, allowedIP := range strings.Split(config.OSPM.ClientPolicies.OrganizationSoftDeleteWhiteListedIPs, ",") {
-		if complementary.IPRangeCotains(clientIP, allowedIP) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// ClientIPCanSoftDeleteOrganization gets the client's IP and checks it among
-// the permited IPs. If the client's ip is whitelisted, returns true
-func ClientIPCanHardDeleteOrganization(clientIP string) bool {
-
-	// Check if the client's IP is in the allowed list or ranges
-	for _, allowedIP := range strings.Split(config.OSPM.ClientPolicies.OrganizationHardDeleteWhiteListedIPs, ",") {
-		if complementary.IPRangeCotains(clientIP, allowedIP) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// ClientIPCanListAllOrganization gets the client's IP and checks it among
-// the permited IPs. If the client's ip is whitelisted, returns true
-// whitelisted ips can list all of the organizations including soft deleteds
-func ClientIPCanListAllOrganization(clientIP string) bool {
-
-	// Check if the client's IP is in the allowed list or ranges
-	for _, allowedIP := range strings.Split(config.OSPM.ClientPolicies.ListAllOrganizationWhiteListedIPs, ",") {
-		if complementary.IPRangeCotains(clientIP, allowedIP) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// ClientIPCanUndoOrganizationSoftDelete gets the client ip and checks it
-// among permitted IPs. If the client's ip is whitelisted, returns true
-func ClientIPCanUndoOrganizationSoftDelete(clientIP string) bool {
-
-	// Check if the client's IP is in the allowed list or ranges
-	for _, allowedIP := range strings.Split(config.OSPM.ClientPolicies.UndoOrganizationSoftDeleteWhiteListedIPs, ",") {
-		if complementary.IPRangeCotains(clientIP, allowedIP) {
-			return true
-		}
-	}
-
-	return false
-}
@@ -2,188 +2,12 @@ package organization
 
 import (
 	"errors"
-	"ospm/config"
 	"ospm/internal/models"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func TestClientIPCanListAllOrganization(t *testing.T) {
-	type testCase struct {
-		name           string
-		whitelist      string
-		clientIP       string
-		expectedResult bool
-	}
-
-	testCases := []testCase{
-		{
-			name:           "client ip exists in the whitelist but whitelist is using absolute IP instead of ip range. In this case, the output should be true",
-			whitelist:      "172.16.1.5/32,192.168.1.12",
-			clientIP:       "192.168.1.12",
-			expectedResult: true,
-		},
-		{
-			name:           "client ip does not exist in the whitelist. In this case, the output should be false",
-			whitelist:      "172.16.1.5/32,192.168.1.13/32",
-			clientIP:       "192.168.1.12",
-			expectedResult: false,
-		},
-		{
-			name:           "whitelist is empty. In this case, the output should be false",
-			whitelist:      "",
-			clientIP:       "192.168.1.12",
-			expectedResult: false,
-		},
-	}
-
-	config.LoadOSPMConfigs()
-
-	for _, tc := range testCases {
-		// ensures that each subtest gets its own copy of the test case.
-		// This is important because the loop variable tc would otherwise be shared across all goroutines,
-		// leading to race conditions and incorrect test results
-		tc := tc // capture range variable
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-			config.OSPM.ClientPolicies.ListAllOrganizationWhiteListedIPs = tc.whitelist
-			output := ClientIPCanListAllOrganization(tc.clientIP)
-
-			assert.Equal(t, tc.expectedResult, output)
-		})
-	}
-}
-
-func TestClientIPCanUndoOrganizationSoftDelete(t *testing.T) {
-	type testCase struct {
-		name           string
-		whitelist      string
-		clientIP       string
-		expectedResult bool
-	}
-
-	testCases := []testCase{
-		{
-			name:           "client ip exists in the whitelist but whitelist is using absolute IP instead of ip range. In this case, the output should be true",
-			whitelist:      "172.16.1.5/32,192.168.1.12",
-			clientIP:       "192.168.1.12",
-			expectedResult: true,
-		},
-		{
-			name:           "client ip does not exist in the whitelist. In this case, the output should be false",
-			whitelist:      "172.16.1.5/32,192.168.1.13/32",
-			clientIP:       "192.168.1.12",
-			expectedResult: false,
-		},
-		{
-			name:           "whitelist is empty. In this case, the output should be false",
-			whitelist:      "",
-			clientIP:       "192.168.1.12",
-			expectedResult: false,
-		},
-	}
-
-	config.LoadOSPMConfigs()
-
-	for _, tc := range testCases {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-			config.OSPM.ClientPolicies.UndoOrganizationSoftDeleteWhiteListedIPs = tc.whitelist
-			testResult := ClientIPCanUndoOrganizationSoftDelete(tc.clientIP)
-			assert.Equal(t, tc.expectedResult, testResult)
-		})
-	}
-
-}
-
-func TestClientIPCanHardDeleteOrganization(t *testing.T) {
-	type testCase struct {
-		name           string
-		whitelist      string
-		clientIP       string
-		expectedResult bool
-	}
-
-	testCases := []testCase{
-		{
-			name:           "client ip exists in the whitelist but whitelist is using absolute IP instead of ip range. In this case, the output should be true",
-			whitelist:      "172.16.1.5/32,192.168.1.12",
-			clientIP:       "192.168.1.12",
-			expectedResult: true,
-		},
-		{
-			name:           "client ip does not exist in the whitelist. In this case, the output should be false",
-			whitelist:      "172.16.1.5/32,192.168.1.13/32",
-			clientIP:       "192.168.1.12",
-			expectedResult: false,
-		},
-		{
-			name:           "whitelist is empty. In this case, the output should be false",
-			whitelist:      "",
-			clientIP:       "192.168.1.12",
-			expectedResult: false,
-		},
-	}
-
-	config.LoadOSPMConfigs()
-	for _, tc := range testCases {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-			config.OSPM.ClientPolicies.OrganizationHardDeleteWhiteListedIPs = tc.whitelist
-			testResult := ClientIPCanHardDeleteOrganization(tc.clientIP)
-			assert.Equal(t, tc.expectedResult, testResult)
-		})
-
-	}
-
-}
-
-func TestClientIPCanSoftDeleteOrganization(t *testing.T) {
-	type testCase struct {
-		name           string
-		whitelist      string
-		clientIP       string
-		expectedResult bool
-	}
-
-	testCases := []testCase{
-		{
-			name:           "client ip exists in the whitelist but whitelist is using absolute IP instead of ip range. In this case, the output should be true",
-			whitelist:      "172.16.1.5/32,192.168.1.12",
-			clientIP:       "192.168.1.12",
-			expectedResult: true,
-		},
-		{
-			name:           "client ip does not exist in the whitelist. In this case, the output should be false",
-			whitelist:      "172.16.1.5/32,192.168.1.13/32",
-			clientIP:       "192.168.1.12",
-			expectedResult: false,
-		},
-		{
-			name:           "whitelist is empty. In this case, the output should be false",
-			whitelist:      "",
-			clientIP:       "192.168.1.12",
-			expectedResult: false,
-		},
-	}
-
-	config.LoadOSPMConfigs()
-
-	for _, tc := range testCases {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-			config.OSPM.ClientPolicies.OrganizationSoftDeleteWhiteListedIPs = tc.whitelist
-			testResult := ClientIPCanSoftDeleteOrganization(tc.clientIP)
-			assert.Equal(t, tc.expectedResult, testResult)
-		})
-	}
-}
-
-// x
 func TestDetailsCheck(t *testing.T) {
 	type testCase struct {
 		name                string
@@ -212,9 +36,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  0.0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -237,9 +61,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  -100.0,
+				Balance:                  models.NewMoney(models.NewDecimal(-100), "IRR"),
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -262,9 +86,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -287,9 +111,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  -100,
+				Balance:                  models.NewMoney(models.NewDecimal(-100), "IRR"),
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -312,9 +136,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  100,
+				Balance:                  models.NewMoney(models.NewDecimal(100), "IRR"),
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -337,9 +161,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  100.0,
+				Balance:                  models.NewMoney(models.NewDecimal(100), "IRR"),
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -362,9 +186,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  0.0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     true,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -387,9 +211,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  0.0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -412,9 +236,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  0.0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: -100,
+				NegativeBalanceThreshold: models.NewMoney(models.NewDecimal(-100), "IRR"),
 			},
 		},
 		{
@@ -437,9 +261,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  0.0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -462,9 +286,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  0.0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -487,9 +311,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  0.0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -512,9 +336,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  0.0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -537,9 +361,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "AB1234562",
 				},
-				Balance:                  0.0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 		{
@@ -562,9 +386,9 @@ func TestDetailsCheck(t *testing.T) {
 					Phone:           "1234567891",
 					LegalNationalID: "",
 				},
-				Balance:                  0.0,
+				Balance:                  models.Money{},
 				AllowNagativeBalance:     false,
-				NegativeBalanceThreshold: 0.0,
+				NegativeBalanceThreshold: models.Money{},
 			},
 		},
 	}
@@ -583,3 +407,120 @@ func TestDetailsCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestIsOverNegativeBalanceThreshold(t *testing.T) {
+	type testCase struct {
+		name           string
+		organization   models.Organization
+		expectedResult bool
+	}
+
+	testCases := []testCase{
+		{
+			name:           "negative balance is not allowed and the balance is positive. In this case, the output should be false",
+			organization:   models.Organization{Balance: models.NewMoney(models.NewDecimal(10), "IRR"), AllowNagativeBalance: false},
+			expectedResult: false,
+		},
+		{
+			name:           "negative balance is not allowed and the balance is negative. In this case, the output should be true",
+			organization:   models.Organization{Balance: models.NewMoney(models.MustParseDecimal("-0.5"), "IRR"), AllowNagativeBalance: false},
+			expectedResult: true,
+		},
+		{
+			name:           "negative balance is allowed and the balance is within the threshold. In this case, the output should be false",
+			organization:   models.Organization{Balance: models.NewMoney(models.NewDecimal(-50), "IRR"), AllowNagativeBalance: true, NegativeBalanceThreshold: models.NewMoney(models.NewDecimal(100), "IRR")},
+			expectedResult: false,
+		},
+		{
+			name:           "negative balance is allowed and the balance is below the threshold. In this case, the output should be true",
+			organization:   models.Organization{Balance: models.NewMoney(models.NewDecimal(-150), "IRR"), AllowNagativeBalance: true, NegativeBalanceThreshold: models.NewMoney(models.NewDecimal(100), "IRR")},
+			expectedResult: true,
+		},
+		{
+			name:           "the threshold is given as a negative value. In this case, its absolute value should be considered",
+			organization:   models.Organization{Balance: models.NewMoney(models.NewDecimal(-50), "IRR"), AllowNagativeBalance: true, NegativeBalanceThreshold: models.NewMoney(models.NewDecimal(-100), "IRR")},
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedResult, IsOverNegativeBalanceThreshold(&tc.organization))
+		})
+	}
+}
+
+func TestApplyProfilePatch(t *testing.T) {
+	newOrganization := func() models.Organization {
+		return models.Organization{
+			Details: models.OrganizationDetails{Name: "ario", Address: "Tehran", Email: "info@ario.com", Mobile: "09120000000", Phone: "02100000000"},
+			Owner: models.OrganizationOwner{
+				Type: "legal", Name: "ario owner", Email: "owner@ario.com", Mobile: "09120000001", LegalNationalID: "1234567890",
+			},
+			Balance: models.NewMoney(models.NewDecimal(10), "IRR"),
+		}
+	}
+
+	type testCase struct {
+		name            string
+		patch           string
+		expectedDetails models.OrganizationDetails
+		expectedOwner   models.OrganizationOwner
+		expectedError   error
+	}
+
+	testCases := []testCase{
+		{
+			name:  "only the name and the owner mobile are given. In this case, the other fields should be kept",
+			patch: `{"organization_details": {"name": "ario2"}, "organization_owner": {"mobile": "09120000002"}}`,
+			expectedDetails: models.OrganizationDetails{
+				Name: "ario2", Address: "Tehran", Email: "info@ario.com", Mobile: "09120000000", Phone: "02100000000",
+			},
+			expectedOwner: models.OrganizationOwner{
+				Type: "legal", Name: "ario owner", Email: "owner@ario.com", Mobile: "09120000002", LegalNationalID: "1234567890",
+			},
+		},
+		{
+			name:  "the phone is set to null. In this case, the phone should be cleared",
+			patch: `{"organization_details": {"phone": null}}`,
+			expectedDetails: models.OrganizationDetails{
+				Name: "ario", Address: "Tehran", Email: "info@ario.com", Mobile: "09120000000",
+			},
+			expectedOwner: newOrganization().Owner,
+		},
+		{
+			name:          "the balance is given in the patch. In this case, it should be rejected",
+			patch:         `{"balance": {"amount": "1000"}}`,
+			expectedError: ErrInvalidPatch,
+		},
+		{
+			name:          "an unknown field of the details is given. In this case, it should be rejected",
+			patch:         `{"organization_details": {"website": "ario.com"}}`,
+			expectedError: ErrInvalidPatch,
+		},
+		{
+			name:          "the patch is not a JSON object. In this case, it should be rejected",
+			patch:         `["name"]`,
+			expectedError: ErrInvalidPatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			organization := newOrganization()
+			err := ApplyProfilePatch(&organization, []byte(tc.patch))
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedDetails, organization.Details)
+			assert.Equal(t, tc.expectedOwner, organization.Owner)
+			assert.Equal(t, models.NewMoney(models.NewDecimal(10), "IRR"), organization.Balance)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package organization
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/complementary"
+	"ospm/internal/service/logger"
+)
+
+var (
+	// ErrInvalidPatch is returned when the profile patch is not a valid JSON Merge Patch
+	// or it contains fields that can not be changed by the profile update
+	ErrInvalidPatch = errors.New("invalid organization profile patch")
+
+	// ErrInvalidProfile is returned when the patched profile does not meet the organization rules
+	ErrInvalidProfile = errors.New("invalid organization profile")
+
+	// ErrConflict is returned when a unique field of the profile is already used by another organization
+	ErrConflict = errors.New("organization profile conflicts with another organization")
+
+	// ErrInvalidBalancePolicy is returned when the negative balance threshold is negative
+	// or it is not in the currency of the organization balance
+	ErrInvalidBalancePolicy = errors.New("invalid organization balance policy")
+)
+
+// profileDocument is the document that the profile patches are applied on
+type profileDocument struct {
+	Details models.OrganizationDetailsResponse `json:"organization_details"`
+	Owner   models.OrganizationOwnerResponse   `json:"organization_owner"`
+}
+
+// UpdateProfile applies the JSON Merge Patch on the details and the owner of the organization.
+// The patched profile must meet the same rules as a new organization and its unique fields
+// must not be used by any other organization, including the soft deleted ones
+func (s *Service) UpdateProfile(organizationID string, organizationName string, patch []byte, actor audit.Actor) (models.OrganizationResponse, error) {
+	var organization models.Organization
+
+	err := s.repository.Transaction(func(tx repository.OrganizationRepository) error {
+		var err error
+		if organization, err = lockProfile(tx, organizationID, organizationName); err != nil {
+			return err
+		}
+		before := snapshot(&organization)
+
+		if err := ApplyProfilePatch(&organization, patch); err != nil {
+			return err
+		}
+
+		if err := profileCheck(&organization, "updating"); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidProfile, err)
+		}
+
+		if err := uniqueProfileCheck(tx, &organization); err != nil {
+			return err
+		}
+
+		if err := tx.UpdateProfile(&organization); err != nil {
+			return err
+		}
+
+		return audit.Append(tx, actor, audit.ActionUpdate, EntityType, organization.ID, before, snapshot(&organization))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to update the profile of organization %s %s, error: %+v", organizationID, organizationName, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.OrganizationResponse{}, err
+	}
+
+	logger.OSPMLogger.Infof("profile of organization id %s is updated", organization.ID)
+	return Clean(&organization), nil
+}
+
+// UpdateBalancePolicy changes whether the organization is allowed to have negative balance
+// and how much. It is kept apart from the profile update since it is a privileged operation
+func (s *Service) UpdateBalancePolicy(organizationID string, organizationName string, request models.BalancePolicyRequest, actor audit.Actor) (models.OrganizationResponse, error) {
+	var organization models.Organization
+
+	if request.NegativeBalanceThreshold != nil && request.NegativeBalanceThreshold.IsNegative() {
+		return models.OrganizationResponse{}, fmt.Errorf("%w: negative balance threshold is how far below zero the balance may go "+
+			"and it can not be negative, given value is: %s", ErrInvalidBalancePolicy, request.NegativeBalanceThreshold)
+	}
+
+	err := s.repository.Transaction(func(tx repository.OrganizationRepository) error {
+		var err error
+		if organization, err = lockProfile(tx, organizationID, organizationName); err != nil {
+			return err
+		}
+		before := snapshot(&organization)
+
+		if request.Currency != "" && request.Currency != organization.Balance.Currency {
+			return fmt.Errorf("%w: the balance of the organization is in %s, given currency is: %s",
+				ErrInvalidBalancePolicy, organization.Balance.Currency, request.Currency)
+		}
+
+		if request.AllowNagativeBalance != nil {
+			organization.AllowNagativeBalance = *request.AllowNagativeBalance
+		}
+		if request.NegativeBalanceThreshold != nil {
+			organization.NegativeBalanceThreshold = models.NewMoney(*request.NegativeBalanceThreshold, organization.Balance.Currency)
+		}
+
+		if err := tx.UpdateBalancePolicy(&organization); err != nil {
+			return err
+		}
+
+		return audit.Append(tx, actor, audit.ActionUpdate, EntityType, organization.ID, before, snapshot(&organization))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to update the balance policy of organization %s %s, error: %+v", organizationID, organizationName, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.OrganizationResponse{}, err
+	}
+
+	logger.OSPMLogger.Infof("balance policy of organization id %s is updated. allow negative balance: %v, threshold: %s",
+		organization.ID, organization.AllowNagativeBalance, organization.NegativeBalanceThreshold)
+	return Clean(&organization), nil
+}
+
+// ApplyProfilePatch applies the JSON Merge Patch on the details and the owner of the given organization.
+// The patch can only contain the organization_details and the organization_owner objects
+func ApplyProfilePatch(organization *models.Organization, patch []byte) error {
+	var patchObject map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &patchObject); err != nil {
+		return fmt.Errorf("%w: the patch must be a JSON object, %s", ErrInvalidPatch, err)
+	}
+	for key := range patchObject {
+		if key != "organization_details" && key != "organization_owner" {
+			return fmt.Errorf("%w: %s can not be changed by the profile update", ErrInvalidPatch, key)
+		}
+	}
+
+	cleaned := Clean(organization)
+	document, err := json.Marshal(profileDocument{Details: cleaned.Details, Owner: cleaned.Owner})
+	if err != nil {
+		return err
+	}
+
+	patchedDocument, err := complementary.MergePatch(document, patch)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+
+	var patched profileDocument
+	decoder := json.NewDecoder(bytes.NewReader(patchedDocument))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patched); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+
+	organization.Details.Name = patched.Details.Name
+	organization.Details.Address = patched.Details.Address
+	organization.Details.Email = patched.Details.Email
+	organization.Details.Mobile = patched.Details.Mobile
+	organization.Details.Phone = patched.Details.Phone
+
+	organization.Owner.Type = patched.Owner.Type
+	organization.Owner.Name = patched.Owner.Name
+	organization.Owner.Address = patched.Owner.Address
+	organization.Owner.Email = patched.Owner.Email
+	organization.Owner.Mobile = patched.Owner.Mobile
+	organization.Owner.Phone = patched.Owner.Phone
+	organization.Owner.LegalNationalID = patched.Owner.LegalNationalID
+
+	return nil
+}
+
+// lockProfile loads the organization identified by its id or name with its details and owner and
+// locks it until the end of the transaction
+func lockProfile(tx repository.OrganizationRepository, organizationID string, organizationName string) (models.Organization, error) {
+	return tx.Find(repository.OrganizationLookup{ID: organizationID, Name: organizationName, Lock: true})
+}
+
+// uniqueProfileCheck checks the unique fields of the organization details and owner against the other organizations
+func uniqueProfileCheck(tx repository.OrganizationRepository, organization *models.Organization) error {
+	uniqueFields := []struct {
+		owner bool
+		field string
+		value string
+	}{
+		{false, "name", organization.Details.Name},
+		{false, "email", organization.Details.Email},
+		{false, "mobile", organization.Details.Mobile},
+		{true, "name", organization.Owner.Name},
+		{true, "email", organization.Owner.Email},
+		{true, "mobile", organization.Owner.Mobile},
+	}
+
+	for _, unique := range uniqueFields {
+		if unique.value == "" {
+			continue
+		}
+
+		used, err := tx.ProfileValueUsed(unique.owner, unique.field, unique.value, organization.ID)
+		if err != nil {
+			return err
+		}
+
+		if used {
+			owner := "organization"
+			if unique.owner {
+				owner = "organization owner"
+			}
+			return fmt.Errorf("%w: %s %s %s is already used", ErrConflict, owner, unique.field, unique.value)
+		}
+	}
+
+	return nil
+}
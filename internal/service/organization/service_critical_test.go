@@ -0,0 +1,323 @@
+package organization
+
+import (
+	"errors"
+	"fmt"
+	"ospm/config"
+	"ospm/internal/models"
+	"ospm/internal/repository/memory"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/logger"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testOrganization returns a new organization whose unique fields are made of the given name
+func testOrganization(name string) models.Organization {
+	return models.Organization{
+		Details: models.OrganizationDetails{Name: name, Address: "Tehran", Email: "info@" + name + ".com", Mobile: "0912" + name, Phone: "02100000000"},
+		Owner: models.OrganizationOwner{
+			Type: "legal", Name: name + " owner", Email: "owner@" + name + ".com", Mobile: "0935" + name, LegalNationalID: "1234567890",
+		},
+	}
+}
+
+func TestService(t *testing.T) {
+	config.LoadOSPMConfigs()
+	logger.InitLogger()
+
+	actor := audit.Actor{APIKeyID: "key-1", ClientIP: "10.0.0.1", RequestID: "request-1"}
+
+	type testCase struct {
+		name            string
+		run             func(service *Service) error
+		expectedError   error
+		expectedList    []string // names of the organizations that are not deleted
+		expectedListAll []string // names of the organizations including the soft deleted ones
+		expectedActions []string // actions of the audit log after the two organizations are created
+	}
+
+	testCases := []testCase{
+		{
+			name: "an organization is soft deleted by its name. In this case, it should only be listed with the soft deleted ones",
+			run: func(service *Service) error {
+				return service.SoftDelete("", "owl", actor)
+			},
+			expectedList:    []string{"ario"},
+			expectedListAll: []string{"owl", "ario"},
+			expectedActions: []string{audit.ActionDelete},
+		},
+		{
+			name: "a soft deleted organization is recovered. In this case, it should be listed again",
+			run: func(service *Service) error {
+				if err := service.SoftDelete("", "owl", actor); err != nil {
+					return err
+				}
+				return service.Recover("", "owl", actor)
+			},
+			expectedList:    []string{"owl", "ario"},
+			expectedListAll: []string{"owl", "ario"},
+			expectedActions: []string{audit.ActionDelete, audit.ActionRecover},
+		},
+		{
+			name: "a soft deleted organization is hard deleted. In this case, it should not be listed at all",
+			run: func(service *Service) error {
+				if err := service.SoftDelete("", "owl", actor); err != nil {
+					return err
+				}
+				return service.HardDelete("", "owl", actor)
+			},
+			expectedList:    []string{"ario"},
+			expectedListAll: []string{"ario"},
+			expectedActions: []string{audit.ActionDelete, audit.ActionHardDelete},
+		},
+		{
+			name: "an organization that does not exist is deleted. In this case, it should be rejected without any change",
+			run: func(service *Service) error {
+				return service.SoftDelete("", "unknown", actor)
+			},
+			expectedError:   errors.New("failed to find organization to delete"),
+			expectedList:    []string{"owl", "ario"},
+			expectedListAll: []string{"owl", "ario"},
+		},
+		{
+			name: "an organization is created with the name of another one. In this case, it should be rejected without any audit entry",
+			run: func(service *Service) error {
+				newOrganization := testOrganization("mns")
+				newOrganization.Details.Name = "owl"
+				_, err := service.New(newOrganization, actor)
+				return err
+			},
+			expectedError:   errors.New("the new organization can not be created"),
+			expectedList:    []string{"owl", "ario"},
+			expectedListAll: []string{"owl", "ario"},
+		},
+		{
+			name: "the name of an organization is changed by the profile update. In this case, it should be listed by the new name",
+			run: func(service *Service) error {
+				_, err := service.UpdateProfile("", "owl", []byte(`{"organization_details":{"name":"owl-mns"}}`), actor)
+				return err
+			},
+			expectedList:    []string{"owl-mns", "ario"},
+			expectedListAll: []string{"owl-mns", "ario"},
+			expectedActions: []string{audit.ActionUpdate},
+		},
+		{
+			name: "the email of the owner is changed to the email of another organization owner. In this case, it should be rejected as a conflict",
+			run: func(service *Service) error {
+				_, err := service.UpdateProfile("", "owl", []byte(`{"organization_owner":{"email":"owner@ario.com"}}`), actor)
+				return err
+			},
+			expectedError:   ErrConflict,
+			expectedList:    []string{"owl", "ario"},
+			expectedListAll: []string{"owl", "ario"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repository := memory.NewOrganizationRepository()
+			service := NewService(repository)
+
+			for _, name := range []string{"owl", "ario"} {
+				_, err := service.New(testOrganization(name), actor)
+				assert.NoError(t, err)
+			}
+
+			err := tc.run(service)
+			if tc.expectedError != nil {
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			list, err := service.List(models.OrganizationListRequest{})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedList, names(list.Items))
+
+			listAll, err := service.List(models.OrganizationListRequest{Filter: models.OrganizationFilter{Deleted: models.OrganizationDeletedInclude}})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedListAll, names(listAll.Items))
+
+			entries := repository.AuditEntries()
+			actions := []string{}
+			previous := models.AuditEntry{}
+			for _, entry := range entries {
+				actions = append(actions, entry.Action)
+				assert.Empty(t, audit.VerifyLink(previous, entry), fmt.Sprintf("audit entry %d", entry.Sequence))
+				assert.Equal(t, actor.RequestID, entry.RequestID)
+				previous = entry
+			}
+			assert.Equal(t, append([]string{audit.ActionCreate, audit.ActionCreate}, tc.expectedActions...), actions)
+		})
+	}
+}
+
+func TestServiceUpdateBalancePolicy(t *testing.T) {
+	config.LoadOSPMConfigs()
+	logger.InitLogger()
+
+	repository := memory.NewOrganizationRepository()
+	service := NewService(repository)
+
+	organizationID, err := service.New(testOrganization("owl"), audit.Actor{})
+	assert.NoError(t, err)
+
+	allow := true
+	negativeThreshold := models.NewDecimal(-500)
+	_, err = service.UpdateBalancePolicy(organizationID, "", models.BalancePolicyRequest{
+		AllowNagativeBalance: &allow, NegativeBalanceThreshold: &negativeThreshold,
+	}, audit.Actor{})
+	assert.ErrorIs(t, err, ErrInvalidBalancePolicy)
+
+	threshold := models.NewDecimal(500)
+	_, err = service.UpdateBalancePolicy(organizationID, "", models.BalancePolicyRequest{
+		AllowNagativeBalance: &allow, NegativeBalanceThreshold: &threshold, Currency: "XXX",
+	}, audit.Actor{})
+	assert.ErrorIs(t, err, ErrInvalidBalancePolicy)
+
+	updated, err := service.UpdateBalancePolicy(organizationID, "", models.BalancePolicyRequest{
+		AllowNagativeBalance: &allow, NegativeBalanceThreshold: &threshold,
+	}, audit.Actor{})
+	assert.NoError(t, err)
+	assert.True(t, updated.AllowNagativeBalance)
+
+	stored, err := service.Details("owl", "")
+	assert.NoError(t, err)
+	assert.True(t, stored.AllowNagativeBalance)
+	assert.Equal(t, 0, stored.NegativeBalanceThreshold.Amount.Cmp(threshold))
+	assert.Equal(t, config.Current().Billing.DefaultCurrency, stored.NegativeBalanceThreshold.Currency)
+}
+
+func TestServiceList(t *testing.T) {
+	config.LoadOSPMConfigs()
+	logger.InitLogger()
+
+	repository := memory.NewOrganizationRepository()
+	service := NewService(repository)
+
+	// the organizations are added to the repository directly since the new ones can not have any balance
+	balances := map[string]int64{"owl": 300, "ario": -50, "mns": 300, "orca": 0, "otter": 120}
+	for _, name := range []string{"owl", "ario", "mns", "orca", "otter"} {
+		newOrganization := testOrganization(name)
+		newOrganization.Balance = models.NewMoney(models.NewDecimal(balances[name]), "IRR")
+		if name == "orca" {
+			newOrganization.Owner.Type = "individual"
+		}
+		assert.NoError(t, repository.Create(&newOrganization))
+	}
+	assert.NoError(t, service.SoftDelete("", "otter", audit.Actor{}))
+
+	minimum := models.NewDecimal(0)
+
+	type testCase struct {
+		name          string
+		request       models.OrganizationListRequest
+		expectedError error
+		expectedPages [][]string // names of the organizations of each page
+		expectedTotal int64
+	}
+
+	testCases := []testCase{
+		{
+			name:          "the organizations are listed by the default sort. In this case, they should be in the order they are created without the deleted one",
+			request:       models.OrganizationListRequest{},
+			expectedPages: [][]string{{"owl", "ario", "mns", "orca"}},
+			expectedTotal: 4,
+		},
+		{
+			name:          "the organizations are paged by two sorted by their names. In this case, each page should continue from the cursor of the previous one",
+			request:       models.OrganizationListRequest{Sort: "name", Limit: 2},
+			expectedPages: [][]string{{"ario", "mns"}, {"orca", "owl"}},
+			expectedTotal: 4,
+		},
+		{
+			name:          "the organizations are sorted by their balance in descending order. In this case, the equal balances should be ordered by the id and none should be lost between the pages",
+			request:       models.OrganizationListRequest{Sort: "-balance", Limit: 1},
+			expectedPages: [][]string{{"?"}, {"?"}, {"orca"}, {"ario"}},
+			expectedTotal: 4,
+		},
+		{
+			name: "the organizations are filtered by the name prefix, the balance and the deleted state. In this case, only the matching ones should be listed",
+			request: models.OrganizationListRequest{
+				Filter: models.OrganizationFilter{NamePrefix: "O", BalanceMin: &minimum, Deleted: models.OrganizationDeletedInclude},
+				Sort:   "name",
+			},
+			expectedPages: [][]string{{"orca", "otter", "owl"}},
+			expectedTotal: 3,
+		},
+		{
+			name:          "only the deleted organizations of an owner type are requested. In this case, the empty page should not be an error",
+			request:       models.OrganizationListRequest{Filter: models.OrganizationFilter{OwnerType: "individual", Deleted: models.OrganizationDeletedOnly}},
+			expectedPages: [][]string{{}},
+			expectedTotal: 0,
+		},
+		{
+			name:          "the organizations are sorted by an unknown key. In this case, it should be rejected",
+			request:       models.OrganizationListRequest{Sort: "mobile"},
+			expectedError: ErrInvalidListRequest,
+		},
+		{
+			name:          "the limit is more than the maximum. In this case, it should be rejected",
+			request:       models.OrganizationListRequest{Limit: MaxListLimit + 1},
+			expectedError: ErrInvalidListRequest,
+		},
+		{
+			name:          "a cursor that is not made by the list is given. In this case, it should be rejected",
+			request:       models.OrganizationListRequest{Cursor: "not-a-cursor"},
+			expectedError: ErrInvalidListRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			request := tc.request
+			pages := [][]string{}
+
+			for {
+				page, err := service.List(request)
+				if tc.expectedError != nil {
+					assert.ErrorIs(t, err, tc.expectedError)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTotal, page.Total)
+
+				pages = append(pages, names(page.Items))
+				if page.NextCursor == "" || len(pages) > len(tc.expectedPages) {
+					break
+				}
+				request.Cursor = page.NextCursor
+			}
+
+			assert.Equal(t, len(tc.expectedPages), len(pages))
+			for i := range tc.expectedPages {
+				// ? is any of the organizations with the same value of the sort key, which are ordered by their random ids
+				if len(tc.expectedPages[i]) == 1 && tc.expectedPages[i][0] == "?" {
+					assert.Contains(t, []string{"owl", "mns"}, pages[i][0])
+					continue
+				}
+				assert.Equal(t, tc.expectedPages[i], pages[i])
+			}
+		})
+	}
+
+	// the cursor is bound to the sort of its list
+	page, err := service.List(models.OrganizationListRequest{Sort: "name", Limit: 1})
+	assert.NoError(t, err)
+	_, err = service.List(models.OrganizationListRequest{Sort: "-name", Limit: 1, Cursor: page.NextCursor})
+	assert.ErrorIs(t, err, ErrInvalidListRequest)
+}
+
+func names(list []models.OrganizationShortInfo) []string {
+	names := []string{}
+	for _, shortInfo := range list {
+		names = append(names, shortInfo.Name)
+	}
+	return names
+}
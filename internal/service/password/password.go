@@ -0,0 +1,136 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Params determines the argon2id cost parameters used to hash the passwords
+type Params struct {
+	Memory      uint32 // memory in KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultParams follows the OWASP recommendation for argon2id.
+// Changing these values makes NeedsRehash return true for the hashes created by the old values
+// so the passwords get rehashed on the next successful login
+var DefaultParams = Params{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// algorithmID is the identifier of the hash algorithm in the encoded hash
+const algorithmID = "argon2id"
+
+var (
+	ErrInvalidHash         = errors.New("the encoded hash is not in the expected format")
+	ErrIncompatibleVersion = errors.New("incompatible version of argon2")
+)
+
+// Hash gets a plain password and returns its salted argon2id hash encoded in the
+// PHC string format: $argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>
+// The encoded hash carries the algorithm, its version and the cost parameters so
+// the hashes can be verified and upgraded even after the defaults change
+func Hash(plainPassword string) (string, error) {
+	return HashWithParams(plainPassword, DefaultParams)
+}
+
+// HashWithParams is the same as Hash but uses the given cost parameters
+func HashWithParams(plainPassword string, params Params) (string, error) {
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt, error: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(plainPassword), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		algorithmID,
+		argon2.Version,
+		params.Memory,
+		params.Iterations,
+		params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify compares the given plain password with the encoded hash in constant time
+func Verify(plainPassword string, encodedHash string) (bool, error) {
+	params, salt, key, err := decode(encodedHash)
+	if err != nil {
+		return false, err
+	}
+
+	otherKey := argon2.IDKey([]byte(plainPassword), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
+// IsHashed determines whether the given value is an encoded hash or a plain text password
+func IsHashed(value string) bool {
+	_, _, _, err := decode(value)
+	return err == nil
+}
+
+// NeedsRehash returns true if the given encoded hash was created by another algorithm, version
+// or cost parameters than the current defaults
+func NeedsRehash(encodedHash string) bool {
+	params, salt, _, err := decode(encodedHash)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory != DefaultParams.Memory ||
+		params.Iterations != DefaultParams.Iterations ||
+		params.Parallelism != DefaultParams.Parallelism ||
+		params.KeyLength != DefaultParams.KeyLength ||
+		uint32(len(salt)) != DefaultParams.SaltLength
+}
+
+func decode(encodedHash string) (Params, []byte, []byte, error) {
+	var params Params
+	var version int
+
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != algorithmID {
+		return Params{}, nil, nil, ErrInvalidHash
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Params{}, nil, nil, ErrInvalidHash
+	}
+	if version != argon2.Version {
+		return Params{}, nil, nil, ErrIncompatibleVersion
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Params{}, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return Params{}, nil, nil, ErrInvalidHash
+	}
+	params.SaltLength = uint32(len(salt))
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Params{}, nil, nil, ErrInvalidHash
+	}
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,107 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashAndVerify(t *testing.T) {
+	type testCase struct {
+		name           string
+		password       string
+		passwordToTest string
+		expectedResult bool
+	}
+
+	testCases := []testCase{
+		{
+			name:           "the same password is given. In this case, the output should be true",
+			password:       "S3cret!",
+			passwordToTest: "S3cret!",
+			expectedResult: true,
+		},
+		{
+			name:           "another password is given. In this case, the output should be false",
+			password:       "S3cret!",
+			passwordToTest: "s3cret!",
+			expectedResult: false,
+		},
+		{
+			name:           "an empty password is given. In this case, the output should be false",
+			password:       "S3cret!",
+			passwordToTest: "",
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			encodedHash, err := Hash(tc.password)
+			assert.Nil(t, err)
+			assert.True(t, strings.HasPrefix(encodedHash, "$argon2id$v=19$"))
+
+			testResult, err := Verify(tc.passwordToTest, encodedHash)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedResult, testResult)
+		})
+	}
+}
+
+func TestHashIsSalted(t *testing.T) {
+	firstHash, err := Hash("S3cret!")
+	assert.Nil(t, err)
+	secondHash, err := Hash("S3cret!")
+	assert.Nil(t, err)
+
+	assert.NotEqual(t, firstHash, secondHash)
+}
+
+func TestNeedsRehash(t *testing.T) {
+	currentHash, _ := Hash("S3cret!")
+	weakerHash, _ := HashWithParams("S3cret!", Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+
+	type testCase struct {
+		name           string
+		encodedHash    string
+		expectedResult bool
+	}
+
+	testCases := []testCase{
+		{
+			name:           "the hash is created by the default parameters. In this case, the output should be false",
+			encodedHash:    currentHash,
+			expectedResult: false,
+		},
+		{
+			name:           "the hash is created by other parameters. In this case, the output should be true",
+			encodedHash:    weakerHash,
+			expectedResult: true,
+		},
+		{
+			name:           "the value is a plain text password. In this case, the output should be true",
+			encodedHash:    "S3cret!",
+			expectedResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedResult, NeedsRehash(tc.encodedHash))
+		})
+	}
+}
+
+func TestIsHashed(t *testing.T) {
+	encodedHash, _ := Hash("S3cret!")
+
+	assert.True(t, IsHashed(encodedHash))
+	assert.False(t, IsHashed("S3cret!"))
+	assert.False(t, IsHashed("$argon2id$v=19$m=19456,t=2,p=1$$"))
+	assert.False(t, IsHashed(""))
+}
@@ -0,0 +1,74 @@
+package password
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// reversiblePrefix versions the format of the encrypted secrets
+const reversiblePrefix = "v1:"
+
+var ErrInvalidEncryptionKey = errors.New("the encryption key should be 32 bytes in hex format")
+
+// Encrypt encrypts the given plain secret by AES-256-GCM using the given hex encoded key.
+// It must only be used for the secrets that should be recoverable like the ones required by
+// CHAP authentication. Passwords must be stored by Hash
+func Encrypt(plainSecret string, hexKey string) (string, error) {
+	aead, err := newAEAD(hexKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce, error: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plainSecret), nil)
+
+	return reversiblePrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverts Encrypt using the same hex encoded key
+func Decrypt(encryptedSecret string, hexKey string) (string, error) {
+	aead, err := newAEAD(hexKey)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(encryptedSecret, reversiblePrefix) {
+		return "", errors.New("unsupported encrypted secret format")
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(encryptedSecret, reversiblePrefix))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted secret")
+	}
+
+	plainSecret, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the secret, error: %w", err)
+	}
+
+	return string(plainSecret), nil
+}
+
+func newAEAD(hexKey string) (cipher.AEAD, error) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil || len(key) != 32 {
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
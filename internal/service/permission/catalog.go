@@ -0,0 +1,202 @@
+package permission
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"ospm/internal/models"
+	"ospm/internal/service/characteristic"
+	"ospm/internal/service/logger"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrInvalidCatalog is returned when the catalog definition can not be loaded
+	ErrInvalidCatalog = errors.New("invalid permission catalog")
+
+	// ErrUnknownPermission is returned when the permission is not defined in the catalog
+	ErrUnknownPermission = errors.New("permission is not defined in the permission catalog")
+
+	// ErrInvalidValue is returned when the value of the permission is not accepted by its definition
+	ErrInvalidValue = errors.New("invalid permission value")
+)
+
+//go:embed catalog.yaml
+var builtinCatalog []byte
+
+// Catalog is a loaded permission catalog with its definitions indexed by category and name
+type Catalog struct {
+	models.PermissionCatalog
+	definitions map[string]models.PermissionDefinition
+}
+
+// current is replaced as a whole on reload, so each caller either sees the old or the new catalog
+var current atomic.Pointer[Catalog]
+
+func init() {
+	current.Store(mustParse(builtinCatalog, "catalog.yaml"))
+}
+
+// Current returns the catalog in use. It is the built-in catalog until another one is loaded
+func Current() *Catalog {
+	return current.Load()
+}
+
+// LoadCatalog loads the permission catalog file and uses it instead of the current catalog.
+// An empty path loads the built-in catalog
+func LoadCatalog(path string) error {
+	catalog, err := Read(path)
+	if err != nil {
+		return err
+	}
+
+	Use(catalog)
+	if path == "" {
+		logger.OSPMLogger.Infof("built-in permission catalog version %s is loaded", catalog.Version)
+	} else {
+		logger.OSPMLogger.Infof("permission catalog version %s is loaded from %s. permissions: %d", catalog.Version, path, len(catalog.Permissions))
+	}
+	return nil
+}
+
+// Read reads and validates the permission catalog file without using it, so it can be checked
+// before anything is replaced. An empty path returns the built-in catalog
+func Read(path string) (*Catalog, error) {
+	if path == "" {
+		return mustParse(builtinCatalog, "catalog.yaml"), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to read the permission catalog file %s, error: %+v", path, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, err
+	}
+
+	catalog, err := Parse(content, path)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the permission catalog file %s, error: %+v", path, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, err
+	}
+
+	return catalog, nil
+}
+
+// Use replaces the catalog in use
+func Use(catalog *Catalog) {
+	current.Store(catalog)
+}
+
+// Parse decodes and validates the catalog definition. JSON is used for the .json files
+// and YAML for any other file. Unknown fields are rejected so typos do not go unnoticed
+func Parse(content []byte, fileName string) (*Catalog, error) {
+	var definition models.PermissionCatalog
+
+	if strings.EqualFold(filepath.Ext(fileName), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&definition); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCatalog, err)
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&definition); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCatalog, err)
+		}
+	}
+
+	if strings.TrimSpace(definition.Version) == "" {
+		return nil, fmt.Errorf("%w: the version of the catalog must be given", ErrInvalidCatalog)
+	}
+
+	catalog := &Catalog{PermissionCatalog: definition, definitions: map[string]models.PermissionDefinition{}}
+	for index := range catalog.Permissions {
+		permission := &catalog.Permissions[index]
+		if permission.PermissionCategory == "" || permission.PermissionName == "" {
+			return nil, fmt.Errorf("%w: the category and the name of permission number %d must be given", ErrInvalidCatalog, index+1)
+		}
+
+		key := key(permission.PermissionCategory, permission.PermissionName)
+		if _, found := catalog.definitions[key]; found {
+			return nil, fmt.Errorf("%w: permission %s %s is defined more than once", ErrInvalidCatalog, permission.PermissionCategory, permission.PermissionName)
+		}
+
+		if permission.ValueType == "" {
+			return nil, fmt.Errorf("%w: the value type of permission %s %s must be given", ErrInvalidCatalog, permission.PermissionCategory, permission.PermissionName)
+		}
+
+		specification := specificationOf(*permission)
+		if err := characteristic.ValidateSpecification(&specification); err != nil {
+			return nil, fmt.Errorf("%w: permission %s %s, %w", ErrInvalidCatalog, permission.PermissionCategory, permission.PermissionName, err)
+		}
+		permission.MinValue = specification.MinValue
+		permission.MaxValue = specification.MaxValue
+		permission.AllowedValues = specification.AllowedValues
+
+		catalog.definitions[key] = *permission
+	}
+
+	return catalog, nil
+}
+
+// Definition returns the definition of the permission
+func (c *Catalog) Definition(category string, name string) (models.PermissionDefinition, bool) {
+	definition, found := c.definitions[key(category, name)]
+	return definition, found
+}
+
+// Category returns the definitions of the given category in the catalog order
+func (c *Catalog) Category(category string) []models.PermissionDefinition {
+	definitions := []models.PermissionDefinition{}
+	for _, definition := range c.Permissions {
+		if definition.PermissionCategory == category {
+			definitions = append(definitions, definition)
+		}
+	}
+	return definitions
+}
+
+// Validate checks the permission against its definition and returns the normalized value
+func (c *Catalog) Validate(category string, name string, value string) (string, error) {
+	definition, found := c.Definition(category, name)
+	if !found {
+		return "", fmt.Errorf("%w: %s %s", ErrUnknownPermission, category, name)
+	}
+
+	_, normalized, err := characteristic.Validate(specificationOf(definition), "", value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s %s, %w", ErrInvalidValue, category, name, err)
+	}
+
+	return normalized, nil
+}
+
+func specificationOf(definition models.PermissionDefinition) models.CharacteristicSpecification {
+	return models.CharacteristicSpecification{
+		ValueType:     definition.ValueType,
+		MinValue:      definition.MinValue,
+		MaxValue:      definition.MaxValue,
+		AllowedValues: definition.AllowedValues,
+	}
+}
+
+func mustParse(content []byte, fileName string) *Catalog {
+	catalog, err := Parse(content, fileName)
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+func key(category string, name string) string {
+	return category + "/" + name
+}
@@ -0,0 +1,175 @@
+package permission
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		name          string
+		fileName      string
+		content       string
+		expectedError error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "a valid YAML catalog is given. In this case, it should be loaded",
+			fileName: "catalog.yaml",
+			content: `version: "2"
+permissions:
+  - permission_category: ACCESS_LEVEL
+    permission_name: CAN_LOGIN
+    value_type: enum
+    allowed_values: ["yes", "no"]
+`,
+		},
+		{
+			name:     "a valid JSON catalog is given. In this case, it should be loaded",
+			fileName: "catalog.json",
+			content:  `{"version": "2", "permissions": [{"permission_category": "ACCESS_LEVEL", "permission_name": "MAX_CONCURRENT_SESSIONS", "value_type": "integer", "min_value": "1"}]}`,
+		},
+		{
+			name:     "the catalog has no version. In this case, it should be rejected",
+			fileName: "catalog.yaml",
+			content: `permissions:
+  - permission_category: ACCESS_LEVEL
+    permission_name: CAN_LOGIN
+    value_type: boolean
+`,
+			expectedError: ErrInvalidCatalog,
+		},
+		{
+			name:          "the catalog has an unknown field. In this case, it should be rejected",
+			fileName:      "catalog.json",
+			content:       `{"version": "2", "permissions": [{"permission_category": "ACCESS_LEVEL", "permission_name": "CAN_LOGIN", "type": "boolean"}]}`,
+			expectedError: ErrInvalidCatalog,
+		},
+		{
+			name:     "a permission is defined twice. In this case, it should be rejected",
+			fileName: "catalog.yml",
+			content: `version: "2"
+permissions:
+  - permission_category: ACCESS_LEVEL
+    permission_name: CAN_LOGIN
+    value_type: boolean
+  - permission_category: ACCESS_LEVEL
+    permission_name: CAN_LOGIN
+    value_type: boolean
+`,
+			expectedError: ErrInvalidCatalog,
+		},
+		{
+			name:     "a permission without a value type is defined. In this case, it should be rejected",
+			fileName: "catalog.yaml",
+			content: `version: "2"
+permissions:
+  - permission_category: ACCESS_LEVEL
+    permission_name: CAN_LOGIN
+`,
+			expectedError: ErrInvalidCatalog,
+		},
+		{
+			name:     "an enum permission without allowed values is defined. In this case, it should be rejected",
+			fileName: "catalog.yaml",
+			content: `version: "2"
+permissions:
+  - permission_category: ACCESS_LEVEL
+    permission_name: CAN_LOGIN
+    value_type: enum
+`,
+			expectedError: ErrInvalidCatalog,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Parse([]byte(tc.content), tc.fileName)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	catalog, err := Parse([]byte(`version: "2"
+permissions:
+  - permission_category: ACCESS_LEVEL
+    permission_name: CAN_LOGIN
+    value_type: enum
+    allowed_values: ["yes", "no"]
+  - permission_category: USAGE_LEVEL
+    permission_name: MAX_BANDWIDTH
+    value_type: bandwidth
+    max_value: 1Gbps
+`), "catalog.yaml")
+	assert.NoError(t, err)
+
+	type testCase struct {
+		name          string
+		category      string
+		permission    string
+		value         string
+		expectedValue string
+		expectedError error
+	}
+
+	testCases := []testCase{
+		{
+			name:          "an allowed value of an enum permission is given. In this case, it should be accepted",
+			category:      "ACCESS_LEVEL",
+			permission:    "CAN_LOGIN",
+			value:         "yes",
+			expectedValue: "yes",
+		},
+		{
+			name:          "a value out of the allowed values is given. In this case, it should be rejected",
+			category:      "ACCESS_LEVEL",
+			permission:    "CAN_LOGIN",
+			value:         "maybe",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:          "a bandwidth within the range is given. In this case, it should be normalized",
+			category:      "USAGE_LEVEL",
+			permission:    "MAX_BANDWIDTH",
+			value:         "10Mbps",
+			expectedValue: "10000000bps",
+		},
+		{
+			name:          "a bandwidth over the max value is given. In this case, it should be rejected",
+			category:      "USAGE_LEVEL",
+			permission:    "MAX_BANDWIDTH",
+			value:         "2Gbps",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			name:          "a permission of another category is given. In this case, it should be rejected as unknown",
+			category:      "REPORT_LEVEL",
+			permission:    "CAN_LOGIN",
+			value:         "yes",
+			expectedError: ErrUnknownPermission,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			value, err := catalog.Validate(tc.category, tc.permission, tc.value)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, value)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package permission
+
+import (
+	"errors"
+	"fmt"
+	"ospm/config"
+	"ospm/internal/models"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidQuery is returned when the permissions to evaluate are not given properly
+var ErrInvalidQuery = errors.New("invalid permission query")
+
+// maxBatchSize limits the number of permissions that can be evaluated in a single request
+const maxBatchSize = 100
+
+// grantedPermissions are the permissions of a subscriber resolved through its group
+type grantedPermissions struct {
+	subscriberGroupID string
+	rules             map[string]models.PermissionRule
+	expiresAt         time.Time
+}
+
+// evaluationCache keeps the resolved permissions of the subscribers. The generation is increased by
+// each invalidation so a load that was started before the invalidation does not store stale permissions
+type evaluationCache struct {
+	sync.RWMutex
+	generation  uint64
+	subscribers map[string]grantedPermissions
+}
+
+var cache = &evaluationCache{subscribers: map[string]grantedPermissions{}}
+
+// Evaluate resolves the permissions of the subscriber through its subscriber group and
+// decides on each of the requested permissions
+func Evaluate(subscriberID string, queries []models.PermissionAPI) (models.PermissionEvaluationResponse, error) {
+	if len(queries) == 0 || len(queries) > maxBatchSize {
+		return models.PermissionEvaluationResponse{}, fmt.Errorf("%w: between 1 and %d permissions should be given", ErrInvalidQuery, maxBatchSize)
+	}
+	for _, query := range queries {
+		if query.PermissionCategory == "" || query.PermissionName == "" {
+			return models.PermissionEvaluationResponse{}, fmt.Errorf("%w: the category and the name of the permissions must be given", ErrInvalidQuery)
+		}
+	}
+
+	granted, err := grants(subscriberID)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to resolve the permissions of subscriber id %s, error: %+v", subscriberID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.PermissionEvaluationResponse{}, err
+	}
+
+	response := models.PermissionEvaluationResponse{
+		SubscriberID:      subscriberID,
+		SubscriberGroupID: granted.subscriberGroupID,
+		Decisions:         []models.PermissionDecision{},
+	}
+	for _, query := range queries {
+		response.Decisions = append(response.Decisions, Decide(granted.rules, query))
+	}
+
+	return response, nil
+}
+
+// Decide evaluates the query against the given permissions of a subscriber group.
+// A permission that is not in the group is denied. If the query has a value, the group
+// must have the same value, otherwise any value other than false or no is allowed
+func Decide(rules map[string]models.PermissionRule, query models.PermissionAPI) models.PermissionDecision {
+	decision := models.PermissionDecision{
+		PermissionCategory: query.PermissionCategory,
+		PermissionName:     query.PermissionName,
+		RequestedValue:     query.PermissionValue,
+		Decision:           models.PermissionDecisionDeny,
+	}
+
+	rule, found := rules[key(query.PermissionCategory, query.PermissionName)]
+	if !found {
+		decision.Reason = "the subscriber group does not have the permission"
+		return decision
+	}
+	decision.MatchedRule = &rule
+
+	if query.PermissionValue == "" {
+		if isNegative(rule.PermissionValue) {
+			decision.Reason = fmt.Sprintf("the subscriber group denies the permission by value %s", rule.PermissionValue)
+			return decision
+		}
+		decision.Decision = models.PermissionDecisionAllow
+		decision.Reason = "the subscriber group grants the permission"
+		return decision
+	}
+
+	requestedValue := query.PermissionValue
+	if _, defined := Current().Definition(query.PermissionCategory, query.PermissionName); defined {
+		normalized, err := Current().Validate(query.PermissionCategory, query.PermissionName, query.PermissionValue)
+		if err != nil {
+			decision.Reason = err.Error()
+			return decision
+		}
+		requestedValue = normalized
+	}
+
+	if requestedValue != rule.PermissionValue {
+		decision.Reason = fmt.Sprintf("the subscriber group has the permission with value %s", rule.PermissionValue)
+		return decision
+	}
+
+	decision.Decision = models.PermissionDecisionAllow
+	decision.Reason = "the subscriber group grants the permission with the requested value"
+	return decision
+}
+
+// InvalidateSubscriber removes the cached permissions of the subscriber.
+// It should be called whenever the subscriber is moved to another group or deleted
+func InvalidateSubscriber(subscriberID string) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	cache.generation++
+	delete(cache.subscribers, subscriberID)
+}
+
+// InvalidateGroup removes the cached permissions of all members of the subscriber group.
+// It should be called whenever the permissions of the group change or the group is deleted
+func InvalidateGroup(subscriberGroupID string) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	cache.generation++
+	for subscriberID, granted := range cache.subscribers {
+		if granted.subscriberGroupID == subscriberGroupID {
+			delete(cache.subscribers, subscriberID)
+		}
+	}
+}
+
+// grants returns the cached permissions of the subscriber or loads them from the database
+func grants(subscriberID string) (grantedPermissions, error) {
+	cache.RLock()
+	granted, found := cache.subscribers[subscriberID]
+	generation := cache.generation
+	cache.RUnlock()
+
+	if found && time.Now().Before(granted.expiresAt) {
+		return granted, nil
+	}
+
+	granted, err := loadGrants(subscriberID)
+	if err != nil {
+		return grantedPermissions{}, err
+	}
+
+	cache.Lock()
+	if cache.generation == generation {
+		cache.subscribers[subscriberID] = granted
+	}
+	cache.Unlock()
+
+	return granted, nil
+}
+
+func loadGrants(subscriberID string) (grantedPermissions, error) {
+	var subscriber models.Subscriber
+	err := cockroachdb.DB.Select("id", "subscriber_group_id").First(&subscriber, "id = ?", subscriberID).Error
+	if err != nil {
+		return grantedPermissions{}, err
+	}
+
+	var permissions []models.Permission
+	err = cockroachdb.DB.Where("subscriber_group_id = ?", subscriber.SubscriberGroupID).Find(&permissions).Error
+	if err != nil {
+		return grantedPermissions{}, err
+	}
+
+	granted := grantedPermissions{
+		subscriberGroupID: subscriber.SubscriberGroupID,
+		rules:             map[string]models.PermissionRule{},
+		expiresAt:         time.Now().Add(cacheTTL()),
+	}
+	for _, permission := range permissions {
+		granted.rules[key(permission.PermissionCategory, permission.PermissionName)] = models.PermissionRule{
+			PermissionID:       permission.ID,
+			SubscriberGroupID:  permission.SubscriberGroupID,
+			PermissionCategory: permission.PermissionCategory,
+			PermissionName:     permission.PermissionName,
+			PermissionValue:    permission.PermissionValue,
+		}
+	}
+
+	return granted, nil
+}
+
+func cacheTTL() time.Duration {
+	configs := config.Current()
+	if configs == nil || configs.Permission == nil {
+		return 5 * time.Minute
+	}
+	return configs.Permission.CacheTTL
+}
+
+func isNegative(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "false", "no":
+		return true
+	}
+	return false
+}
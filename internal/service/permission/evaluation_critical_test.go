@@ -0,0 +1,93 @@
+package permission
+
+import (
+	"ospm/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecide(t *testing.T) {
+	rules := map[string]models.PermissionRule{
+		key("ACCESS_LEVEL", "CAN_LOGIN"): {
+			PermissionID: "1", SubscriberGroupID: "group", PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN", PermissionValue: "yes",
+		},
+		key("REPORT_LEVEL", "CAN_VIEW_PAYMENT_HISTORY"): {
+			PermissionID: "2", SubscriberGroupID: "group", PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_PAYMENT_HISTORY", PermissionValue: "no",
+		},
+		key("ACCESS_LEVEL", "MAX_CONCURRENT_SESSIONS"): {
+			PermissionID: "3", SubscriberGroupID: "group", PermissionCategory: "ACCESS_LEVEL", PermissionName: "MAX_CONCURRENT_SESSIONS", PermissionValue: "2",
+		},
+	}
+
+	type testCase struct {
+		name             string
+		query            models.PermissionAPI
+		expectedDecision string
+		expectedRuleID   string
+	}
+
+	testCases := []testCase{
+		{
+			name:             "the group grants the permission and no value is requested. In this case, it should be allowed",
+			query:            models.PermissionAPI{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN"},
+			expectedDecision: models.PermissionDecisionAllow,
+			expectedRuleID:   "1",
+		},
+		{
+			name:             "the group has the permission with value no. In this case, it should be denied with the matched rule",
+			query:            models.PermissionAPI{PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_PAYMENT_HISTORY"},
+			expectedDecision: models.PermissionDecisionDeny,
+			expectedRuleID:   "2",
+		},
+		{
+			name:             "the group does not have the permission. In this case, it should be denied without a matched rule",
+			query:            models.PermissionAPI{PermissionCategory: "PAYMENT_LEVEL", PermissionName: "CAN_PAY_ONLINE"},
+			expectedDecision: models.PermissionDecisionDeny,
+		},
+		{
+			name:             "the requested value is the same as the value of the group in another form. In this case, it should be allowed",
+			query:            models.PermissionAPI{PermissionCategory: "ACCESS_LEVEL", PermissionName: "MAX_CONCURRENT_SESSIONS", PermissionValue: " 2"},
+			expectedDecision: models.PermissionDecisionAllow,
+			expectedRuleID:   "3",
+		},
+		{
+			name:             "the requested value is different from the value of the group. In this case, it should be denied",
+			query:            models.PermissionAPI{PermissionCategory: "ACCESS_LEVEL", PermissionName: "MAX_CONCURRENT_SESSIONS", PermissionValue: "3"},
+			expectedDecision: models.PermissionDecisionDeny,
+			expectedRuleID:   "3",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			decision := Decide(rules, tc.query)
+			assert.Equal(t, tc.expectedDecision, decision.Decision)
+			if tc.expectedRuleID == "" {
+				assert.Nil(t, decision.MatchedRule)
+			} else {
+				assert.Equal(t, tc.expectedRuleID, decision.MatchedRule.PermissionID)
+			}
+		})
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	cache.subscribers = map[string]grantedPermissions{
+		"subscriber-1": {subscriberGroupID: "group-1", expiresAt: expiresAt},
+		"subscriber-2": {subscriberGroupID: "group-1", expiresAt: expiresAt},
+		"subscriber-3": {subscriberGroupID: "group-2", expiresAt: expiresAt},
+	}
+
+	InvalidateGroup("group-1")
+	assert.NotContains(t, cache.subscribers, "subscriber-1")
+	assert.NotContains(t, cache.subscribers, "subscriber-2")
+	assert.Contains(t, cache.subscribers, "subscriber-3")
+
+	InvalidateSubscriber("subscriber-3")
+	assert.Empty(t, cache.subscribers)
+}
@@ -0,0 +1,429 @@
+package policy
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"ospm/internal/service/apikey"
+	"ospm/internal/service/complementary"
+	"ospm/internal/service/logger"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// ErrInvalidPolicy is returned when the policy file can not be loaded
+var ErrInvalidPolicy = errors.New("invalid route policy")
+
+//go:embed policy.yaml
+var builtinPolicy []byte
+
+// Document is the content of the policy file
+type Document struct {
+	Version       string `json:"version" yaml:"version"`
+	DefaultEffect string `json:"default_effect" yaml:"default_effect"`
+	Rules         []Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule decides the effect of the requests that match its routes, methods and query
+// parameters if all of its conditions hold
+type Rule struct {
+	Name       string            `json:"name" yaml:"name"`
+	Routes     []string          `json:"routes" yaml:"routes"`
+	Methods    []string          `json:"methods" yaml:"methods"`
+	Query      map[string]string `json:"query" yaml:"query"`
+	Conditions Conditions        `json:"conditions" yaml:"conditions"`
+	Effect     string            `json:"effect" yaml:"effect"`
+	Message    string            `json:"message" yaml:"message"` // returned to the client when the request is denied
+}
+
+// Conditions of a rule. Empty conditions always hold
+type Conditions struct {
+	CIDRs       []string     `json:"cidrs" yaml:"cidrs"`
+	Scopes      []string     `json:"scopes" yaml:"scopes"`
+	TimeWindows []TimeWindow `json:"time_windows" yaml:"time_windows"`
+}
+
+type TimeWindow struct {
+	Days     []string `json:"days" yaml:"days"`
+	Start    string   `json:"start" yaml:"start"`
+	End      string   `json:"end" yaml:"end"`
+	Timezone string   `json:"timezone" yaml:"timezone"`
+}
+
+// Request contains what the rules are evaluated on. Scopes are the scopes
+// of the API key of the request and it is nil if there is no key
+type Request struct {
+	Method   string
+	Path     string
+	Query    func(key string) string
+	ClientIP string
+	Scopes   []string
+	Time     time.Time
+}
+
+// Decision is the effect on the request and the rule that decided it.
+// Rule is empty if the default effect is applied
+type Decision struct {
+	Effect  string
+	Rule    string
+	Message string
+}
+
+// Policy is a loaded and validated policy document
+type Policy struct {
+	Version       string
+	defaultEffect string
+	rules         []rule
+}
+
+type rule struct {
+	Rule
+	clientIPs *complementary.IPSet
+	routes    [][]string
+	methods   map[string]bool
+	windows   []window
+}
+
+type window struct {
+	days     map[time.Weekday]bool
+	start    int
+	end      int
+	location *time.Location
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// current is replaced as a whole on reload, so each caller either sees the old or the new policy
+var current atomic.Pointer[Policy]
+
+func init() {
+	current.Store(mustParse(builtinPolicy, "policy.yaml"))
+}
+
+// Current returns the policy in use. It is the built-in policy until another one is loaded
+func Current() *Policy {
+	return current.Load()
+}
+
+// Load loads the route policy file and uses it instead of the current policy.
+// An empty path loads the built-in policy
+func Load(path string) error {
+	policy, err := Read(path)
+	if err != nil {
+		return err
+	}
+
+	Use(policy)
+	if path == "" {
+		logger.OSPMLogger.Infof("built-in route policy version %s is loaded", policy.Version)
+	} else {
+		logger.OSPMLogger.Infof("route policy version %s is loaded from %s. rules: %d", policy.Version, path, len(policy.rules))
+	}
+	return nil
+}
+
+// Read reads and validates the route policy file without using it, so it can be checked
+// before anything is replaced. An empty path returns the built-in policy
+func Read(path string) (*Policy, error) {
+	if path == "" {
+		return mustParse(builtinPolicy, "policy.yaml"), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to read the route policy file %s, error: %+v", path, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, err
+	}
+
+	policy, err := Parse(content, path)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the route policy file %s, error: %+v", path, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// Use replaces the policy in use
+func Use(policy *Policy) {
+	current.Store(policy)
+}
+
+// Parse decodes and validates the policy document. JSON is used for the .json files
+// and YAML for any other file. Unknown fields are rejected so typos do not go unnoticed
+func Parse(content []byte, fileName string) (*Policy, error) {
+	var document Document
+
+	if strings.EqualFold(filepath.Ext(fileName), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&document); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPolicy, err)
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&document); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPolicy, err)
+		}
+	}
+
+	if strings.TrimSpace(document.Version) == "" {
+		return nil, fmt.Errorf("%w: the version of the policy must be given", ErrInvalidPolicy)
+	}
+
+	policy := &Policy{Version: document.Version, defaultEffect: EffectAllow}
+	if document.DefaultEffect != "" {
+		if !isValidEffect(document.DefaultEffect) {
+			return nil, fmt.Errorf("%w: unknown default effect %q, valid values are: allow/deny", ErrInvalidPolicy, document.DefaultEffect)
+		}
+		policy.defaultEffect = document.DefaultEffect
+	}
+
+	for index, definition := range document.Rules {
+		compiled, err := compile(definition)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule number %d %s, %s", ErrInvalidPolicy, index+1, definition.Name, err)
+		}
+		policy.rules = append(policy.rules, compiled)
+	}
+
+	return policy, nil
+}
+
+// Evaluate returns the effect of the first rule that matches the request and whose
+// conditions all hold. The default effect is returned if there is no such rule
+func (p *Policy) Evaluate(request Request) Decision {
+	for _, rule := range p.rules {
+		if rule.matches(request) && rule.holds(request) {
+			return Decision{Effect: rule.Effect, Rule: rule.Name, Message: rule.Message}
+		}
+	}
+
+	return Decision{Effect: p.defaultEffect}
+}
+
+func compile(definition Rule) (rule, error) {
+	compiled := rule{Rule: definition, methods: map[string]bool{}}
+
+	if definition.Name == "" {
+		return rule{}, errors.New("the name of the rule must be given")
+	}
+	if !isValidEffect(definition.Effect) {
+		return rule{}, fmt.Errorf("unknown effect %q, valid values are: allow/deny", definition.Effect)
+	}
+	if len(definition.Routes) == 0 {
+		return rule{}, errors.New("at least one route must be given")
+	}
+
+	for _, route := range definition.Routes {
+		if !strings.HasPrefix(route, "/") {
+			return rule{}, fmt.Errorf("route %q must start with /", route)
+		}
+		segments := splitPath(route)
+		for index, segment := range segments {
+			if segment == "*" && index != len(segments)-1 {
+				return rule{}, fmt.Errorf("* can only be the last segment of route %q", route)
+			}
+		}
+		compiled.routes = append(compiled.routes, segments)
+	}
+
+	for _, method := range definition.Methods {
+		compiled.methods[strings.ToUpper(method)] = true
+	}
+
+	// the CIDRs are parsed once here, so an invalid entry fails the loading instead of never matching
+	clientIPs, err := complementary.ParseIPSet(definition.Conditions.CIDRs)
+	if err != nil {
+		return rule{}, err
+	}
+	compiled.clientIPs = clientIPs
+
+	for _, scope := range definition.Conditions.Scopes {
+		if !apikey.IsValidScope(scope) {
+			return rule{}, fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	for _, definedWindow := range definition.Conditions.TimeWindows {
+		compiledWindow, err := compileWindow(definedWindow)
+		if err != nil {
+			return rule{}, err
+		}
+		compiled.windows = append(compiled.windows, compiledWindow)
+	}
+
+	return compiled, nil
+}
+
+func compileWindow(definition TimeWindow) (window, error) {
+	compiled := window{days: map[time.Weekday]bool{}, location: time.Local}
+
+	for _, day := range definition.Days {
+		weekday, found := weekdays[strings.ToLower(day)]
+		if !found {
+			return window{}, fmt.Errorf("unknown day %q, valid values are: mon/tue/wed/thu/fri/sat/sun", day)
+		}
+		compiled.days[weekday] = true
+	}
+
+	var err error
+	if compiled.start, err = parseClock(definition.Start); err != nil {
+		return window{}, err
+	}
+	if compiled.end, err = parseClock(definition.End); err != nil {
+		return window{}, err
+	}
+
+	if definition.Timezone != "" {
+		if compiled.location, err = time.LoadLocation(definition.Timezone); err != nil {
+			return window{}, fmt.Errorf("unknown timezone %q", definition.Timezone)
+		}
+	}
+
+	return compiled, nil
+}
+
+func (r *rule) matches(request Request) bool {
+	if len(r.methods) > 0 && !r.methods[strings.ToUpper(request.Method)] {
+		return false
+	}
+
+	for key, value := range r.Query {
+		given := request.Query(key)
+		if given == "" || (value != "*" && given != value) {
+			return false
+		}
+	}
+
+	path := splitPath(request.Path)
+	for _, route := range r.routes {
+		if matchRoute(route, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *rule) holds(request Request) bool {
+	if !r.clientIPs.IsEmpty() && !r.clientIPs.ContainsString(request.ClientIP) {
+		return false
+	}
+
+	if len(r.Conditions.Scopes) > 0 {
+		hasScope := false
+		for _, scope := range r.Conditions.Scopes {
+			if apikey.HasScope(request.Scopes, scope) {
+				hasScope = true
+				break
+			}
+		}
+		if !hasScope {
+			return false
+		}
+	}
+
+	if len(r.windows) > 0 {
+		inWindow := false
+		for _, window := range r.windows {
+			if window.contains(request.Time) {
+				inWindow = true
+				break
+			}
+		}
+		if !inWindow {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (w *window) contains(at time.Time) bool {
+	local := at.In(w.location)
+	minute := local.Hour()*60 + local.Minute()
+
+	// a window that passes midnight belongs to the day that it starts on
+	day := local.Weekday()
+	inWindow := minute >= w.start && minute < w.end
+	if w.end <= w.start {
+		inWindow = minute >= w.start || minute < w.end
+		if minute < w.end {
+			day = local.AddDate(0, 0, -1).Weekday()
+		}
+	}
+
+	return inWindow && (len(w.days) == 0 || w.days[day])
+}
+
+// matchRoute matches the path segments against the route pattern. The segments are compared
+// case-insensitively since the routing of Fiber is case-insensitive, so /Organization reaches
+// the same handler as /organization and it must not bypass the rules of the route
+func matchRoute(route []string, path []string) bool {
+	for index, segment := range route {
+		if segment == "*" {
+			return true
+		}
+		if index >= len(path) {
+			return false
+		}
+		if !strings.HasPrefix(segment, ":") && !strings.EqualFold(segment, path[index]) {
+			return false
+		}
+	}
+
+	return len(route) == len(path)
+}
+
+func splitPath(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return []string{}
+	}
+	return strings.Split(trimmed, "/")
+}
+
+// parseClock returns the minutes of the day of the given HH:MM time
+func parseClock(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, it should be in HH:MM format", clock)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func isValidEffect(effect string) bool {
+	return effect == EffectAllow || effect == EffectDeny
+}
+
+func mustParse(content []byte, fileName string) *Policy {
+	policy, err := Parse(content, fileName)
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
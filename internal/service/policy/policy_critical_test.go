@@ -0,0 +1,238 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	policy, err := Parse([]byte(`version: "1"
+default_effect: allow
+rules:
+  - name: hard-delete-from-admins
+    routes: ["/organization", "/subscriber/:subscriber_id"]
+    methods: [DELETE]
+    query: {mode: hard}
+    conditions:
+      cidrs: ["172.16.1.5/32", "192.168.1.12"]
+      scopes: ["organization:hard_delete"]
+    effect: allow
+  - name: hard-delete-others
+    routes: ["/organization", "/subscriber/:subscriber_id"]
+    methods: [DELETE]
+    query: {mode: hard}
+    effect: deny
+  - name: subscriber-group-office-hours
+    routes: ["/subscriber_group/*"]
+    methods: [POST, PATCH, DELETE]
+    conditions:
+      time_windows:
+        - days: [mon, tue, wed, thu, fri]
+          start: "08:00"
+          end: "18:00"
+          timezone: UTC
+    effect: allow
+  - name: subscriber-group-changes
+    routes: ["/subscriber_group/*"]
+    methods: [POST, PATCH, DELETE]
+    effect: deny
+  - name: maintenance-window
+    routes: ["/catalog/*"]
+    conditions:
+      time_windows:
+        - start: "23:00"
+          end: "01:00"
+          timezone: UTC
+    effect: deny
+`), "policy.yaml")
+	assert.NoError(t, err)
+
+	// 2024-01-03 is a Wednesday
+	officeHours := time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		name           string
+		request        Request
+		expectedEffect string
+		expectedRule   string
+	}
+
+	testCases := []testCase{
+		{
+			name:           "client ip exists in the whitelist as an absolute IP and the key has the scope. In this case, the hard delete should be allowed",
+			request:        Request{Method: "DELETE", Path: "/organization", Query: query(map[string]string{"mode": "hard"}), ClientIP: "192.168.1.12", Scopes: []string{"organization:*"}, Time: officeHours},
+			expectedEffect: EffectAllow,
+			expectedRule:   "hard-delete-from-admins",
+		},
+		{
+			name:           "client ip does not exist in the whitelist. In this case, the hard delete should be denied",
+			request:        Request{Method: "DELETE", Path: "/subscriber/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a", Query: query(map[string]string{"mode": "hard"}), ClientIP: "192.168.1.13", Scopes: []string{"*"}, Time: officeHours},
+			expectedEffect: EffectDeny,
+			expectedRule:   "hard-delete-others",
+		},
+		{
+			name:           "client ip exists in the whitelist but the key does not have the scope. In this case, the hard delete should be denied",
+			request:        Request{Method: "DELETE", Path: "/organization", Query: query(map[string]string{"mode": "hard"}), ClientIP: "172.16.1.5", Scopes: []string{"organization:delete"}, Time: officeHours},
+			expectedEffect: EffectDeny,
+			expectedRule:   "hard-delete-others",
+		},
+		{
+			name:           "the organization is deleted in soft mode. In this case, the default effect should be applied",
+			request:        Request{Method: "DELETE", Path: "/organization", Query: query(map[string]string{"mode": "soft"}), ClientIP: "192.168.1.13", Time: officeHours},
+			expectedEffect: EffectAllow,
+		},
+		{
+			name:           "a subscriber group is changed in the office hours. In this case, it should be allowed",
+			request:        Request{Method: "PATCH", Path: "/subscriber_group/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a", Query: query(nil), Time: officeHours},
+			expectedEffect: EffectAllow,
+			expectedRule:   "subscriber-group-office-hours",
+		},
+		{
+			name:           "a subscriber group is changed on the weekend. In this case, it should be denied",
+			request:        Request{Method: "PATCH", Path: "/subscriber_group/ed83a2ba-c55c-4297-b2ac-df7b02abdd7a", Query: query(nil), Time: officeHours.AddDate(0, 0, 3)},
+			expectedEffect: EffectDeny,
+			expectedRule:   "subscriber-group-changes",
+		},
+		{
+			name:           "the catalog is requested after midnight in a window that passes midnight. In this case, it should be denied",
+			request:        Request{Method: "GET", Path: "/catalog/productOffering", Query: query(nil), Time: time.Date(2024, 1, 3, 0, 30, 0, 0, time.UTC)},
+			expectedEffect: EffectDeny,
+			expectedRule:   "maintenance-window",
+		},
+		{
+			name:           "the catalog is requested out of the maintenance window. In this case, the default effect should be applied",
+			request:        Request{Method: "GET", Path: "/catalog/productOffering", Query: query(nil), Time: officeHours},
+			expectedEffect: EffectAllow,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			decision := policy.Evaluate(tc.request)
+			assert.Equal(t, tc.expectedEffect, decision.Effect)
+			assert.Equal(t, tc.expectedRule, decision.Rule)
+		})
+	}
+}
+
+func TestBuiltinPolicy(t *testing.T) {
+	type testCase struct {
+		name           string
+		path           string
+		clientIP       string
+		expectedEffect string
+	}
+
+	testCases := []testCase{
+		{
+			name:           "a local client changes the balance policy. In this case, it should be allowed",
+			path:           "/organization/profile/balance_policy",
+			clientIP:       "127.0.0.1",
+			expectedEffect: EffectAllow,
+		},
+		{
+			name:           "a remote client changes the balance policy. In this case, it should be denied",
+			path:           "/organization/profile/balance_policy",
+			clientIP:       "192.168.1.12",
+			expectedEffect: EffectDeny,
+		},
+		{
+			name:           "a remote client changes the balance policy by a mixed-case path which reaches the same handler. In this case, it should be denied",
+			path:           "/Organization/Profile/Balance_Policy/",
+			clientIP:       "192.168.1.12",
+			expectedEffect: EffectDeny,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			decision := Current().Evaluate(Request{
+				Method:   "PATCH",
+				Path:     tc.path,
+				Query:    query(nil),
+				ClientIP: tc.clientIP,
+				Time:     time.Now(),
+			})
+			assert.Equal(t, tc.expectedEffect, decision.Effect)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		name     string
+		fileName string
+		content  string
+	}
+
+	testCases := []testCase{
+		{
+			name:     "the policy has no version. In this case, it should be rejected",
+			fileName: "policy.yaml",
+			content:  "rules: []",
+		},
+		{
+			name:     "a rule has an unknown effect. In this case, it should be rejected",
+			fileName: "policy.json",
+			content:  `{"version": "1", "rules": [{"name": "rule", "routes": ["/organization"], "effect": "block"}]}`,
+		},
+		{
+			name:     "a rule has an unknown scope. In this case, it should be rejected",
+			fileName: "policy.json",
+			content:  `{"version": "1", "rules": [{"name": "rule", "routes": ["/organization"], "conditions": {"scopes": ["organizations:read"]}, "effect": "deny"}]}`,
+		},
+		{
+			name:     "a rule has a mistyped CIDR. In this case, it should be rejected instead of never matching",
+			fileName: "policy.json",
+			content:  `{"version": "1", "rules": [{"name": "rule", "routes": ["/organization"], "conditions": {"cidrs": ["10.0.0.0/8", "10.0.0.256"]}, "effect": "allow"}]}`,
+		},
+		{
+			name:     "a rule has a wildcard in the middle of the route. In this case, it should be rejected",
+			fileName: "policy.json",
+			content:  `{"version": "1", "rules": [{"name": "rule", "routes": ["/*/profile"], "effect": "deny"}]}`,
+		},
+		{
+			name:     "a time window has an invalid time. In this case, it should be rejected",
+			fileName: "policy.yaml",
+			content: `version: "1"
+rules:
+  - name: rule
+    routes: ["/catalog/*"]
+    conditions:
+      time_windows: [{start: "8am", end: "18:00"}]
+    effect: deny
+`,
+		},
+		{
+			name:     "a rule has an unknown field. In this case, it should be rejected",
+			fileName: "policy.yaml",
+			content: `version: "1"
+rules:
+  - name: rule
+    route: "/catalog"
+    effect: deny
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Parse([]byte(tc.content), tc.fileName)
+			assert.ErrorIs(t, err, ErrInvalidPolicy)
+		})
+	}
+}
+
+func query(values map[string]string) func(string) string {
+	return func(key string) string {
+		return values[key]
+	}
+}
@@ -0,0 +1,41 @@
+// Package settings keeps the config, the route policy and the permission catalog that are in use
+// as one snapshot, so they are replaced together on reload and a request never sees a mix of them
+package settings
+
+import (
+	"ospm/config"
+	"ospm/internal/service/permission"
+	"ospm/internal/service/policy"
+	"sync/atomic"
+)
+
+// Snapshot is the config with the route policy and the permission catalog of the files it points to
+type Snapshot struct {
+	Config  *config.OSPMConfig
+	Policy  *policy.Policy
+	Catalog *permission.Catalog
+}
+
+// current is replaced as a whole on reload, so each reader either sees the old or the new snapshot
+var current atomic.Pointer[Snapshot]
+
+// Current returns the snapshot in use. Until a snapshot is published, it is made of
+// the config, the route policy and the permission catalog that are loaded one by one
+func Current() *Snapshot {
+	if snapshot := current.Load(); snapshot != nil {
+		return snapshot
+	}
+
+	return &Snapshot{Config: config.Current(), Policy: policy.Current(), Catalog: permission.Current()}
+}
+
+// Publish replaces the snapshot in use. The config, the route policy and the permission catalog of
+// their own packages are replaced after it, they are only read one by one by the code that does not
+// serve the requests through the middlewares, like the logger and the RADIUS server
+func Publish(snapshot *Snapshot) {
+	current.Store(snapshot)
+
+	config.Use(snapshot.Config)
+	policy.Use(snapshot.Policy)
+	permission.Use(snapshot.Catalog)
+}
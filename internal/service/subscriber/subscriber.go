@@ -0,0 +1,576 @@
+package subscriber
+
+import (
+	"errors"
+	"fmt"
+	"ospm/config"
+	"ospm/internal/models"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/logger"
+	"ospm/internal/service/password"
+	"ospm/internal/service/permission"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EntityType is the type of the subscribers in the audit log
+const EntityType = "subscriber"
+
+var (
+	// ErrInvalidReference is returned when the organization or the subscriber group
+	// that a subscriber points to does not exist
+	ErrInvalidReference = errors.New("invalid subscriber reference")
+
+	// ErrInvalidCredentials is returned when the given username or password is wrong
+	ErrInvalidCredentials = errors.New("invalid username or password")
+)
+
+// List returns the subscribers of the given organization in shortened format.
+// Soft deleted subscribers are excluded!
+func List(organizationID string) ([]models.SubscriberShortInfo, error) {
+	subscriberList := []models.Subscriber{}
+
+	err := cockroachdb.DB.Preload("Details").
+		Where("organization_id = ?", organizationID).
+		Find(&subscriberList).Error
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the list of subscribers for organization id %s, error: %+v", organizationID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, errors.New(errorMessage)
+	}
+
+	return Shorten(subscriberList), nil
+}
+
+// ListByGroup returns the subscribers that are member of the given subscriber group
+// in shortened format. Soft deleted subscribers are excluded!
+func ListByGroup(subscriberGroupID string) ([]models.SubscriberShortInfo, error) {
+	subscriberList := []models.Subscriber{}
+
+	err := cockroachdb.DB.Preload("Details").
+		Where("subscriber_group_id = ?", subscriberGroupID).
+		Find(&subscriberList).Error
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the list of subscribers for subscriber group id %s, error: %+v", subscriberGroupID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, errors.New(errorMessage)
+	}
+
+	return Shorten(subscriberList), nil
+}
+
+// Detail returns the subscriber identified by the given id including its details
+// and credentials. The result should be cleaned before being sent to the clients
+func Detail(subscriberID string) (models.Subscriber, error) {
+	var subscriber models.Subscriber
+
+	err := cockroachdb.DB.Preload("Details").Preload("Credentials").
+		First(&subscriber, "id = ?", subscriberID).Error
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load details of given subscriber id %s, error: %+v", subscriberID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.Subscriber{}, err
+	}
+
+	return subscriber, nil
+}
+
+// New gets the new subscriber details and adds it into the database then returns
+// the new added subscriber's ID. The organization and subscriber group that
+// the subscriber belongs to must already exist
+func New(newSubscriber models.Subscriber, actor audit.Actor) (string, error) {
+	if err := DetailsCheck(&newSubscriber); err != nil {
+		errorMessage := fmt.Sprintf("the new subscriber can not be created, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return "", err
+	}
+
+	if err := ReferencesCheck(newSubscriber.OrganizationID, newSubscriber.SubscriberGroupID); err != nil {
+		errorMessage := fmt.Sprintf("the new subscriber can not be created, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return "", err
+	}
+
+	chapSecret, err := sealCHAPSecret(newSubscriber.Credentials.Password)
+	if err != nil {
+		errorMessage := fmt.Sprintf("the new subscriber can not be created, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return "", err
+	}
+	newSubscriber.Credentials.CHAPSecret = chapSecret
+
+	hashedPassword, err := password.Hash(newSubscriber.Credentials.Password)
+	if err != nil {
+		errorMessage := fmt.Sprintf("the new subscriber can not be created, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return "", err
+	}
+	newSubscriber.Credentials.Password = hashedPassword
+
+	err = cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newSubscriber).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, actor, audit.ActionCreate, EntityType, newSubscriber.ID, nil, snapshot(&newSubscriber))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("the new subscriber can not be created, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return "", err
+	}
+
+	logger.OSPMLogger.Infof("subscriber %s successfully added. id: %s", newSubscriber.Details.Name, newSubscriber.ID)
+
+	return newSubscriber.ID, nil
+}
+
+// Update applies the non-empty fields of the given subscriber on the subscriber identified
+// by the given id. The subscriber can be moved to another group of its own organization
+// but it can not be moved to another organization
+func Update(newSubscriberDetails models.Subscriber, subscriberID string, actor audit.Actor) error {
+	oldSubscriberDetails, err := Detail(subscriberID)
+	if err != nil {
+		return err
+	}
+
+	if newSubscriberDetails.OrganizationID != "" && newSubscriberDetails.OrganizationID != oldSubscriberDetails.OrganizationID {
+		return fmt.Errorf("%w: subscriber can not be moved to another organization", ErrInvalidReference)
+	}
+
+	if newSubscriberDetails.SubscriberGroupID != "" && newSubscriberDetails.SubscriberGroupID != oldSubscriberDetails.SubscriberGroupID {
+		if err := ReferencesCheck(oldSubscriberDetails.OrganizationID, newSubscriberDetails.SubscriberGroupID); err != nil {
+			return err
+		}
+	}
+
+	// the new password should be hashed before being stored
+	if newSubscriberDetails.Credentials.Password != "" {
+		chapSecret, err := sealCHAPSecret(newSubscriberDetails.Credentials.Password)
+		if err != nil {
+			errorMessage := fmt.Sprintf("failed to encrypt the new chap secret of subscriber id %s, error: %+v", subscriberID, err)
+			logger.OSPMLogger.Errorln(errorMessage)
+			return err
+		}
+		newSubscriberDetails.Credentials.CHAPSecret = chapSecret
+
+		hashedPassword, err := password.Hash(newSubscriberDetails.Credentials.Password)
+		if err != nil {
+			errorMessage := fmt.Sprintf("failed to hash the new password of subscriber id %s, error: %+v", subscriberID, err)
+			logger.OSPMLogger.Errorln(errorMessage)
+			return err
+		}
+		newSubscriberDetails.Credentials.Password = hashedPassword
+	}
+
+	updateTX := cockroachdb.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			updateTX.Rollback()
+		}
+	}()
+
+	if newSubscriberDetails.SubscriberGroupID != "" {
+		err = updateTX.Model(&models.Subscriber{}).
+			Where("id = ?", subscriberID).
+			Update("subscriber_group_id", newSubscriberDetails.SubscriberGroupID).Error
+		if err != nil {
+			updateTX.Rollback()
+			errorMessage := fmt.Sprintf("failed to update the given subscriber id %s, error: %+v", subscriberID, err)
+			logger.OSPMLogger.Errorln(errorMessage)
+			return err
+		}
+	}
+
+	// Updates with struct only apply the non-zero fields
+	err = updateTX.Model(&models.SubscriberDetails{}).
+		Where("subscriber_id = ?", subscriberID).
+		Updates(models.SubscriberDetails{
+			Name:       newSubscriberDetails.Details.Name,
+			Email:      newSubscriberDetails.Details.Email,
+			NationalID: newSubscriberDetails.Details.NationalID,
+			PassportID: newSubscriberDetails.Details.PassportID,
+			Mobile:     newSubscriberDetails.Details.Mobile,
+			Phone:      newSubscriberDetails.Details.Phone,
+		}).Error
+	if err != nil {
+		updateTX.Rollback()
+		errorMessage := fmt.Sprintf("failed to update details of the given subscriber id %s, error: %+v", subscriberID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	err = updateTX.Model(&models.Credentials{}).
+		Where("subscriber_id = ?", subscriberID).
+		Updates(models.Credentials{
+			Username:   newSubscriberDetails.Credentials.Username,
+			Password:   newSubscriberDetails.Credentials.Password,
+			CHAPSecret: newSubscriberDetails.Credentials.CHAPSecret,
+		}).Error
+	if err != nil {
+		updateTX.Rollback()
+		errorMessage := fmt.Sprintf("failed to update credentials of the given subscriber id %s, error: %+v", subscriberID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	var updatedSubscriber models.Subscriber
+	err = updateTX.Preload("Details").Preload("Credentials").First(&updatedSubscriber, "id = ?", subscriberID).Error
+	if err == nil {
+		err = audit.Record(updateTX, actor, audit.ActionUpdate, EntityType, subscriberID, snapshot(&oldSubscriberDetails), snapshot(&updatedSubscriber))
+	}
+	if err != nil {
+		updateTX.Rollback()
+		errorMessage := fmt.Sprintf("failed to audit the update of the given subscriber id %s, error: %+v", subscriberID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	if err := updateTX.Commit().Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to update the given subscriber id %s at apply step, error: %+v", subscriberID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	if newSubscriberDetails.SubscriberGroupID != "" {
+		permission.InvalidateSubscriber(subscriberID)
+	}
+	logger.OSPMLogger.Infof("subscriber id %s successfully updated", subscriberID)
+
+	return nil
+}
+
+// SoftDelete deletes the desired subscriber including its details and credentials
+// The delete action happens in soft mode
+func SoftDelete(subscriberID string, actor audit.Actor) error {
+	var subscriber models.Subscriber
+
+	if err := cockroachdb.DB.Preload("Details").Preload("Credentials").First(&subscriber, "id = ?", subscriberID).Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to find subscriber to delete, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("Details", "Credentials").Delete(&subscriber).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, actor, audit.ActionDelete, EntityType, subscriberID, snapshot(&subscriber), nil)
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to delete subscriber and related records, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	permission.InvalidateSubscriber(subscriberID)
+	logger.OSPMLogger.Infof("subscriber id %s successfully deleted in soft mode", subscriberID)
+
+	return nil
+}
+
+// HardDelete deletes the desired subscriber including its details and credentials
+// The delete action happens in hard mode and can not be undone
+func HardDelete(subscriberID string, actor audit.Actor) error {
+	var subscriber models.Subscriber
+
+	if err := cockroachdb.DB.Unscoped().Preload("Details", unscoped).Preload("Credentials", unscoped).First(&subscriber, "id = ?", subscriberID).Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to find subscriber to delete, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Select("Details", "Credentials").Delete(&subscriber).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, actor, audit.ActionHardDelete, EntityType, subscriberID, snapshot(&subscriber), nil)
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to delete subscriber and related records, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	permission.InvalidateSubscriber(subscriberID)
+	logger.OSPMLogger.Infof("subscriber id %s successfully deleted in hard mode", subscriberID)
+
+	return nil
+}
+
+// Recover truncates the deleted_at field of the subscriber and its related records
+// which recovers the subscriber from soft delete
+func Recover(subscriberID string, actor audit.Actor) error {
+	var subscriber models.Subscriber
+
+	if err := cockroachdb.DB.Unscoped().Preload("Details", unscoped).Preload("Credentials", unscoped).First(&subscriber, "id = ?", subscriberID).Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to find subscriber to recover, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	// the organization and the group of the subscriber might have been deleted in the meantime
+	if err := ReferencesCheck(subscriber.OrganizationID, subscriber.SubscriberGroupID); err != nil {
+		return err
+	}
+
+	recoverTX := cockroachdb.DB.Begin()
+
+	if err := recoverTX.Unscoped().Model(&models.Subscriber{}).Where("id = ?", subscriber.ID).Update("deleted_at", nil).Error; err != nil {
+		recoverTX.Rollback()
+		errorMessage := fmt.Sprintf("failed to recover subscriber from soft delete, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	if err := recoverTX.Unscoped().Model(&models.SubscriberDetails{}).Where("subscriber_id = ?", subscriber.ID).Update("deleted_at", nil).Error; err != nil {
+		recoverTX.Rollback()
+		errorMessage := fmt.Sprintf("failed to recover subscriber details from soft delete, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	if err := recoverTX.Unscoped().Model(&models.Credentials{}).Where("subscriber_id = ?", subscriber.ID).Update("deleted_at", nil).Error; err != nil {
+		recoverTX.Rollback()
+		errorMessage := fmt.Sprintf("failed to recover subscriber credentials from soft delete, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	before := snapshot(&subscriber)
+	subscriber.DeletedAt = gorm.DeletedAt{}
+	if err := audit.Record(recoverTX, actor, audit.ActionRecover, EntityType, subscriber.ID, before, snapshot(&subscriber)); err != nil {
+		recoverTX.Rollback()
+		errorMessage := fmt.Sprintf("failed to audit the recovery of subscriber from soft delete, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	if err := recoverTX.Commit().Error; err != nil {
+		errorMessage := fmt.Sprintf("failed to recover subscriber from soft delete, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return err
+	}
+
+	logger.OSPMLogger.Infof("subscriber id %s successfully recovered", subscriberID)
+
+	return nil
+}
+
+// VerifyCredentials looks up the subscriber by the given username and checks the given plain
+// password against the stored hash. If the stored hash was created by outdated parameters, the
+// password gets rehashed by the current ones. ErrInvalidCredentials is returned if the username
+// does not exist or the password does not match
+func VerifyCredentials(username string, plainPassword string) (models.Subscriber, error) {
+	var credentials models.Credentials
+
+	err := cockroachdb.DB.First(&credentials, "username = ?", username).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// hash the given password anyway so the response time does not reveal the existing usernames
+		_, _ = password.Hash(plainPassword)
+		return models.Subscriber{}, ErrInvalidCredentials
+	} else if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the credentials of username %s, error: %+v", username, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.Subscriber{}, err
+	}
+
+	matched, err := password.Verify(plainPassword, credentials.Password)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to verify the password of username %s, error: %+v", username, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.Subscriber{}, ErrInvalidCredentials
+	}
+	if !matched {
+		return models.Subscriber{}, ErrInvalidCredentials
+	}
+
+	if password.NeedsRehash(credentials.Password) {
+		if hashedPassword, err := password.Hash(plainPassword); err == nil {
+			err = cockroachdb.DB.Model(&models.Credentials{}).
+				Where("id = ?", credentials.ID).
+				Update("password", hashedPassword).Error
+			if err != nil {
+				logger.OSPMLogger.Warnf("failed to rehash the password of username %s, error: %+v", username, err)
+			}
+		}
+	}
+
+	return Detail(credentials.SubscriberID)
+}
+
+// MigratePlaintextPasswords hashes the passwords that are still stored in plain text.
+// It is safe to be called on every startup since the already hashed passwords are skipped
+func MigratePlaintextPasswords() error {
+	var legacyCredentials []models.Credentials
+
+	err := cockroachdb.DB.Unscoped().
+		Select("id", "password").
+		Where("password NOT LIKE ?", "$argon2id$%").
+		Find(&legacyCredentials).Error
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the plain text passwords, error: %+v", err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return errors.New(errorMessage)
+	}
+
+	for _, credentials := range legacyCredentials {
+		if password.IsHashed(credentials.Password) {
+			continue
+		}
+
+		hashedPassword, err := password.Hash(credentials.Password)
+		if err != nil {
+			return err
+		}
+
+		chapSecret, err := sealCHAPSecret(credentials.Password)
+		if err != nil {
+			return err
+		}
+
+		// the where clause on the old value avoids overwriting a password which is changed in the meantime
+		err = cockroachdb.DB.Unscoped().Model(&models.Credentials{}).
+			Where("id = ? AND password = ?", credentials.ID, credentials.Password).
+			Updates(map[string]interface{}{"password": hashedPassword, "chap_secret": chapSecret}).Error
+		if err != nil {
+			errorMessage := fmt.Sprintf("failed to hash the plain text password of credential id %s, error: %+v", credentials.ID, err)
+			logger.OSPMLogger.Errorln(errorMessage)
+			return errors.New(errorMessage)
+		}
+	}
+
+	if len(legacyCredentials) > 0 {
+		logger.OSPMLogger.Infof("%d plain text passwords successfully hashed", len(legacyCredentials))
+	}
+
+	return nil
+}
+
+// sealCHAPSecret encrypts the given plain password to be used by RADIUS CHAP authentication.
+// If CHAP is not enabled, an empty value is returned and nothing is stored
+func sealCHAPSecret(plainPassword string) (string, error) {
+	if config.Current().Radius.CHAPSecretKey == "" {
+		return "", nil
+	}
+
+	return password.Encrypt(plainPassword, config.Current().Radius.CHAPSecretKey)
+}
+
+// ReferencesCheck makes sure that the given organization exists and is not soft deleted
+// and the given subscriber group exists within the same organization
+func ReferencesCheck(organizationID string, subscriberGroupID string) error {
+	var organization models.Organization
+	err := cockroachdb.DB.Select("id").First(&organization, "id = ?", organizationID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: organization id %s does not exist", ErrInvalidReference, organizationID)
+	} else if err != nil {
+		return err
+	}
+
+	var group models.SubscriberGroup
+	err = cockroachdb.DB.Select("id").
+		First(&group, "id = ? AND organization_id = ?", subscriberGroupID, organizationID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: subscriber group id %s does not exist in organization id %s",
+			ErrInvalidReference, subscriberGroupID, organizationID)
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DetailsCheck checks the given new subscriber details and validates the given values
+// Since the given values have met the creation policies, the new subscriber can be created
+// by returning nil as error otherwise the error determines what is wrong with the new given information
+func DetailsCheck(subscriberDetails *models.Subscriber) error {
+	var err error
+
+	if subscriberDetails.OrganizationID == "" {
+		err = errors.New("subscriber organization id can not be empty while creating the subscriber")
+	}
+
+	if subscriberDetails.SubscriberGroupID == "" {
+		err = errors.New("subscriber group id can not be empty while creating the subscriber")
+	}
+
+	if subscriberDetails.Details.Name == "" {
+		err = errors.New("subscriber name can not be empty while creating the subscriber")
+	}
+
+	if subscriberDetails.Details.Email == "" {
+		err = errors.New("subscriber email can not be empty while creating the subscriber")
+	}
+
+	if subscriberDetails.Details.Mobile == "" {
+		err = errors.New("subscriber mobile can not be empty while creating the subscriber")
+	}
+
+	if subscriberDetails.Credentials.Username == "" {
+		err = errors.New("subscriber username can not be empty while creating the subscriber")
+	}
+
+	if subscriberDetails.Credentials.Password == "" {
+		err = errors.New("subscriber password can not be empty while creating the subscriber")
+	}
+
+	if err != nil {
+		return fmt.Errorf("new subscriber details are wrong. error: %w", err)
+	}
+	return nil
+}
+
+// Shorten gets a list of subscribers and returns a list of subscribers just including
+// ID, Name and the group ID
+func Shorten(subscribers []models.Subscriber) []models.SubscriberShortInfo {
+	shortList := []models.SubscriberShortInfo{}
+	for _, subscriber := range subscribers {
+		shortList = append(shortList, models.SubscriberShortInfo{
+			ID:                subscriber.ID,
+			Name:              subscriber.Details.Name,
+			SubscriberGroupID: subscriber.SubscriberGroupID,
+		})
+	}
+
+	return shortList
+}
+
+// snapshot is the state of the subscriber that is kept in the audit log.
+// The password and the CHAP secret are never included
+func snapshot(subscriber *models.Subscriber) interface{} {
+	var deletedAt *time.Time
+	if subscriber.DeletedAt.Valid {
+		deletedAt = &subscriber.DeletedAt.Time
+	}
+
+	return struct {
+		models.SubscriberResponse
+		DeletedAt *time.Time `json:"deleted_at"`
+	}{Clean(subscriber), deletedAt}
+}
+
+// unscoped loads the soft deleted associations too
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// Clean can be used to remove database related items and the credentials from the results
+// returned from the DB query like created_at, deleted_at, password and etc.
+func Clean(subscriber *models.Subscriber) models.SubscriberResponse {
+	return models.SubscriberResponse{
+		ID:                subscriber.ID,
+		Username:          subscriber.Credentials.Username,
+		OrganizationID:    subscriber.OrganizationID,
+		SubscriberGroupID: subscriber.SubscriberGroupID,
+		Details: models.SubscriberDetailsResponse{
+			Name:       subscriber.Details.Name,
+			Email:      subscriber.Details.Email,
+			NationalID: subscriber.Details.NationalID,
+			PassportID: subscriber.Details.PassportID,
+			Mobile:     subscriber.Details.Mobile,
+			Phone:      subscriber.Details.Phone,
+		},
+	}
+}
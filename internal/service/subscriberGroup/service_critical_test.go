@@ -0,0 +1,143 @@
+package subscriberGroup
+
+import (
+	"ospm/config"
+	"ospm/internal/models"
+	"ospm/internal/repository/memory"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/logger"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestServiceUpdate(t *testing.T) {
+	config.LoadOSPMConfigs()
+	logger.InitLogger()
+
+	name := "premium"
+	emptyName := ""
+	replacement := []models.PermissionAPI{
+		{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN", PermissionValue: "no"},
+	}
+
+	type testCase struct {
+		name                string
+		request             models.SubscriberGroupUpdateRequest
+		expectedError       error
+		expectedName        string
+		expectedPermissions map[string]string // values of the permissions of the group by their names after the update
+		expectedActions     []string
+	}
+
+	testCases := []testCase{
+		{
+			name: "the name is changed and a permission is added. In this case, both should be saved",
+			request: models.SubscriberGroupUpdateRequest{
+				Name:           &name,
+				AddPermissions: []models.PermissionAPI{{PermissionCategory: "PAYMENT_LEVEL", PermissionName: "CAN_PAY_ONLINE", PermissionValue: "yes"}},
+			},
+			expectedName:        "premium",
+			expectedPermissions: map[string]string{"CAN_LOGIN": "yes", "CAN_VIEW_PAYMENT_HISTORY": "yes", "CAN_PAY_ONLINE": "yes"},
+			expectedActions:     []string{audit.ActionCreate, audit.ActionUpdate},
+		},
+		{
+			name:                "the whole permission set is replaced. In this case, only the given permissions should be kept",
+			request:             models.SubscriberGroupUpdateRequest{Permissions: &replacement},
+			expectedName:        "basic",
+			expectedPermissions: map[string]string{"CAN_LOGIN": "no"},
+			expectedActions:     []string{audit.ActionCreate, audit.ActionUpdate},
+		},
+		{
+			name: "the name is cleared and a permission is removed. In this case, it should be rejected and nothing should be changed",
+			request: models.SubscriberGroupUpdateRequest{
+				Name:              &emptyName,
+				RemovePermissions: []models.PermissionAPI{{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN"}},
+			},
+			expectedError:       ErrInvalidRequest,
+			expectedName:        "basic",
+			expectedPermissions: map[string]string{"CAN_LOGIN": "yes", "CAN_VIEW_PAYMENT_HISTORY": "yes"},
+			expectedActions:     []string{audit.ActionCreate},
+		},
+		{
+			name: "a permission that is not in the catalog is added. In this case, it should be rejected",
+			request: models.SubscriberGroupUpdateRequest{
+				AddPermissions: []models.PermissionAPI{{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_FLY", PermissionValue: "yes"}},
+			},
+			expectedError:       ErrInvalidRequest,
+			expectedName:        "basic",
+			expectedPermissions: map[string]string{"CAN_LOGIN": "yes", "CAN_VIEW_PAYMENT_HISTORY": "yes"},
+			expectedActions:     []string{audit.ActionCreate},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repository := memory.NewSubscriberGroupRepository()
+			service := NewService(repository)
+
+			subscriberGroupID, err := service.New(models.SubscriberGroup{
+				Name:           "basic",
+				OrganizationID: "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a",
+				Permissions: []models.Permission{
+					{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN", PermissionValue: "yes"},
+					{PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_PAYMENT_HISTORY", PermissionValue: "yes"},
+				},
+			}, audit.Actor{})
+			assert.NoError(t, err)
+
+			_, err = service.Update(tc.request, subscriberGroupID, audit.Actor{})
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			subscriberGroup, err := service.Detail(subscriberGroupID)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedName, subscriberGroup.Name)
+
+			permissions := map[string]string{}
+			for _, permission := range subscriberGroup.Permissions {
+				permissions[permission.PermissionName] = permission.PermissionValue
+			}
+			assert.Equal(t, tc.expectedPermissions, permissions)
+
+			actions := []string{}
+			for _, entry := range repository.AuditEntries() {
+				actions = append(actions, entry.Action)
+			}
+			assert.Equal(t, tc.expectedActions, actions)
+		})
+	}
+}
+
+func TestServiceDelete(t *testing.T) {
+	config.LoadOSPMConfigs()
+	logger.InitLogger()
+
+	repository := memory.NewSubscriberGroupRepository()
+	service := NewService(repository)
+	organizationID := "ed83a2ba-c55c-4297-b2ac-df7b02abdd7a"
+
+	basicID, err := service.New(models.SubscriberGroup{Name: "basic", OrganizationID: organizationID}, audit.Actor{})
+	assert.NoError(t, err)
+	_, err = service.New(models.SubscriberGroup{Name: "premium", OrganizationID: organizationID}, audit.Actor{})
+	assert.NoError(t, err)
+
+	// the names of the groups are unique within their organization
+	_, err = service.New(models.SubscriberGroup{Name: "basic", OrganizationID: organizationID}, audit.Actor{})
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+	assert.NoError(t, service.Delete(basicID, audit.Actor{}))
+	assert.ErrorIs(t, service.Delete(basicID, audit.Actor{}), gorm.ErrRecordNotFound)
+
+	groupList, err := service.List(organizationID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(groupList))
+	assert.Equal(t, "premium", groupList[0].Name)
+	assert.Equal(t, 3, len(repository.AuditEntries()))
+}
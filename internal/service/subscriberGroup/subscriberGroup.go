@@ -4,20 +4,40 @@ import (
 	"errors"
 	"fmt"
 	"ospm/internal/models"
-	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/repository"
+	"ospm/internal/service/audit"
 	"ospm/internal/service/logger"
+	"ospm/internal/service/permission"
 )
 
+// EntityType is the type of the subscriber groups in the audit log
+const EntityType = "subscriber_group"
+
+var (
+	// ErrInvalidRequest is returned when the requested changes of the group are not valid
+	ErrInvalidRequest = errors.New("invalid subscriber group request")
+
+	// ErrPermissionExists is returned when the permission to add is already in the group
+	ErrPermissionExists = errors.New("permission already exists in the subscriber group")
+
+	// ErrPermissionNotFound is returned when the permission to modify or remove is not in the group
+	ErrPermissionNotFound = errors.New("permission does not exist in the subscriber group")
+)
+
+// Service manages the subscriber groups kept in its repository
+type Service struct {
+	repository repository.SubscriberGroupRepository
+}
+
+// NewService returns a service that keeps the subscriber groups in the given repository
+func NewService(subscriberGroupRepository repository.SubscriberGroupRepository) *Service {
+	return &Service{repository: subscriberGroupRepository}
+}
+
 // GetSubscriberGroupList get the organization id and returns all groups within the given organiztion
 // In the listed group, soft deleted groups are excluded!
-func List(organizationsID string) ([]models.SubscriberGroupMinimal, error) {
-	var groupList []models.SubscriberGroupMinimal
-
-	err := cockroachdb.DB.
-		Model(&models.SubscriberGroup{}).
-		Select("id,name").
-		Where("organization_id =  ?", organizationsID).
-		Find(&groupList).Error
+func (s *Service) List(organizationsID string) ([]models.SubscriberGroupMinimal, error) {
+	groupList, err := s.repository.List(organizationsID)
 	if err != nil {
 		errorMessage := fmt.Sprintf("failed to load the list of subscriber group for organization id %s, error: %s", organizationsID, err.Error())
 		logger.OSPMLogger.Errorln(errorMessage)
@@ -27,9 +47,8 @@ func List(organizationsID string) ([]models.SubscriberGroupMinimal, error) {
 	return groupList, nil
 }
 
-func Detail(subscriberGroupID string) (models.SubscriberGroup, error) {
-	var subscriberGroupDetail models.SubscriberGroup
-	err := cockroachdb.DB.Preload("Permissions").First(&subscriberGroupDetail, "id = ? ", subscriberGroupID).Error
+func (s *Service) Detail(subscriberGroupID string) (models.SubscriberGroup, error) {
+	subscriberGroupDetail, err := s.repository.Find(subscriberGroupID, false)
 	if err != nil {
 		errorMessage := fmt.Sprintf("failed to load details of given group id %s, error: %+v", subscriberGroupID, err)
 		logger.OSPMLogger.Errorln(errorMessage)
@@ -39,130 +58,267 @@ func Detail(subscriberGroupID string) (models.SubscriberGroup, error) {
 	return subscriberGroupDetail, nil
 }
 
-func Delete(subscriberGroupID string) error {
-	deletetionTX := cockroachdb.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			errorMessage := fmt.Sprintf(
-				"failed to delete the given group id %s, error: %+v",
-				subscriberGroupID, r)
-			logger.OSPMLogger.Errorln(errorMessage)
+func (s *Service) Delete(subscriberGroupID string, actor audit.Actor) error {
+	err := s.repository.Transaction(func(tx repository.SubscriberGroupRepository) error {
+		subscriberGroupDetail, err := tx.Find(subscriberGroupID, true)
+		if err != nil {
+			return fmt.Errorf("load group step, %w", err)
 		}
-	}()
 
-	err := deletetionTX.Unscoped().Where("id = ?", subscriberGroupID).Delete(&models.SubscriberGroup{}).Error
-	if err != nil {
-		errorMessage := fmt.Sprintf(
-			"failed to delete the given group id %s at delete group step, error: %+v",
-			subscriberGroupID, err.Error())
-		logger.OSPMLogger.Errorln(errorMessage)
-		return err
-	}
-
-	err = deletetionTX.Unscoped().Where("subscriber_group_id = ?", subscriberGroupID).Delete(&models.Permission{}).Error
-	if err != nil {
-		errorMessage := fmt.Sprintf(
-			"failed to delete the given group id %s at delete permission set step, error: %+v",
-			subscriberGroupID, err.Error())
-		logger.OSPMLogger.Errorln(errorMessage)
-		return err
-	}
+		// the permission set is deleted with the group
+		if err := tx.Delete(subscriberGroupID); err != nil {
+			return fmt.Errorf("delete group step, %w", err)
+		}
 
-	err = deletetionTX.Commit().Error
+		if err := audit.Append(tx, actor, audit.ActionHardDelete, EntityType, subscriberGroupID, subscriberGroupDetail.BeautifyV2(), nil); err != nil {
+			return fmt.Errorf("audit step, %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		errorMessage := fmt.Sprintf(
-			"failed to delete the given group id %s at apply step, error: %+v",
-			subscriberGroupID, err.Error())
+		errorMessage := fmt.Sprintf("failed to delete the given group id %s at %+v", subscriberGroupID, err)
 		logger.OSPMLogger.Errorln(errorMessage)
 		return err
 	}
 
-	logger.OSPMLogger.Infoln("subscriber group id %s successfully deleted", subscriberGroupID)
+	permission.InvalidateGroup(subscriberGroupID)
+	logger.OSPMLogger.Infof("subscriber group id %s successfully deleted", subscriberGroupID)
 
 	return nil
 }
 
-func New(newSubscriberGroup models.SubscriberGroup) (string, error) {
-	createTX := cockroachdb.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			errorMessage := fmt.Sprintf(
-				"failed to add the new subscriber group  %s at apply step, error: %+v",
-				newSubscriberGroup.Name, r)
-			logger.OSPMLogger.Errorln(errorMessage)
-		}
-	}()
-
-	err := createTX.Create(&newSubscriberGroup).Error
-	if err != nil {
+func (s *Service) New(newSubscriberGroup models.SubscriberGroup, actor audit.Actor) (string, error) {
+	if err := CatalogCheck(newSubscriberGroup.Permissions); err != nil {
 		errorMessage := fmt.Sprintf(
-			"failed to add the new subscriber group  %s at apply step, error: %+v",
+			"failed to add the new subscriber group  %s at check step, error: %+v",
 			newSubscriberGroup.Name, err)
 		logger.OSPMLogger.Errorln(errorMessage)
 		return "-1", err
 	}
 
-	err = createTX.Commit().Error
+	err := s.repository.Transaction(func(tx repository.SubscriberGroupRepository) error {
+		if err := tx.Create(&newSubscriberGroup); err != nil {
+			return err
+		}
+		return audit.Append(tx, actor, audit.ActionCreate, EntityType, newSubscriberGroup.ID, nil, newSubscriberGroup.BeautifyV2())
+	})
 	if err != nil {
 		errorMessage := fmt.Sprintf(
 			"failed to add the new subscriber group  %s at apply step, error: %+v",
 			newSubscriberGroup.Name, err)
 		logger.OSPMLogger.Errorln(errorMessage)
-		createTX.Rollback()
 		return "-1", err
 	}
 
-	logger.OSPMLogger.Infoln("subscriber group %s successfully added. id: %s", newSubscriberGroup.Name, newSubscriberGroup.ID)
+	logger.OSPMLogger.Infof("subscriber group %s successfully added. id: %s", newSubscriberGroup.Name, newSubscriberGroup.ID)
 
 	return newSubscriberGroup.ID, nil
 }
 
-func Update(newSubscriberGroupDetails models.SubscriberGroup, subscriberGroupID string) error {
-	var oldSubscriberGroupDetail models.SubscriberGroup
-	err := cockroachdb.DB.Preload("Permissions").
-		Preload("Permissions.OrganizationLevelPerms").
-		Preload("Permissions.AccessLevelPerms").
-		Preload("Permissions.SubscriberLevelPerms").
-		Preload("Permissions.PaymentLevelPerms").
-		Preload("Permissions.ReportLevelPerms").
-		Preload("Permissions.SubscriberGroupID").First(&oldSubscriberGroupDetail).Where("id = ? ", subscriberGroupID).Error
+// Update applies the given changes on the subscriber group and its permissions within a single
+// transaction and returns the resulting group. Either the whole permission set is replaced or
+// the individual permissions are added, modified and removed
+func (s *Service) Update(request models.SubscriberGroupUpdateRequest, subscriberGroupID string, actor audit.Actor) (models.SubscriberGroup, error) {
+	var subscriberGroupDetail models.SubscriberGroup
+
+	err := s.repository.Transaction(func(tx repository.SubscriberGroupRepository) error {
+		var err error
+		if subscriberGroupDetail, err = tx.Find(subscriberGroupID, true); err != nil {
+			return err
+		}
+
+		before := subscriberGroupDetail.BeautifyV2()
+
+		changes, err := PlanPermissionChanges(subscriberGroupDetail.Permissions, request)
+		if err != nil {
+			return err
+		}
+
+		if err := CatalogCheck(changes.Add); err != nil {
+			return err
+		}
+		if err := CatalogCheck(changes.Modify); err != nil {
+			return err
+		}
+
+		if request.Name != nil {
+			if *request.Name == "" {
+				return fmt.Errorf("%w: the name of the subscriber group can not be empty", ErrInvalidRequest)
+			}
+			subscriberGroupDetail.Name = *request.Name
+		}
+		if request.Description != nil {
+			subscriberGroupDetail.Description = *request.Description
+		}
+		if request.Name != nil || request.Description != nil {
+			if err := tx.UpdateDetails(&subscriberGroupDetail); err != nil {
+				return err
+			}
+		}
+
+		for _, permission := range changes.Remove {
+			if err := tx.RemovePermission(permission.ID); err != nil {
+				return err
+			}
+		}
+
+		for _, permission := range changes.Modify {
+			if err := tx.UpdatePermissionValue(permission.ID, permission.PermissionValue); err != nil {
+				return err
+			}
+		}
+
+		for index := range changes.Add {
+			changes.Add[index].SubscriberGroupID = subscriberGroupID
+		}
+		if err := tx.AddPermissions(changes.Add); err != nil {
+			return err
+		}
+
+		if subscriberGroupDetail, err = tx.Find(subscriberGroupID, false); err != nil {
+			return err
+		}
+
+		return audit.Append(tx, actor, audit.ActionUpdate, EntityType, subscriberGroupID, before, subscriberGroupDetail.BeautifyV2())
+	})
 	if err != nil {
-		errorMessage := fmt.Sprintf(
-			"failed to find the given group id %s to update, error: %+v",
-			subscriberGroupID, err.Error())
+		errorMessage := fmt.Sprintf("failed to update the given group id %s, error: %+v", subscriberGroupID, err)
 		logger.OSPMLogger.Errorln(errorMessage)
-		return err
+		return models.SubscriberGroup{}, err
+	}
+
+	permission.InvalidateGroup(subscriberGroupID)
+	logger.OSPMLogger.Infof("subscriber group %s successfully updated. id: %s", subscriberGroupDetail.Name, subscriberGroupID)
+
+	return subscriberGroupDetail, nil
+}
+
+// PermissionChanges contains the permission rows that should be added, modified and removed
+type PermissionChanges struct {
+	Add    []models.Permission
+	Modify []models.Permission
+	Remove []models.Permission
+}
+
+// PlanPermissionChanges compares the requested changes with the current permissions of the group
+// and returns the rows to add, modify and remove. Replacing the whole set removes the permissions
+// that are not given, modifies the ones with a new value and adds the new ones
+func PlanPermissionChanges(current []models.Permission, request models.SubscriberGroupUpdateRequest) (PermissionChanges, error) {
+	changes := PermissionChanges{}
+
+	if request.Permissions != nil && (len(request.AddPermissions) > 0 || len(request.UpdatePermissions) > 0 || len(request.RemovePermissions) > 0) {
+		return changes, fmt.Errorf("%w: the permission set can not be replaced and changed in the same request", ErrInvalidRequest)
+	}
+
+	existing := map[string]models.Permission{}
+	for _, permission := range current {
+		existing[permissionKey(permission.PermissionCategory, permission.PermissionName)] = permission
+	}
+
+	if request.Permissions != nil {
+		given := map[string]bool{}
+		for _, permission := range *request.Permissions {
+			key, err := requestedPermissionKey(permission, given)
+			if err != nil {
+				return changes, err
+			}
+
+			if old, found := existing[key]; !found {
+				changes.Add = append(changes.Add, newPermission(permission))
+			} else if old.PermissionValue != permission.PermissionValue {
+				old.PermissionValue = permission.PermissionValue
+				changes.Modify = append(changes.Modify, old)
+			}
+		}
+
+		for _, permission := range current {
+			if !given[permissionKey(permission.PermissionCategory, permission.PermissionName)] {
+				changes.Remove = append(changes.Remove, permission)
+			}
+		}
+
+		return changes, nil
 	}
 
-	if oldSubscriberGroupDetail.Name != newSubscriberGroupDetails.Name && newSubscriberGroupDetails.Name != "" {
-		err = cockroachdb.DB.Model(&models.SubscriberGroup{}).
-			Update("subscriber_group_name", newSubscriberGroupDetails.Name).
-			Where("id = ?", subscriberGroupID).Error
+	given := map[string]bool{}
+	for _, permission := range request.RemovePermissions {
+		key, err := requestedPermissionKey(permission, given)
 		if err != nil {
-			errorMessage := fmt.Sprintf(
-				"failed to update the given group id %s, error: %+v",
-				subscriberGroupID, err.Error())
-			logger.OSPMLogger.Errorln(errorMessage)
-			return err
+			return changes, err
 		}
+		old, found := existing[key]
+		if !found {
+			return changes, fmt.Errorf("%w: %s %s", ErrPermissionNotFound, permission.PermissionCategory, permission.PermissionName)
+		}
+		changes.Remove = append(changes.Remove, old)
 	}
 
-	if oldSubscriberGroupDetail.Description != newSubscriberGroupDetails.Description && newSubscriberGroupDetails.Description != "" {
-		err = cockroachdb.DB.Model(&models.SubscriberGroup{}).
-			Update("subscriber_group_description", newSubscriberGroupDetails.Description).
-			Where("id = ?", subscriberGroupID).Error
+	for _, permission := range request.UpdatePermissions {
+		key, err := requestedPermissionKey(permission, given)
 		if err != nil {
-			errorMessage := fmt.Sprintf(
-				"failed to update the given group id %s, error: %+v",
-				subscriberGroupID, err.Error())
-			logger.OSPMLogger.Errorln(errorMessage)
-			return errors.New(errorMessage)
+			return changes, err
+		}
+		old, found := existing[key]
+		if !found {
+			return changes, fmt.Errorf("%w: %s %s", ErrPermissionNotFound, permission.PermissionCategory, permission.PermissionName)
 		}
+		old.PermissionValue = permission.PermissionValue
+		changes.Modify = append(changes.Modify, old)
 	}
 
-	// update perms should be added here
+	for _, permission := range request.AddPermissions {
+		key, err := requestedPermissionKey(permission, given)
+		if err != nil {
+			return changes, err
+		}
+		if _, found := existing[key]; found {
+			return changes, fmt.Errorf("%w: %s %s", ErrPermissionExists, permission.PermissionCategory, permission.PermissionName)
+		}
+		changes.Add = append(changes.Add, newPermission(permission))
+	}
 
-	logger.OSPMLogger.Infoln("subscriber group %s successfully updated. id: %s", newSubscriberGroupDetails.Name, subscriberGroupID)
+	return changes, nil
+}
+
+// CatalogCheck checks the permissions against the permission catalog and replaces
+// their values with the normalized form
+func CatalogCheck(permissions []models.Permission) error {
+	catalog := permission.Current()
+	for index := range permissions {
+		value, err := catalog.Validate(permissions[index].PermissionCategory, permissions[index].PermissionName, permissions[index].PermissionValue)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+		}
+		permissions[index].PermissionValue = value
+	}
 
 	return nil
 }
+
+// requestedPermissionKey validates the requested permission and returns its key.
+// Each permission can only be given once in a request
+func requestedPermissionKey(permission models.PermissionAPI, given map[string]bool) (string, error) {
+	if permission.PermissionCategory == "" || permission.PermissionName == "" {
+		return "", fmt.Errorf("%w: the category and the name of the permissions must be given", ErrInvalidRequest)
+	}
+
+	key := permissionKey(permission.PermissionCategory, permission.PermissionName)
+	if given[key] {
+		return "", fmt.Errorf("%w: permission %s %s is given more than once", ErrInvalidRequest, permission.PermissionCategory, permission.PermissionName)
+	}
+	given[key] = true
+
+	return key, nil
+}
+
+func permissionKey(category string, name string) string {
+	return category + "/" + name
+}
+
+func newPermission(permission models.PermissionAPI) models.Permission {
+	return models.Permission{
+		PermissionName:     permission.PermissionName,
+		PermissionValue:    permission.PermissionValue,
+		PermissionCategory: permission.PermissionCategory,
+	}
+}
@@ -0,0 +1,111 @@
+package subscriberGroup
+
+import (
+	"ospm/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanPermissionChanges(t *testing.T) {
+	current := []models.Permission{
+		{ID: "1", PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_PAYMENT_HISTORY", PermissionValue: "yes"},
+		{ID: "2", PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN", PermissionValue: "yes"},
+	}
+
+	replacement := []models.PermissionAPI{
+		{PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_PAYMENT_HISTORY", PermissionValue: "no"},
+		{PermissionCategory: "PAYMENT_LEVEL", PermissionName: "CAN_PAY_ONLINE", PermissionValue: "yes"},
+	}
+
+	type testCase struct {
+		name            string
+		request         models.SubscriberGroupUpdateRequest
+		expectedChanges PermissionChanges
+		expectedError   error
+	}
+
+	testCases := []testCase{
+		{
+			name:    "the whole permission set is replaced. In this case, the missing ones should be removed, the changed ones modified and the new ones added",
+			request: models.SubscriberGroupUpdateRequest{Permissions: &replacement},
+			expectedChanges: PermissionChanges{
+				Add: []models.Permission{
+					{PermissionCategory: "PAYMENT_LEVEL", PermissionName: "CAN_PAY_ONLINE", PermissionValue: "yes"},
+				},
+				Modify: []models.Permission{
+					{ID: "1", PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_PAYMENT_HISTORY", PermissionValue: "no"},
+				},
+				Remove: []models.Permission{current[1]},
+			},
+		},
+		{
+			name: "individual permissions are added, modified and removed. In this case, only those should be changed",
+			request: models.SubscriberGroupUpdateRequest{
+				AddPermissions:    []models.PermissionAPI{{PermissionCategory: "PAYMENT_LEVEL", PermissionName: "CAN_PAY_ONLINE", PermissionValue: "yes"}},
+				UpdatePermissions: []models.PermissionAPI{{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN", PermissionValue: "no"}},
+				RemovePermissions: []models.PermissionAPI{{PermissionCategory: "REPORT_LEVEL", PermissionName: "CAN_VIEW_PAYMENT_HISTORY"}},
+			},
+			expectedChanges: PermissionChanges{
+				Add: []models.Permission{
+					{PermissionCategory: "PAYMENT_LEVEL", PermissionName: "CAN_PAY_ONLINE", PermissionValue: "yes"},
+				},
+				Modify: []models.Permission{
+					{ID: "2", PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN", PermissionValue: "no"},
+				},
+				Remove: []models.Permission{current[0]},
+			},
+		},
+		{
+			name: "an existing permission is added. In this case, it should be rejected",
+			request: models.SubscriberGroupUpdateRequest{
+				AddPermissions: []models.PermissionAPI{{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN", PermissionValue: "no"}},
+			},
+			expectedError: ErrPermissionExists,
+		},
+		{
+			name: "a missing permission is removed. In this case, it should be rejected",
+			request: models.SubscriberGroupUpdateRequest{
+				RemovePermissions: []models.PermissionAPI{{PermissionCategory: "PAYMENT_LEVEL", PermissionName: "CAN_PAY_ONLINE"}},
+			},
+			expectedError: ErrPermissionNotFound,
+		},
+		{
+			name: "the same permission is modified and removed. In this case, it should be rejected",
+			request: models.SubscriberGroupUpdateRequest{
+				UpdatePermissions: []models.PermissionAPI{{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN", PermissionValue: "no"}},
+				RemovePermissions: []models.PermissionAPI{{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGIN"}},
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			name: "the permission set is replaced and changed at the same time. In this case, it should be rejected",
+			request: models.SubscriberGroupUpdateRequest{
+				Permissions:    &replacement,
+				AddPermissions: []models.PermissionAPI{{PermissionCategory: "ACCESS_LEVEL", PermissionName: "CAN_LOGOUT", PermissionValue: "yes"}},
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			name: "a permission without a category is given. In this case, it should be rejected",
+			request: models.SubscriberGroupUpdateRequest{
+				AddPermissions: []models.PermissionAPI{{PermissionName: "CAN_LOGOUT", PermissionValue: "yes"}},
+			},
+			expectedError: ErrInvalidRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			changes, err := PlanPermissionChanges(current, tc.request)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedChanges, changes)
+		})
+	}
+}
@@ -0,0 +1,457 @@
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/audit"
+	"ospm/internal/service/catalog"
+	"ospm/internal/service/logger"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EntityType is the type of the subscriptions in the audit log
+const EntityType = "subscription"
+
+var (
+	// ErrInvalidReference is returned when the subscriber or the product offering does not exist
+	// or the offering can not be subscribed to
+	ErrInvalidReference = errors.New("invalid subscription reference")
+
+	// ErrInvalidRequest is returned when the given dates of the subscription are not valid
+	ErrInvalidRequest = errors.New("invalid subscription request")
+
+	// ErrInvalidTransition is returned when the action can not be applied on the current status of the subscription
+	ErrInvalidTransition = errors.New("invalid subscription status transition")
+
+	// ErrAlreadySubscribed is returned when the subscriber already has a subscription to the offering in the same period
+	ErrAlreadySubscribed = errors.New("subscriber is already subscribed to the product offering")
+)
+
+// transitions contains the statuses that each action can be applied on
+var transitions = map[string][]string{
+	models.SubscriptionActionSuspend:    {models.SubscriptionActive},
+	models.SubscriptionActionResume:     {models.SubscriptionSuspended},
+	models.SubscriptionActionChangePlan: {models.SubscriptionActive},
+	models.SubscriptionActionTerminate:  {models.SubscriptionPending, models.SubscriptionActive, models.SubscriptionSuspended},
+}
+
+// CanApply determines whether the action can be applied on a subscription with the given status
+func CanApply(action string, status string) bool {
+	for _, allowed := range transitions[action] {
+		if allowed == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// List returns the subscriptions of the subscriber including the terminated ones, the latest first
+func List(subscriberID string) ([]models.SubscriptionResponse, error) {
+	subscriptions := []models.Subscription{}
+
+	err := cockroachdb.DB.Preload("History").
+		Where("subscriber_id = ?", subscriberID).
+		Order("start_date DESC").
+		Find(&subscriptions).Error
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the subscriptions of subscriber id %s, error: %+v", subscriberID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, errors.New(errorMessage)
+	}
+
+	now := time.Now()
+	response := []models.SubscriptionResponse{}
+	for index := range subscriptions {
+		response = append(response, Clean(&subscriptions[index], now))
+	}
+
+	return response, nil
+}
+
+// Entitlements returns the subscriptions of the subscriber which were active at the given time
+func Entitlements(subscriberID string, at time.Time) ([]models.SubscriptionResponse, error) {
+	subscriptions := []models.Subscription{}
+
+	err := cockroachdb.DB.Preload("History").
+		Where("subscriber_id = ? AND start_date <= ?", subscriberID, at).
+		Order("start_date").
+		Find(&subscriptions).Error
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the subscriptions of subscriber id %s, error: %+v", subscriberID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, errors.New(errorMessage)
+	}
+
+	response := []models.SubscriptionResponse{}
+	for index := range subscriptions {
+		if StatusAt(&subscriptions[index], at) == models.SubscriptionActive {
+			response = append(response, Clean(&subscriptions[index], at))
+		}
+	}
+
+	return response, nil
+}
+
+// Detail returns the subscription of the given id including its history
+func Detail(subscriptionID string) (models.SubscriptionResponse, error) {
+	var subscription models.Subscription
+
+	if err := cockroachdb.DB.Preload("History").First(&subscription, "id = ?", subscriptionID).Error; err != nil {
+		return models.SubscriptionResponse{}, err
+	}
+
+	return Clean(&subscription, time.Now()), nil
+}
+
+// Subscribe subscribes the subscriber to the active product offering. The subscription starts
+// now unless a start date is given, it is pending until a start date in the future
+func Subscribe(subscriberID string, request models.SubscriptionRequest, actor audit.Actor) (models.SubscriptionResponse, error) {
+	now := time.Now()
+	subscription := models.Subscription{
+		SubscriberID:      subscriberID,
+		ProductOfferingID: request.ProductOfferingID,
+		StartDate:         now,
+		EndDate:           request.EndDate,
+	}
+	if request.StartDate != nil {
+		subscription.StartDate = *request.StartDate
+	}
+
+	if subscription.EndDate != nil && !subscription.EndDate.After(subscription.StartDate) {
+		return models.SubscriptionResponse{}, fmt.Errorf("%w: the end date (%s) must be after the start date (%s)",
+			ErrInvalidRequest, subscription.EndDate.Format(time.RFC3339), subscription.StartDate.Format(time.RFC3339))
+	}
+
+	event := models.SubscriptionEvent{
+		Action:      models.SubscriptionActionSubscribe,
+		ToStatus:    models.SubscriptionActive,
+		Reason:      request.Reason,
+		EffectiveAt: subscription.StartDate,
+	}
+	if subscription.StartDate.After(now) {
+		event.ToStatus = models.SubscriptionPending
+		event.EffectiveAt = now
+	}
+	subscription.Status = event.ToStatus
+	subscription.History = []models.SubscriptionEvent{event}
+
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Select("id").First(&models.Subscriber{}, "id = ?", subscriberID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: subscriber %s does not exist", ErrInvalidReference, subscriberID)
+		} else if err != nil {
+			return err
+		}
+
+		if err := offeringCheck(tx, subscription.ProductOfferingID, subscription.StartDate); err != nil {
+			return err
+		}
+
+		var overlapCount int64
+		err = tx.Model(&models.Subscription{}).
+			Where("subscriber_id = ? AND product_offering_id = ? AND status <> ?", subscriberID, subscription.ProductOfferingID, models.SubscriptionTerminated).
+			Where("end_date IS NULL OR end_date > ?", subscription.StartDate).
+			Count(&overlapCount).Error
+		if err != nil {
+			return err
+		}
+		if overlapCount > 0 {
+			return fmt.Errorf("%w: %s", ErrAlreadySubscribed, subscription.ProductOfferingID)
+		}
+
+		if err := tx.Create(&subscription).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, actor, audit.ActionCreate, EntityType, subscription.ID, nil, Clean(&subscription, now))
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to subscribe subscriber id %s to product offering id %s, error: %+v",
+			subscriberID, request.ProductOfferingID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.SubscriptionResponse{}, err
+	}
+
+	logger.OSPMLogger.Infof("subscriber id %s is subscribed to product offering id %s with subscription id %s",
+		subscriberID, subscription.ProductOfferingID, subscription.ID)
+	return Clean(&subscription, now), nil
+}
+
+// ChangePlan terminates the active subscription and subscribes the subscriber to the given
+// offering from now on. The new subscription keeps the end date of the replaced one
+func ChangePlan(subscriptionID string, request models.SubscriptionActionRequest, actor audit.Actor) (models.SubscriptionResponse, error) {
+	return apply(subscriptionID, models.SubscriptionActionChangePlan, request, actor)
+}
+
+// Suspend suspends the active subscription until it is resumed
+func Suspend(subscriptionID string, request models.SubscriptionActionRequest, actor audit.Actor) (models.SubscriptionResponse, error) {
+	return apply(subscriptionID, models.SubscriptionActionSuspend, request, actor)
+}
+
+// Resume activates the suspended subscription again
+func Resume(subscriptionID string, request models.SubscriptionActionRequest, actor audit.Actor) (models.SubscriptionResponse, error) {
+	return apply(subscriptionID, models.SubscriptionActionResume, request, actor)
+}
+
+// Terminate ends the subscription now. Terminated subscriptions can not be changed anymore
+func Terminate(subscriptionID string, request models.SubscriptionActionRequest, actor audit.Actor) (models.SubscriptionResponse, error) {
+	return apply(subscriptionID, models.SubscriptionActionTerminate, request, actor)
+}
+
+// StatusAt returns the status of the subscription at the given time according to its history.
+// It is empty if the subscription did not exist at that time
+func StatusAt(subscription *models.Subscription, at time.Time) string {
+	status := ""
+	for _, event := range effectiveHistory(subscription, at) {
+		if !event.EffectiveAt.After(at) {
+			status = event.ToStatus
+		}
+	}
+
+	return status
+}
+
+// Clean removes the database related fields of the subscription. The status and the history
+// are the ones effective at the given time
+func Clean(subscription *models.Subscription, at time.Time) models.SubscriptionResponse {
+	response := models.SubscriptionResponse{
+		ID:                     subscription.ID,
+		SubscriberID:           subscription.SubscriberID,
+		ProductOfferingID:      subscription.ProductOfferingID,
+		Status:                 StatusAt(subscription, at),
+		StartDate:              subscription.StartDate,
+		EndDate:                subscription.EndDate,
+		PreviousSubscriptionID: subscription.PreviousSubscriptionID,
+		History:                []models.SubscriptionEventResponse{},
+	}
+
+	for _, event := range effectiveHistory(subscription, at) {
+		if event.EffectiveAt.After(at) {
+			continue
+		}
+		response.History = append(response.History, models.SubscriptionEventResponse{
+			Action:            event.Action,
+			FromStatus:        event.FromStatus,
+			ToStatus:          event.ToStatus,
+			ProductOfferingID: event.ProductOfferingID,
+			Reason:            event.Reason,
+			EffectiveAt:       event.EffectiveAt,
+		})
+	}
+
+	return response
+}
+
+// apply applies the action on the subscription and records it in the audit log within a single transaction.
+// The subscription is locked, so the concurrent actions on the same subscription are serialized
+func apply(subscriptionID string, action string, request models.SubscriptionActionRequest, actor audit.Actor) (models.SubscriptionResponse, error) {
+	var result models.Subscription
+	now := time.Now()
+
+	err := cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		var subscription models.Subscription
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&subscription, "id = ?", subscriptionID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("subscription_id = ?", subscriptionID).Find(&subscription.History).Error; err != nil {
+			return err
+		}
+		before := Clean(&subscription, now)
+
+		if err := refresh(tx, &subscription, now); err != nil {
+			return err
+		}
+
+		if !CanApply(action, subscription.Status) {
+			return fmt.Errorf("%w: can not %s a %s subscription", ErrInvalidTransition, action, subscription.Status)
+		}
+
+		event := models.SubscriptionEvent{
+			Action:      action,
+			FromStatus:  subscription.Status,
+			Reason:      request.Reason,
+			EffectiveAt: now,
+		}
+		updates := map[string]interface{}{}
+		var next *models.Subscription
+
+		switch action {
+		case models.SubscriptionActionSuspend:
+			event.ToStatus = models.SubscriptionSuspended
+
+		case models.SubscriptionActionResume:
+			event.ToStatus = models.SubscriptionActive
+
+		case models.SubscriptionActionTerminate:
+			event.ToStatus = models.SubscriptionTerminated
+			endDate := now
+			if subscription.StartDate.After(now) {
+				endDate = subscription.StartDate
+			}
+			updates["end_date"] = endDate
+			subscription.EndDate = &endDate
+
+		case models.SubscriptionActionChangePlan:
+			changed, err := changePlan(tx, &subscription, request, now)
+			if err != nil {
+				return err
+			}
+			event.ToStatus = models.SubscriptionTerminated
+			event.ProductOfferingID = changed.ProductOfferingID
+			endDate := now
+			updates["end_date"] = endDate
+			subscription.EndDate = &endDate
+			next = &changed
+		}
+
+		event.SubscriptionID = subscription.ID
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+
+		updates["status"] = event.ToStatus
+		if err := tx.Model(&subscription).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		subscription.Status = event.ToStatus
+		subscription.History = append(subscription.History, event)
+		if err := audit.Record(tx, actor, audit.ActionUpdate, EntityType, subscription.ID, before, Clean(&subscription, now)); err != nil {
+			return err
+		}
+
+		// the plan change returns the new subscription, which is recorded as created
+		if next != nil {
+			result = *next
+			return audit.Record(tx, actor, audit.ActionCreate, EntityType, next.ID, nil, Clean(next, now))
+		}
+
+		result = subscription
+		return nil
+	})
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to %s subscription id %s, error: %+v", action, subscriptionID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.SubscriptionResponse{}, err
+	}
+
+	logger.OSPMLogger.Infof("%s is applied on subscription id %s", action, subscriptionID)
+	return Clean(&result, now), nil
+}
+
+// changePlan creates the subscription that replaces the given one for the offering of the request
+func changePlan(tx *gorm.DB, subscription *models.Subscription, request models.SubscriptionActionRequest, now time.Time) (models.Subscription, error) {
+	if request.ProductOfferingID == "" || request.ProductOfferingID == subscription.ProductOfferingID {
+		return models.Subscription{}, fmt.Errorf("%w: a product offering other than the current one must be given", ErrInvalidRequest)
+	}
+
+	if err := offeringCheck(tx, request.ProductOfferingID, now); err != nil {
+		return models.Subscription{}, err
+	}
+
+	next := models.Subscription{
+		SubscriberID:           subscription.SubscriberID,
+		ProductOfferingID:      request.ProductOfferingID,
+		Status:                 models.SubscriptionActive,
+		StartDate:              now,
+		EndDate:                subscription.EndDate,
+		PreviousSubscriptionID: &subscription.ID,
+		History: []models.SubscriptionEvent{
+			{
+				Action:            models.SubscriptionActionChangePlan,
+				ToStatus:          models.SubscriptionActive,
+				ProductOfferingID: subscription.ProductOfferingID,
+				Reason:            request.Reason,
+				EffectiveAt:       now,
+			},
+		},
+	}
+
+	return next, tx.Create(&next).Error
+}
+
+// refresh stores the changes of the status that are implied by the dates of the subscription,
+// like the activation of a pending subscription when its start date is reached
+func refresh(tx *gorm.DB, subscription *models.Subscription, now time.Time) error {
+	history := effectiveHistory(subscription, now)
+	for _, event := range history[len(subscription.History):] {
+		event.SubscriptionID = subscription.ID
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+		subscription.Status = event.ToStatus
+	}
+	subscription.History = history
+
+	return tx.Model(subscription).Update("status", subscription.Status).Error
+}
+
+// effectiveHistory returns the history of the subscription including the changes of the status
+// that are implied by its dates until the given time but are not stored yet
+func effectiveHistory(subscription *models.Subscription, at time.Time) []models.SubscriptionEvent {
+	history := append([]models.SubscriptionEvent{}, subscription.History...)
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].EffectiveAt.Before(history[j].EffectiveAt)
+	})
+
+	status := ""
+	if len(history) > 0 {
+		status = history[len(history)-1].ToStatus
+	}
+
+	if status == models.SubscriptionPending && !subscription.StartDate.After(at) {
+		history = append(history, models.SubscriptionEvent{
+			Action:      models.SubscriptionActionActivate,
+			FromStatus:  status,
+			ToStatus:    models.SubscriptionActive,
+			Reason:      "start date is reached",
+			EffectiveAt: subscription.StartDate,
+		})
+		status = models.SubscriptionActive
+	}
+
+	if (status == models.SubscriptionActive || status == models.SubscriptionSuspended) &&
+		subscription.EndDate != nil && !subscription.EndDate.After(at) {
+		history = append(history, models.SubscriptionEvent{
+			Action:      models.SubscriptionActionTerminate,
+			FromStatus:  status,
+			ToStatus:    models.SubscriptionTerminated,
+			Reason:      "end date is reached",
+			EffectiveAt: *subscription.EndDate,
+		})
+	}
+
+	return history
+}
+
+// offeringCheck checks that the product offering exists, it is active and it is valid at the given time
+func offeringCheck(tx *gorm.DB, offeringID string, at time.Time) error {
+	if offeringID == "" {
+		return fmt.Errorf("%w: the product offering must be given", ErrInvalidReference)
+	}
+
+	var offering models.ProductOffering
+	err := tx.Select("id", "lifecycle_status", "valid_from", "valid_to").First(&offering, "id = ?", offeringID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: product offering %s does not exist", ErrInvalidReference, offeringID)
+	} else if err != nil {
+		return err
+	}
+
+	if offering.LifecycleStatus != models.LifecycleStatusActive {
+		return fmt.Errorf("%w: product offering %s is %s", ErrInvalidReference, offeringID, offering.LifecycleStatus)
+	}
+
+	if !catalog.IsValidAt(offering.ValidFrom, offering.ValidTo, at) {
+		return fmt.Errorf("%w: product offering %s is not valid at %s", ErrInvalidReference, offeringID, at.Format(time.RFC3339))
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package subscription
+
+import (
+	"ospm/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanApply(t *testing.T) {
+	type testCase struct {
+		name           string
+		action         string
+		status         string
+		expectedResult bool
+	}
+
+	testCases := []testCase{
+		{
+			name:           "an active subscription is suspended. In this case, it should be allowed",
+			action:         models.SubscriptionActionSuspend,
+			status:         models.SubscriptionActive,
+			expectedResult: true,
+		},
+		{
+			name:           "a pending subscription is suspended. In this case, it should be rejected",
+			action:         models.SubscriptionActionSuspend,
+			status:         models.SubscriptionPending,
+			expectedResult: false,
+		},
+		{
+			name:           "a suspended subscription is resumed. In this case, it should be allowed",
+			action:         models.SubscriptionActionResume,
+			status:         models.SubscriptionSuspended,
+			expectedResult: true,
+		},
+		{
+			name:           "the plan of a suspended subscription is changed. In this case, it should be rejected",
+			action:         models.SubscriptionActionChangePlan,
+			status:         models.SubscriptionSuspended,
+			expectedResult: false,
+		},
+		{
+			name:           "a pending subscription is terminated. In this case, it should be allowed",
+			action:         models.SubscriptionActionTerminate,
+			status:         models.SubscriptionPending,
+			expectedResult: true,
+		},
+		{
+			name:           "a terminated subscription is terminated again. In this case, it should be rejected",
+			action:         models.SubscriptionActionTerminate,
+			status:         models.SubscriptionTerminated,
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedResult, CanApply(tc.action, tc.status))
+		})
+	}
+}
+
+func TestStatusAt(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	startDate := createdAt.Add(7 * 24 * time.Hour)
+	endDate := startDate.Add(30 * 24 * time.Hour)
+	suspendedAt := startDate.Add(10 * 24 * time.Hour)
+	resumedAt := suspendedAt.Add(5 * 24 * time.Hour)
+
+	subscription := models.Subscription{
+		Status:    models.SubscriptionActive,
+		StartDate: startDate,
+		EndDate:   &endDate,
+		History: []models.SubscriptionEvent{
+			{Action: models.SubscriptionActionResume, FromStatus: models.SubscriptionSuspended, ToStatus: models.SubscriptionActive, EffectiveAt: resumedAt},
+			{Action: models.SubscriptionActionSubscribe, ToStatus: models.SubscriptionPending, EffectiveAt: createdAt},
+			{Action: models.SubscriptionActionActivate, FromStatus: models.SubscriptionPending, ToStatus: models.SubscriptionActive, EffectiveAt: startDate},
+			{Action: models.SubscriptionActionSuspend, FromStatus: models.SubscriptionActive, ToStatus: models.SubscriptionSuspended, EffectiveAt: suspendedAt},
+		},
+	}
+
+	pendingSubscription := models.Subscription{
+		Status:    models.SubscriptionPending,
+		StartDate: startDate,
+		History: []models.SubscriptionEvent{
+			{Action: models.SubscriptionActionSubscribe, ToStatus: models.SubscriptionPending, EffectiveAt: createdAt},
+		},
+	}
+
+	type testCase struct {
+		name           string
+		subscription   models.Subscription
+		at             time.Time
+		expectedStatus string
+	}
+
+	testCases := []testCase{
+		{
+			name:           "the time is before the subscription is created. In this case, it should have no status",
+			subscription:   subscription,
+			at:             createdAt.Add(-time.Hour),
+			expectedStatus: "",
+		},
+		{
+			name:           "the time is before the start date. In this case, it should be pending",
+			subscription:   subscription,
+			at:             createdAt.Add(time.Hour),
+			expectedStatus: models.SubscriptionPending,
+		},
+		{
+			name:           "the time is while the subscription is suspended. In this case, it should be suspended",
+			subscription:   subscription,
+			at:             suspendedAt.Add(time.Hour),
+			expectedStatus: models.SubscriptionSuspended,
+		},
+		{
+			name:           "the time is after the subscription is resumed. In this case, it should be active",
+			subscription:   subscription,
+			at:             resumedAt,
+			expectedStatus: models.SubscriptionActive,
+		},
+		{
+			name:           "the time is after the end date. In this case, it should be terminated",
+			subscription:   subscription,
+			at:             endDate.Add(time.Hour),
+			expectedStatus: models.SubscriptionTerminated,
+		},
+		{
+			name:           "the start date of a pending subscription is reached but it is not stored yet. In this case, it should be active",
+			subscription:   pendingSubscription,
+			at:             startDate.Add(time.Hour),
+			expectedStatus: models.SubscriptionActive,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedStatus, StatusAt(&tc.subscription, tc.at))
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"ospm/internal/models"
+	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/logger"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnknownSubscriber is returned when the subscriber of an accounting request can not be found
+var ErrUnknownSubscriber = errors.New("the subscriber of the accounting request is unknown")
+
+// Record stores the given accounting request and updates the usage of its session.
+// Retransmitted and out of order requests are tolerated since the counters of a session
+// never decrease and a stopped session is never reactivated
+func Record(request models.AccountingRequest) error {
+	subscriber, err := resolveSubscriber(request)
+	if err != nil {
+		return err
+	}
+
+	return cockroachdb.DB.Transaction(func(tx *gorm.DB) error {
+		var session models.AccountingSession
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("acct_session_id = ? AND nas_identifier = ? AND nas_ip_address = ?",
+				request.AcctSessionID, request.NASIdentifier, request.NASIPAddress).
+			First(&session).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			session = NewSession(request, subscriber)
+		} else if err != nil {
+			return fmt.Errorf("failed to load accounting session %s, error: %w", request.AcctSessionID, err)
+		}
+
+		ApplyRequest(&session, request)
+
+		if err := tx.Save(&session).Error; err != nil {
+			return fmt.Errorf("failed to save accounting session %s, error: %w", request.AcctSessionID, err)
+		}
+
+		record := models.AccountingRecord{
+			AccountingSessionID: session.ID,
+			StatusType:          request.StatusType,
+			EventTime:           request.EventTime,
+			SessionTime:         request.SessionTime,
+			InputOctets:         request.InputOctets,
+			OutputOctets:        request.OutputOctets,
+			InputPackets:        request.InputPackets,
+			OutputPackets:       request.OutputPackets,
+			TerminateCause:      request.TerminateCause,
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return fmt.Errorf("failed to save accounting record of session %s, error: %w", request.AcctSessionID, err)
+		}
+
+		return nil
+	})
+}
+
+// NewSession creates a session for the first accounting request received for it.
+// If the Start request is lost, the start time is estimated by the session time
+func NewSession(request models.AccountingRequest, subscriber models.Subscriber) models.AccountingSession {
+	return models.AccountingSession{
+		AcctSessionID:    request.AcctSessionID,
+		NASIdentifier:    request.NASIdentifier,
+		NASIPAddress:     request.NASIPAddress,
+		NASPortID:        request.NASPortID,
+		SubscriberID:     subscriber.ID,
+		OrganizationID:   subscriber.OrganizationID,
+		Username:         request.Username,
+		FramedIPAddress:  request.FramedIPAddress,
+		CallingStationID: request.CallingStationID,
+		Status:           models.AccountingSessionActive,
+		StartedAt:        request.EventTime.Add(-time.Duration(request.SessionTime) * time.Second),
+		LastUpdatedAt:    request.EventTime,
+	}
+}
+
+// ApplyRequest updates the usage of the given session by the given accounting request
+func ApplyRequest(session *models.AccountingSession, request models.AccountingRequest) {
+	session.SessionTime = max(session.SessionTime, request.SessionTime)
+	session.InputOctets = max(session.InputOctets, request.InputOctets)
+	session.OutputOctets = max(session.OutputOctets, request.OutputOctets)
+	session.InputPackets = max(session.InputPackets, request.InputPackets)
+	session.OutputPackets = max(session.OutputPackets, request.OutputPackets)
+
+	if request.EventTime.After(session.LastUpdatedAt) {
+		session.LastUpdatedAt = request.EventTime
+	}
+
+	if request.FramedIPAddress != "" {
+		session.FramedIPAddress = request.FramedIPAddress
+	}
+
+	if request.StatusType == models.AccountingStatusStop && session.Status != models.AccountingSessionStopped {
+		stoppedAt := request.EventTime
+		session.Status = models.AccountingSessionStopped
+		session.StoppedAt = &stoppedAt
+		session.TerminateCause = request.TerminateCause
+	}
+}
+
+// SubscriberUsage returns the total usage of the sessions of the given subscriber
+// which are started within the given time range
+func SubscriberUsage(subscriberID string, from time.Time, to time.Time) (models.UsageSummary, error) {
+	summary, err := summarize(cockroachdb.DB.Where("subscriber_id = ?", subscriberID), from, to)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to calculate the usage of subscriber id %s, error: %+v", subscriberID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.UsageSummary{}, errors.New(errorMessage)
+	}
+
+	summary.SubscriberID = subscriberID
+	return summary, nil
+}
+
+// OrganizationUsage returns the total usage of the sessions of all subscribers of the given organization
+// which are started within the given time range
+func OrganizationUsage(organizationID string, from time.Time, to time.Time) (models.UsageSummary, error) {
+	summary, err := summarize(cockroachdb.DB.Where("organization_id = ?", organizationID), from, to)
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to calculate the usage of organization id %s, error: %+v", organizationID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return models.UsageSummary{}, errors.New(errorMessage)
+	}
+
+	summary.OrganizationID = organizationID
+	return summary, nil
+}
+
+// SubscriberSessions returns the sessions of the given subscriber which are started within the given time range
+func SubscriberSessions(subscriberID string, from time.Time, to time.Time) ([]models.AccountingSessionInfo, error) {
+	sessions := []models.AccountingSessionInfo{}
+
+	err := cockroachdb.DB.Model(&models.AccountingSession{}).
+		Where("subscriber_id = ? AND started_at >= ? AND started_at < ?", subscriberID, from, to).
+		Order("started_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		errorMessage := fmt.Sprintf("failed to load the sessions of subscriber id %s, error: %+v", subscriberID, err)
+		logger.OSPMLogger.Errorln(errorMessage)
+		return nil, errors.New(errorMessage)
+	}
+
+	return sessions, nil
+}
+
+func summarize(query *gorm.DB, from time.Time, to time.Time) (models.UsageSummary, error) {
+	summary := models.UsageSummary{From: from, To: to}
+
+	err := query.Model(&models.AccountingSession{}).
+		Select(`COUNT(*) AS session_count,
+			COALESCE(SUM(session_time), 0) AS session_time,
+			COALESCE(SUM(input_octets), 0) AS input_octets,
+			COALESCE(SUM(output_octets), 0) AS output_octets,
+			COALESCE(SUM(input_packets), 0) AS input_packets,
+			COALESCE(SUM(output_packets), 0) AS output_packets`).
+		Where("started_at >= ? AND started_at < ?", from, to).
+		Scan(&summary).Error
+
+	return summary, err
+}
+
+// resolveSubscriber finds the subscriber of the accounting request by the subscriber id that is
+// sent back by the NAS in the Class attribute, or by the username. Deleted subscribers are included
+// since the usage of their last sessions should still be recorded
+func resolveSubscriber(request models.AccountingRequest) (models.Subscriber, error) {
+	var subscriber models.Subscriber
+
+	if request.SubscriberID != "" {
+		err := cockroachdb.DB.Unscoped().First(&subscriber, "id = ?", request.SubscriberID).Error
+		if err == nil {
+			return subscriber, nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Subscriber{}, err
+		}
+	}
+
+	var credentials models.Credentials
+	err := cockroachdb.DB.Unscoped().Select("subscriber_id").First(&credentials, "username = ?", request.Username).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Subscriber{}, ErrUnknownSubscriber
+	} else if err != nil {
+		return models.Subscriber{}, err
+	}
+
+	err = cockroachdb.DB.Unscoped().First(&subscriber, "id = ?", credentials.SubscriberID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Subscriber{}, ErrUnknownSubscriber
+	} else if err != nil {
+		return models.Subscriber{}, err
+	}
+
+	return subscriber, nil
+}
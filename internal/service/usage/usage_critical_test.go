@@ -0,0 +1,76 @@
+package usage
+
+import (
+	"ospm/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyRequest(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		name                 string
+		requests             []models.AccountingRequest
+		expectedStatus       string
+		expectedInputOctets  int64
+		expectedSessionTime  int64
+		expectedLastUpdateAt time.Time
+	}
+
+	testCases := []testCase{
+		{
+			name: "start, interim-update and stop are received in order. In this case, the session should be stopped with the last counters",
+			requests: []models.AccountingRequest{
+				{StatusType: models.AccountingStatusStart, EventTime: startedAt},
+				{StatusType: models.AccountingStatusInterimUpdate, EventTime: startedAt.Add(time.Minute), SessionTime: 60, InputOctets: 1000},
+				{StatusType: models.AccountingStatusStop, EventTime: startedAt.Add(2 * time.Minute), SessionTime: 120, InputOctets: 3000},
+			},
+			expectedStatus:       models.AccountingSessionStopped,
+			expectedInputOctets:  3000,
+			expectedSessionTime:  120,
+			expectedLastUpdateAt: startedAt.Add(2 * time.Minute),
+		},
+		{
+			name: "an old interim-update is received after the stop. In this case, the counters should not go backwards",
+			requests: []models.AccountingRequest{
+				{StatusType: models.AccountingStatusStart, EventTime: startedAt},
+				{StatusType: models.AccountingStatusStop, EventTime: startedAt.Add(2 * time.Minute), SessionTime: 120, InputOctets: 3000},
+				{StatusType: models.AccountingStatusInterimUpdate, EventTime: startedAt.Add(time.Minute), SessionTime: 60, InputOctets: 1000},
+			},
+			expectedStatus:       models.AccountingSessionStopped,
+			expectedInputOctets:  3000,
+			expectedSessionTime:  120,
+			expectedLastUpdateAt: startedAt.Add(2 * time.Minute),
+		},
+		{
+			name: "only interim-updates are received. In this case, the session should stay active",
+			requests: []models.AccountingRequest{
+				{StatusType: models.AccountingStatusInterimUpdate, EventTime: startedAt.Add(time.Minute), SessionTime: 60, InputOctets: 1000},
+			},
+			expectedStatus:       models.AccountingSessionActive,
+			expectedInputOctets:  1000,
+			expectedSessionTime:  60,
+			expectedLastUpdateAt: startedAt.Add(time.Minute),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			session := NewSession(tc.requests[0], models.Subscriber{})
+			for _, request := range tc.requests {
+				ApplyRequest(&session, request)
+			}
+
+			assert.Equal(t, tc.expectedStatus, session.Status)
+			assert.Equal(t, tc.expectedInputOctets, session.InputOctets)
+			assert.Equal(t, tc.expectedSessionTime, session.SessionTime)
+			assert.Equal(t, tc.expectedLastUpdateAt, session.LastUpdatedAt)
+			assert.Equal(t, startedAt, session.StartedAt)
+		})
+	}
+}
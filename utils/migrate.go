@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"ospm/config"
+	"ospm/internal/repository/database/cockroachdb"
+	OSPMInternalLogger "ospm/internal/service/logger"
+)
+
+const migrateUsage = `usage: ospm migrate <command> [flags]
+
+commands:
+  up [-steps N]                 applies the pending migrations, all of them unless -steps is given
+  down [-steps N]               rolls back the last applied migrations, one unless -steps is given
+  status                        lists the migrations and whether they are applied
+  create [-dir DIR] <name>      adds the empty up and down files of a new migration
+`
+
+// RunMigrate runs the migrate subcommand with its arguments and returns the exit code
+func RunMigrate(args []string) int {
+	if err := migrate(args, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+func migrate(args []string, output io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(output, migrateUsage)
+		return flag.ErrHelp
+	}
+
+	command, args := args[0], args[1:]
+	flags := flag.NewFlagSet("migrate "+command, flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	switch command {
+	case "up", "down":
+		defaultSteps := 0
+		if command == "down" {
+			defaultSteps = 1
+		}
+		steps := flags.Int("steps", defaultSteps, "number of the migrations")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *steps < 0 || (command == "down" && *steps == 0) {
+			return fmt.Errorf("invalid -steps value: %d", *steps)
+		}
+
+		connect()
+
+		var migrations []cockroachdb.Migration
+		var err error
+		if command == "up" {
+			migrations, err = cockroachdb.MigrateUp(*steps)
+		} else {
+			migrations, err = cockroachdb.MigrateDown(*steps)
+		}
+		for _, migration := range migrations {
+			fmt.Fprintf(output, "%s %04d_%s\n", command, migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(migrations) == 0 {
+			fmt.Fprintln(output, "nothing to migrate")
+		}
+		return nil
+
+	case "status":
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		connect()
+
+		states, err := cockroachdb.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(output, states)
+		return nil
+
+	case "create":
+		directory := flags.String("dir", cockroachdb.MigrationsDirectory, "directory of the migration files")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return fmt.Errorf("the name of the migration is required, e.g. ospm migrate create add_subscriber_notes")
+		}
+
+		upFile, downFile, err := cockroachdb.CreateMigration(*directory, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(output, "created %s\ncreated %s\n", upFile, downFile)
+		return nil
+	}
+
+	fmt.Fprint(output, migrateUsage)
+	return fmt.Errorf("unknown migrate command: %s", command)
+}
+
+// connect loads the configs and connects to the database without migrating it
+func connect() {
+	config.LoadOSPMConfigs()
+	OSPMInternalLogger.InitLogger()
+	cockroachdb.Connect()
+}
+
+func printMigrationStatus(output io.Writer, states []cockroachdb.MigrationState) {
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+
+	for _, state := range states {
+		status, appliedAt := "pending", ""
+		if state.AppliedAt != nil {
+			status, appliedAt = "applied", state.AppliedAt.Format(time.RFC3339)
+		}
+		if state.Modified {
+			status += " (modified)"
+		}
+		if state.Unknown {
+			status += " (unknown)"
+		}
+		fmt.Fprintf(writer, "%04d\t%s\t%s\t%s\n", state.Version, state.Name, status, appliedAt)
+	}
+
+	writer.Flush()
+}
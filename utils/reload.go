@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"ospm/config"
+	"ospm/internal/service/apikey"
+	OSPMInternalLogger "ospm/internal/service/logger"
+	"ospm/internal/service/permission"
+	"ospm/internal/service/policy"
+	"ospm/internal/service/settings"
+)
+
+// reloadCheckInterval determines how often the config files are checked for changes
+const reloadCheckInterval = 2 * time.Second
+
+var reloadLock sync.Mutex
+
+// WatchConfigs reloads the configs when a SIGHUP is received or when the config file,
+// the route policy file or the permission catalog file is changed
+func WatchConfigs() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	ticker := time.NewTicker(reloadCheckInterval)
+	defer ticker.Stop()
+
+	lastState := configFilesState()
+	for {
+		select {
+		case <-signals:
+			ReloadConfigs("SIGHUP is received")
+			lastState = configFilesState()
+		case <-ticker.C:
+			state := configFilesState()
+			if state != lastState {
+				lastState = state
+				ReloadConfigs("config files are changed")
+			}
+		}
+	}
+}
+
+// ReloadConfigs reads the config file, the route policy and the permission catalog again and replaces
+// the current ones only if all of them are valid. They are published together as one settings snapshot,
+// so the requests are either served by the old or the new ones. The current ones are kept if anything fails
+func ReloadConfigs(reason string) error {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+
+	previousConfigs := config.Current()
+	reloadedConfigs, warnings, err := config.Reload()
+	if err != nil {
+		return reloadFailed(reason, err)
+	}
+
+	routePolicy, err := policy.Read(reloadedConfigs.Policy.File)
+	if err != nil {
+		return reloadFailed(reason, err)
+	}
+
+	catalog, err := permission.Read(reloadedConfigs.Permission.CatalogFile)
+	if err != nil {
+		return reloadFailed(reason, err)
+	}
+
+	// the API keys are bootstrapped before the reloaded configs require them, so the API is never locked out
+	if reloadedConfigs.Auth.APIKeyRequired && !previousConfigs.Auth.APIKeyRequired {
+		if err := apikey.Bootstrap(); err != nil {
+			return reloadFailed(reason, fmt.Errorf("failed to bootstrap the API keys, error: %w", err))
+		}
+	}
+
+	settings.Publish(&settings.Snapshot{Config: reloadedConfigs, Policy: routePolicy, Catalog: catalog})
+	OSPMInternalLogger.SetLevel(reloadedConfigs.Logrus.LogLevel)
+
+	for _, warning := range warnings {
+		OSPMInternalLogger.OSPMLogger.Warnf("configs are reloaded but %s", warning)
+	}
+
+	OSPMInternalLogger.OSPMLogger.Infof("configs are reloaded since %s. route policy version: %s, permission catalog version: %s",
+		reason, routePolicy.Version, catalog.Version)
+	return nil
+}
+
+func reloadFailed(reason string, err error) error {
+	errorMessage := fmt.Sprintf("failed to reload the configs since %s, the current configs are kept. error: %+v", reason, err)
+	OSPMInternalLogger.OSPMLogger.Errorln(errorMessage)
+	return err
+}
+
+// configFilesState returns the modification time and the size of the watched files
+// so their changes can be detected by comparing it with the previous state
+func configFilesState() string {
+	paths := []string{config.ConfigFilePath()}
+	if configs := config.Current(); configs != nil {
+		paths = append(paths, configs.Policy.File, configs.Permission.CatalogFile)
+	}
+	sort.Strings(paths)
+
+	var state strings.Builder
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&state, "%s:%d:%d;", path, info.ModTime().UnixNano(), info.Size())
+		} else {
+			fmt.Fprintf(&state, "%s:missing;", path)
+		}
+	}
+
+	return state.String()
+}
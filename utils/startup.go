@@ -7,8 +7,15 @@ import (
 
 	"ospm/config"
 	"ospm/internal/api/routes"
+	"ospm/internal/radius"
 	"ospm/internal/repository/database/cockroachdb"
+	"ospm/internal/service/apikey"
 	OSPMInternalLogger "ospm/internal/service/logger"
+	"ospm/internal/service/permission"
+	"ospm/internal/service/policy"
+	"ospm/internal/service/settings"
+	"ospm/internal/service/subscriber"
+	"ospm/internal/service/usage"
 
 	"sync"
 	"time"
@@ -31,14 +38,51 @@ func StartOSPM() {
 	// init the logger
 	OSPMInternalLogger.InitLogger()
 
-	configs, _ := json.MarshalIndent(config.OSPM, "", "  ")
+	// the route policy is loaded before anything else so an invalid policy fails fast
+	if err := policy.Load(config.Current().Policy.File); err != nil {
+		OSPMInternalLogger.OSPMLogger.Fatal(err)
+	}
+
+	configs, _ := json.MarshalIndent(config.Current(), "", "  ")
 	OSPMInternalLogger.OSPMLogger.Debugf("%+v", string(configs))
 
 	//3.
 	// init the database
 	cockroachdb.InitialDB()
 
+	// the passwords that are stored before hashing was introduced
+	// are hashed here so the plain text values do not stay in the database
+	if err := subscriber.MigratePlaintextPasswords(); err != nil {
+		OSPMInternalLogger.OSPMLogger.Fatal(err)
+	}
+
+	// the first operator API key is created here so the API is not locked out
+	if config.Current().Auth.APIKeyRequired {
+		if err := apikey.Bootstrap(); err != nil {
+			OSPMInternalLogger.OSPMLogger.Fatal(err)
+		}
+	}
+
+	// the permission catalog is needed to validate the permissions of the subscriber groups
+	if err := permission.LoadCatalog(config.Current().Permission.CatalogFile); err != nil {
+		OSPMInternalLogger.OSPMLogger.Fatal(err)
+	}
+
+	// the requests are served by the snapshot of the configs, the route policy and the permission catalog
+	// which is replaced as a whole when they are reloaded on SIGHUP or when their files change
+	settings.Publish(&settings.Snapshot{Config: config.Current(), Policy: policy.Current(), Catalog: permission.Current()})
+
+	// the configs, the route policy and the permission catalog are reloaded on SIGHUP or when their files change
+	go WatchConfigs()
+
 	//4.
+	// starting the radius server if it is enabled
+	if config.Current().Radius.Enabled {
+		OSPMWG.Add(1)
+		go StartRadiusServer(&OSPMWG)
+	}
+
+	//5.
 	// starting the api server
 	OSPMWG.Add(1)
 	StartAPIServer(&OSPMWG)
@@ -53,14 +97,16 @@ func StartAPIServer(wg *sync.WaitGroup) {
 
 	app := fiber.New()
 
+	apiSettings := config.Current().API
+
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: config.OSPM.API.AllowOrigins,
-		AllowMethods: config.OSPM.API.AllowMethods,
-		AllowHeaders: config.OSPM.API.AllowHeaders,
+		AllowOrigins: apiSettings.AllowOrigins,
+		AllowMethods: apiSettings.AllowMethods,
+		AllowHeaders: apiSettings.AllowHeaders,
 	}))
 
 	app.Use(logger.New(logger.Config{
-		Format:       "[${time}] status:${status} - Latency: ${latency} Method: ${method} Path: ${path}\n",
+		Format:       "[${time}] status:${status} - Latency: ${latency} Method: ${method} Path: ${path} Client IP: ${locals:client_ip} Request ID: ${locals:requestid}\n",
 		TimeFormat:   time.RFC3339Nano,
 		TimeZone:     "Local",
 		TimeInterval: 500 * time.Millisecond,
@@ -74,6 +120,32 @@ func StartAPIServer(wg *sync.WaitGroup) {
 
 	routes.Setup(app)
 
-	OSPMInternalLogger.OSPMLogger.Fatal(app.Listen(config.OSPM.API.GetListenAddress()))
+	OSPMInternalLogger.OSPMLogger.Fatal(app.Listen(apiSettings.GetListenAddress()))
+
+}
+
+func StartRadiusServer(wg *sync.WaitGroup) {
+	defer func() {
+		wg.Done()
+	}()
+
+	radiusSettings := config.Current().Radius
+
+	if radiusSettings.SharedSecret == "" {
+		OSPMInternalLogger.OSPMLogger.Fatal("radius server is enabled but OSPM_RADIUS_SHARED_SECRET is not set")
+	}
+
+	radiusServer := radius.NewServer(
+		radiusSettings.SharedSecret,
+		radius.DatabaseAccountStore{CHAPSecretKey: radiusSettings.CHAPSecretKey},
+		radius.AccountingRecorderFunc(usage.Record),
+	)
+
+	go func() {
+		OSPMInternalLogger.OSPMLogger.Infof("radius accounting server is listening on %s", radiusSettings.GetAccountingListenAddress())
+		OSPMInternalLogger.OSPMLogger.Fatal(radiusServer.ListenAndServeAccounting(radiusSettings.GetAccountingListenAddress()))
+	}()
 
+	OSPMInternalLogger.OSPMLogger.Infof("radius authentication server is listening on %s", radiusSettings.GetAuthListenAddress())
+	OSPMInternalLogger.OSPMLogger.Fatal(radiusServer.ListenAndServeAuth(radiusSettings.GetAuthListenAddress()))
 }